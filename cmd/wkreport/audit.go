@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// auditRecord is one line of jira.audit_log. It holds run metadata only, never issue
+// contents.
+type auditRecord struct {
+	Time       string `json:"time"`
+	User       string `json:"user"`
+	FilterID   int    `json:"filterId,omitempty"`
+	FilterName string `json:"filterName"`
+	Issues     int    `json:"issues"`
+}
+
+// writeAudit appends a record of this run to the configured audit log. Failures are
+// reported as warnings so that a broken log never blocks the report itself. -input
+// runs fetch nothing and are not recorded.
+func (r *reportRun) writeAudit(ctx context.Context, issueCount int) {
+	path := r.cfg.Jira.AuditLog
+	if path == "" || r.flags.inputPath != "" {
+		return
+	}
+
+	line, err := json.Marshal(auditRecord{
+		Time:       time.Now().UTC().Format(time.RFC3339),
+		User:       r.auditUser(ctx),
+		FilterID:   r.filter.ID,
+		FilterName: r.filter.Name,
+		Issues:     issueCount,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not encode audit record: %v\n", err)
+		return
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not open audit log: %v\n", err)
+		return
+	}
+	_, err = file.Write(append(line, '\n'))
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not write audit log: %v\n", err)
+	}
+}
+
+// auditUser names who ran the report: the configured email, or for OAuth the account
+// Jira reports for the token. The lookup is done once per run, even under -watch.
+func (r *reportRun) auditUser(ctx context.Context) string {
+	if r.user != "" {
+		return r.user
+	}
+	r.user = r.cfg.Jira.Email
+	if r.user == "" {
+		user, err := r.client.CurrentUser(ctx)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: could not look up the current user for the audit log: %v\n", err)
+			return "unknown"
+		}
+		r.user = user.Email
+		if r.user == "" {
+			r.user = user.AccountID
+		}
+	}
+	return r.user
+}
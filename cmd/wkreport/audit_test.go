@@ -0,0 +1,83 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeAuditConfig returns a test config for url that audits to logPath.
+func writeAuditConfig(t *testing.T, url, logPath string) string {
+	t.Helper()
+	path := writeTestConfig(t, url)
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content = fmt.Appendf(content, "  audit_log: %s\n", logPath)
+	if err := os.WriteFile(path, content, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestAuditLogAppendsRuns(t *testing.T) {
+	fake := newFakeJira(t,
+		fakeIssue{id: "1", key: "ABC-1", status: "Done"},
+		fakeIssue{id: "2", key: "ABC-2", status: "To Do"},
+	)
+	logPath := filepath.Join(t.TempDir(), "audit.log")
+	cfgPath := writeAuditConfig(t, fake.URL, logPath)
+	for range 2 {
+		if _, err := runCapture(t, "-config", cfgPath, "-f", fmt.Sprint(fakeFilterID), "-format", "tsv"); err != nil {
+			t.Fatalf("run: %v", err)
+		}
+	}
+
+	content, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("read audit log: %v", err)
+	}
+	lines := strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("audit log has %d lines, want one per run:\n%s", len(lines), content)
+	}
+	for _, line := range lines {
+		var record auditRecord
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("audit line %q: %v", line, err)
+		}
+		if _, err := time.Parse(time.RFC3339, record.Time); err != nil {
+			t.Errorf("audit time %q: %v", record.Time, err)
+		}
+		record.Time = ""
+		if want := (auditRecord{User: "user@example.com", FilterID: fakeFilterID, FilterName: "Weekly", Issues: 2}); record != want {
+			t.Errorf("audit record = %+v, want %+v", record, want)
+		}
+		if strings.Contains(line, "ABC-1") {
+			t.Errorf("audit line %q includes issue contents", line)
+		}
+	}
+}
+
+func TestAuditLogFailsSoft(t *testing.T) {
+	fake := newFakeJira(t, fakeIssue{id: "1", key: "ABC-1", status: "Done"})
+	cfgPath := writeAuditConfig(t, fake.URL, filepath.Join(t.TempDir(), "missing", "audit.log"))
+	var out string
+	stderr := captureStderr(t, func() {
+		var err error
+		if out, err = runCapture(t, "-config", cfgPath, "-f", fmt.Sprint(fakeFilterID), "-format", "tsv", "-fields", "key"); err != nil {
+			t.Errorf("run: %v", err)
+		}
+	})
+	if out != "KEY\nABC-1\n" {
+		t.Errorf("stdout = %q, want the report", out)
+	}
+	if !strings.Contains(stderr, "Warning: could not open audit log") {
+		t.Errorf("stderr = %q, want an audit log warning", stderr)
+	}
+}
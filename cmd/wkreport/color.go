@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	ansiReset   = "\x1b[0m"
+	ansiRed     = "\x1b[31m"
+	ansiGreen   = "\x1b[32m"
+	ansiYellow  = "\x1b[33m"
+	ansiBlue    = "\x1b[34m"
+	ansiMagenta = "\x1b[35m"
+	ansiCyan    = "\x1b[36m"
+)
+
+// colorCodes maps the color names accepted by output.status_colors to ANSI codes. none
+// prints a status without color, to turn off one of the defaults.
+var colorCodes = map[string]string{
+	"red":     ansiRed,
+	"green":   ansiGreen,
+	"yellow":  ansiYellow,
+	"blue":    ansiBlue,
+	"magenta": ansiMagenta,
+	"cyan":    ansiCyan,
+	"none":    "",
+}
+
+// defaultStatusColors maps lower-cased status names to the color names used unless
+// output.status_colors overrides them.
+var defaultStatusColors = map[string]string{
+	"done":        "green",
+	"closed":      "green",
+	"resolved":    "green",
+	"in progress": "yellow",
+	"in review":   "yellow",
+	"blocked":     "red",
+}
+
+// statusColors maps lower-cased status names to the ANSI color used in the terminal table.
+// Statuses that are not listed are printed without color. A nil map disables color.
+type statusColors map[string]string
+
+// newStatusColors applies the output.status_colors entries, which map statuses
+// (matched case-insensitively) to color names, over defaultStatusColors.
+func newStatusColors(configured map[string]string) (statusColors, error) {
+	names := maps.Clone(defaultStatusColors)
+	for status, name := range configured {
+		names[strings.ToLower(strings.TrimSpace(status))] = strings.ToLower(strings.TrimSpace(name))
+	}
+	colors := make(statusColors, len(names))
+	for status, name := range names {
+		code, ok := colorCodes[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown color %q for status %q (expected %s)", name, status, strings.Join(slices.Sorted(maps.Keys(colorCodes)), ", "))
+		}
+		if code != "" {
+			colors[status] = code
+		}
+	}
+	return colors, nil
+}
+
+// terminalColors returns colors when ANSI color should be written to f, and nil when
+// -no-color is set or useColor declines.
+func terminalColors(colors statusColors, f *os.File, noColor bool) statusColors {
+	if noColor || !useColor(f) {
+		return nil
+	}
+	return colors
+}
+
+// useColor reports whether ANSI color should be written to f. It follows the NO_COLOR
+// (disable when set) and CLICOLOR_FORCE (enable even without a TTY) conventions and
+// otherwise colors only terminal output.
+func useColor(f *os.File) bool {
+	if _, ok := os.LookupEnv("NO_COLOR"); ok {
+		return false
+	}
+	if v := strings.TrimSpace(os.Getenv("CLICOLOR_FORCE")); v != "" && v != "0" {
+		return true
+	}
+	return isTerminal(f)
+}
+
+// formatStatus truncates status to width with the ellipsis marker, pads it to width, and
+// wraps the visible text in its color from colors. Padding is computed on the plain text
+// so columns stay aligned.
+func formatStatus(status string, width int, ellipsis string, colors statusColors) string {
+	code := colors[strings.ToLower(strings.TrimSpace(status))]
+	status = truncate(status, width, ellipsis)
+	padding := ""
+	if n := width - utf8.RuneCountInString(status); n > 0 {
+		padding = strings.Repeat(" ", n)
+	}
+
+	if code == "" {
+		return status + padding
+	}
+	return code + status + ansiReset + padding
+}
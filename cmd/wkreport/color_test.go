@@ -0,0 +1,114 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestFormatStatus(t *testing.T) {
+	colors, err := newStatusColors(map[string]string{"QA": "Cyan", "done": "none"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		status string
+		width  int
+		colors statusColors
+		want   string
+	}{
+		{"Blocked", 10, nil, "Blocked   "},
+		{"Blocked", 10, colors, ansiRed + "Blocked" + ansiReset + "   "},
+		{"qa", 4, colors, ansiCyan + "qa" + ansiReset + "  "},
+		{"Done", 6, colors, "Done  "},
+		{"Waiting for customer", 10, nil, "Waiting..."},
+		{"In Progress", 8, colors, ansiYellow + "In Pr..." + ansiReset},
+	} {
+		if got := formatStatus(tc.status, tc.width, asciiEllipsis, tc.colors); got != tc.want {
+			t.Errorf("formatStatus(%q, %d) = %q, want %q", tc.status, tc.width, got, tc.want)
+		}
+	}
+}
+
+func TestNewStatusColorsRejectsUnknownColor(t *testing.T) {
+	_, err := newStatusColors(map[string]string{"Blocked": "purple"})
+	if err == nil || !strings.Contains(err.Error(), `unknown color "purple" for status "blocked"`) {
+		t.Errorf("newStatusColors error = %v, want the unknown color reported", err)
+	}
+}
+
+func TestPipedTableHasNoEscapes(t *testing.T) {
+	fake := newFakeJira(t,
+		fakeIssue{id: "1", key: "ABC-1", status: "Blocked"},
+		fakeIssue{id: "2", key: "ABC-2", status: "Done"},
+	)
+	cfgPath := writeTestConfig(t, fake.URL)
+	t.Setenv("NO_COLOR", "")
+	os.Unsetenv("NO_COLOR")
+	args := []string{"-config", cfgPath, "-f", fmt.Sprint(fakeFilterID), "-format", "table"}
+
+	t.Setenv("CLICOLOR_FORCE", "")
+	out, err := runCapture(t, args...)
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if !strings.Contains(out, "Blocked") {
+		t.Fatalf("table is missing the statuses:\n%s", out)
+	}
+	if strings.Contains(out, "\x1b[") {
+		t.Errorf("piped table contains escape codes:\n%q", out)
+	}
+
+	// Forcing color shows the check above would notice them.
+	t.Setenv("CLICOLOR_FORCE", "1")
+	if out, _ = runCapture(t, args...); !strings.Contains(out, ansiRed+"Blocked"+ansiReset) {
+		t.Errorf("CLICOLOR_FORCE table is not colored:\n%q", out)
+	}
+}
+
+func TestUseColor(t *testing.T) {
+	// isTerminal only checks for a character device, which /dev/null is.
+	tty, err := os.Open(os.DevNull)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tty.Close()
+	pipe, pipeWriter, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pipe.Close()
+	defer pipeWriter.Close()
+
+	unset := "<unset>"
+	for _, tc := range []struct {
+		name    string
+		noColor string
+		force   string
+		file    *os.File
+		want    bool
+	}{
+		{"terminal", unset, unset, tty, true},
+		{"pipe", unset, unset, pipeWriter, false},
+		{"NO_COLOR on a terminal", "1", unset, tty, false},
+		{"empty NO_COLOR still disables", "", unset, tty, false},
+		{"NO_COLOR beats CLICOLOR_FORCE", "1", "1", pipeWriter, false},
+		{"CLICOLOR_FORCE on a pipe", unset, "1", pipeWriter, true},
+		{"CLICOLOR_FORCE=0 on a pipe", unset, "0", pipeWriter, false},
+		{"empty CLICOLOR_FORCE on a pipe", unset, "", pipeWriter, false},
+		{"CLICOLOR_FORCE=0 on a terminal", unset, "0", tty, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			for name, value := range map[string]string{"NO_COLOR": tc.noColor, "CLICOLOR_FORCE": tc.force} {
+				t.Setenv(name, value)
+				if value == unset {
+					os.Unsetenv(name)
+				}
+			}
+			if got := useColor(tc.file); got != tc.want {
+				t.Errorf("useColor = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
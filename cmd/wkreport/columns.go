@@ -0,0 +1,169 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"wkreport/internal/jira"
+)
+
+// column is one column of the tabular outputs (table, tsv, csv, md, html, docs,
+// confluence, and xlsx).
+type column struct {
+	// name is the column's -fields name; title is its heading in Markdown and
+	// Confluence, upper-cased for the other outputs.
+	name  string
+	title string
+	// field is the Jira field the value is decoded from, empty for the always-present
+	// key. checkColumnFields compares it with the fields the client requests.
+	field string
+	// width is the column's width in the terminal table. fit truncates longer values
+	// to it there; other outputs never truncate except for the summary width.
+	width int
+	fit   bool
+	// value returns the cell text. row is the issue's 1-based position in the rendered
+	// output; summaryWidth limits the summary column, 0 for none.
+	value func(issue jira.Issue, row int, sf summaryFormat, summaryWidth int) string
+}
+
+func (c column) header() string {
+	return strings.ToUpper(c.title)
+}
+
+var columnsByName = map[string]column{
+	"key": {name: "key", title: "Key", width: 12, value: func(issue jira.Issue, _ int, _ summaryFormat, _ int) string {
+		return issue.Key
+	}},
+	"summary": {name: "summary", field: "summary", title: "Summary", width: 150, value: func(issue jira.Issue, _ int, sf summaryFormat, width int) string {
+		return sf.summary(issue, width)
+	}},
+	"status": {name: "status", field: "status", title: "Status", width: 20, value: func(issue jira.Issue, _ int, _ summaryFormat, _ int) string {
+		return issue.Status
+	}},
+	"parent": {name: "parent", field: "parent", title: "Parent", width: 12, fit: true, value: func(issue jira.Issue, _ int, _ summaryFormat, _ int) string {
+		return strings.TrimSpace(issue.Parent)
+	}},
+	"parent-summary": {name: "parent-summary", field: "parent", title: "Parent Summary", width: 40, fit: true, value: func(issue jira.Issue, _ int, _ summaryFormat, _ int) string {
+		return issue.ParentSummary
+	}},
+	"resolved": {name: "resolved", field: "resolutiondate", title: "Resolved", width: 16, value: func(issue jira.Issue, _ int, _ summaryFormat, _ int) string {
+		return issue.Resolved
+	}},
+	"updated": {name: "updated", field: "updated", title: "Updated", width: 16, value: func(issue jira.Issue, _ int, _ summaryFormat, _ int) string {
+		return issue.Updated
+	}},
+	"assignee": {name: "assignee", field: "assignee", title: "Assignee", width: 20, fit: true, value: func(issue jira.Issue, _ int, _ summaryFormat, _ int) string {
+		return issue.Assignee
+	}},
+	"type": {name: "type", field: "issuetype", title: "Type", width: 12, fit: true, value: func(issue jira.Issue, _ int, _ summaryFormat, _ int) string {
+		return issue.Type
+	}},
+	"priority": {name: "priority", field: "priority", title: "Priority", width: 10, fit: true, value: func(issue jira.Issue, _ int, _ summaryFormat, _ int) string {
+		return issue.Priority
+	}},
+	"comments": {name: "comments", field: "comment", title: "Comments", width: 8, value: func(issue jira.Issue, _ int, _ summaryFormat, _ int) string {
+		return strconv.Itoa(issue.CommentCount)
+	}},
+	"description": {name: "description", field: "description", title: "Description", width: 60, fit: true, value: func(issue jira.Issue, _ int, _ summaryFormat, _ int) string {
+		return strings.Join(strings.Fields(issue.Description), " ")
+	}},
+}
+
+// columnNames lists the -fields names in their default order.
+var columnNames = []string{"key", "summary", "status", "parent", "parent-summary", "resolved", "updated", "assignee", "type", "priority", "comments", "description"}
+
+// defaultColumns is the column set when -fields is not given.
+const defaultColumns = "key,summary,status,parent,resolved"
+
+// parseColumns parses a -fields value such as "key,summary,comments". An empty spec
+// selects the default columns, plus comments when withComments is set. The description
+// column is never a default, as it needs a column of its own to be readable.
+func parseColumns(spec string, withComments bool) ([]column, error) {
+	if strings.TrimSpace(spec) == "" {
+		spec = defaultColumns
+		if withComments {
+			spec += ",comments"
+		}
+	}
+
+	columns := make([]column, 0)
+	for _, name := range strings.Split(spec, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		col, ok := columnsByName[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown field %q (expected one of %s)", name, strings.Join(columnNames, ", "))
+		}
+		columns = append(columns, col)
+	}
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("-fields %q selects no columns", spec)
+	}
+	return columns, nil
+}
+
+// withSummaryWidth returns columns with the terminal table's summary column set to
+// width, which pads and truncates it there.
+func withSummaryWidth(columns []column, width int) []column {
+	sized := make([]column, len(columns))
+	for i, col := range columns {
+		if col.name == "summary" {
+			col.width = width
+		}
+		sized[i] = col
+	}
+	return sized
+}
+
+// numberColumn is the "#" column of -numbered, which numbers the rows from 1 in the
+// order they are rendered.
+var numberColumn = column{name: "#", title: "#", width: 4, value: func(_ jira.Issue, row int, _ summaryFormat, _ int) string {
+	return strconv.Itoa(row)
+}}
+
+// fieldGuidance says how to have Jira fields that are not requested by default fetched.
+var fieldGuidance = map[string]string{
+	"comment":     "pass -with-comments, which asks Jira for each issue's comments",
+	"description": "pass -with-description, which asks Jira for each issue's description",
+}
+
+// checkColumnFields rejects columns whose Jira field is not among requested, the fields
+// the client asks Jira for, since they would otherwise print blank.
+func checkColumnFields(columns []column, requested []string) error {
+	have := make(map[string]bool, len(requested))
+	for _, field := range requested {
+		have[field] = true
+	}
+	for _, col := range columns {
+		if col.field == "" || have[col.field] {
+			continue
+		}
+		guidance, ok := fieldGuidance[col.field]
+		if !ok {
+			guidance = fmt.Sprintf("add %s to jira.extra_fields", col.field)
+		}
+		return fmt.Errorf("to show %q you must %s", col.name, guidance)
+	}
+	return nil
+}
+
+// columnHeaders returns the upper-cased headings of columns.
+func columnHeaders(columns []column) []string {
+	headers := make([]string, len(columns))
+	for i, col := range columns {
+		headers[i] = col.header()
+	}
+	return headers
+}
+
+// columnValues returns the cells for columns of issue, the row'th rendered issue.
+func columnValues(issue jira.Issue, row int, columns []column, sf summaryFormat, summaryWidth int) []string {
+	values := make([]string, len(columns))
+	for i, col := range columns {
+		values[i] = col.value(issue, row, sf, summaryWidth)
+	}
+	return values
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"wkreport/internal/jira"
+)
+
+func TestCheckColumnFields(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		fields  string
+		opts    []jira.Option
+		wantErr string
+	}{
+		{"defaults", "", nil, ""},
+		{"every default field", "key,summary,status,parent,parent-summary,resolved,updated,assignee,type,priority", nil, ""},
+		{"comments without -with-comments", "key,comments", nil, `to show "comments" you must pass -with-comments`},
+		{"comments with -with-comments", "key,comments", []jira.Option{jira.WithComments(true)}, ""},
+		{"description without -with-description", "description", nil, `to show "description" you must pass -with-description`},
+		{"description with -with-description", "description", []jira.Option{jira.WithDescription(true)}, ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			client, err := jira.NewClient("https://jira.example.com", "user@example.com", "token", tc.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			columns, err := parseColumns(tc.fields, false)
+			if err != nil {
+				t.Fatalf("parseColumns(%q): %v", tc.fields, err)
+			}
+			err = checkColumnFields(columns, client.IssueFields())
+			switch {
+			case tc.wantErr == "" && err != nil:
+				t.Errorf("checkColumnFields(%q) = %v, want nil", tc.fields, err)
+			case tc.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tc.wantErr)):
+				t.Errorf("checkColumnFields(%q) = %v, want an error containing %q", tc.fields, err, tc.wantErr)
+			}
+		})
+	}
+
+	// A column whose field is missing and has no flag of its own points at extra_fields.
+	err := checkColumnFields([]column{columnsByName["assignee"]}, []string{"summary"})
+	if want := `to show "assignee" you must add assignee to jira.extra_fields`; err == nil || err.Error() != want {
+		t.Errorf("checkColumnFields without assignee = %v, want %q", err, want)
+	}
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"wkreport/internal/config"
+	"wkreport/internal/jira"
+)
+
+const defaultConfigPath = "cfg/config.yaml"
+
+// configTemplate is written by `wkreport init`.
+const configTemplate = `jira:
+  url: https://<JIRA>.atlassian.net/
+  email: <your-email>
+  token: <your-token>
+output:
+  default_mode: table
+`
+
+func printUsage() {
+	fmt.Println(`Usage: wkreport [command] [flags]
+
+Commands:
+  report      Generate a report for a Jira filter (default when no command is given)
+  filters     List the Jira filters available to you
+  open        Open a filter's issues in the Jira web UI
+  init        Write a starter configuration file
+  doctor      Check the configuration, connectivity, and credentials
+  config      Validate the configuration and print it ("config validate")
+  completion  Print a shell completion script (bash, zsh, or fish)
+  help        Show this message
+
+Run "wkreport <command> -h" for the flags of each command.`)
+}
+
+// loadClient loads the configuration at path, selecting profile when it is not empty,
+// and creates a Jira client from it.
+func loadClient(path, profile string, opts ...jira.Option) (*config.Config, *jira.Client, error) {
+	cfg, err := config.LoadProfile(path, profile)
+	if err != nil {
+		return nil, nil, fmt.Errorf("load config: %w", err)
+	}
+
+	client, err := newJiraClient(cfg, opts...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("create jira client: %w", err)
+	}
+	return cfg, client, nil
+}
+
+func runFilters(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("wkreport filters", flag.ContinueOnError)
+	flags.SetOutput(os.Stdout)
+
+	var configPath, profile string
+	var opts listOptions
+	flags.StringVar(&configPath, "config", defaultConfigPath, "Path to configuration file")
+	flags.StringVar(&profile, "profile", "", "Config profile to use (defaults to default_profile)")
+	flags.StringVar(&opts.match, "match", "", "Only list filters whose name contains this text (case-insensitive)")
+	flags.StringVar(&opts.sortBy, "sort", "name", "Sort filters by name or id")
+	flags.BoolVar(&opts.showJQL, "jql", false, "Include each filter's JQL")
+	flags.BoolVar(&opts.favourites, "favourites", false, "Only list your favourite (starred) filters")
+	flags.BoolVar(&opts.details, "details", false, "Include each filter's owner and a * for favourites")
+	flags.IntVar(&opts.limit, "limit", 0, "Show at most this many filters (0 for all)")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if opts.match == "" && flags.NArg() > 0 {
+		opts.match = strings.Join(flags.Args(), " ")
+	}
+
+	_, client, err := loadClient(configPath, profile)
+	if err != nil {
+		return err
+	}
+	return displayFilters(ctx, client, opts)
+}
+
+func runInit(args []string) error {
+	flags := flag.NewFlagSet("wkreport init", flag.ContinueOnError)
+	flags.SetOutput(os.Stdout)
+
+	var configPath string
+	var force bool
+	flags.StringVar(&configPath, "config", defaultConfigPath, "Path of the configuration file to create")
+	flags.BoolVar(&force, "force", false, "Overwrite an existing configuration file")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if _, err := os.Stat(configPath); err == nil && !force {
+		return fmt.Errorf("%s already exists (use -force to overwrite)", configPath)
+	} else if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("check config file: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(configPath), 0o755); err != nil {
+		return fmt.Errorf("create config dir: %w", err)
+	}
+	if err := os.WriteFile(configPath, []byte(configTemplate), 0o600); err != nil {
+		return fmt.Errorf("write config file: %w", err)
+	}
+
+	fmt.Printf("Wrote %s. Fill in your Jira URL, email, and API token.\n", configPath)
+	return nil
+}
+
+func runOpen(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("wkreport open", flag.ContinueOnError)
+	flags.SetOutput(os.Stdout)
+
+	var filterRef string
+	var configPath, profile string
+	flags.StringVar(&filterRef, "f", "", "Jira filter identifier (name or numeric id)")
+	flags.StringVar(&configPath, "config", defaultConfigPath, "Path to configuration file")
+	flags.StringVar(&profile, "profile", "", "Config profile to use (defaults to default_profile)")
+
+	if err := flags.Parse(normalizeFilterFlag(args)); err != nil {
+		return err
+	}
+
+	if strings.TrimSpace(filterRef) == "" && flags.NArg() > 0 {
+		filterRef = flags.Arg(0)
+	}
+	if strings.TrimSpace(filterRef) == stdinFilterRef {
+		var err error
+		if filterRef, err = readFilterRef(os.Stdin); err != nil {
+			return err
+		}
+	}
+	if strings.TrimSpace(filterRef) == "" {
+		return errors.New("filter identifier (-f) is required")
+	}
+
+	_, client, err := loadClient(configPath, profile)
+	if err != nil {
+		return err
+	}
+
+	filter, err := client.ResolveFilter(ctx, strings.TrimSpace(filterRef))
+	if err != nil {
+		return fmt.Errorf("resolve filter %q: %w", filterRef, err)
+	}
+	if strings.TrimSpace(filter.JQL) == "" {
+		return fmt.Errorf("filter %q has no JQL to open", filter.Name)
+	}
+
+	link := client.BrowseJQLURL(filter.JQL)
+	if err := openBrowser(link); err != nil {
+		fmt.Println(link)
+		fmt.Fprintf(os.Stderr, "Warning: unable to open a browser (%v).\n", err)
+		return nil
+	}
+	fmt.Fprintf(os.Stderr, "Opened %s\n", link)
+	return nil
+}
+
+func openBrowser(link string) error {
+	var opener string
+	switch runtime.GOOS {
+	case "darwin":
+		opener = "open"
+	case "linux":
+		opener = "xdg-open"
+	default:
+		return fmt.Errorf("opening a browser is not supported on %s", runtime.GOOS)
+	}
+	path, err := lookTool(opener)
+	if err != nil {
+		return err
+	}
+	return exec.Command(path, link).Run()
+}
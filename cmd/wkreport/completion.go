@@ -0,0 +1,115 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// subcommands lists the first-argument commands offered by shell completion.
+var subcommands = []string{"report", "filters", "open", "init", "doctor", "config", "completion", "help"}
+
+// filterIDsCommand prints the filter ids for dynamic completion of -f.
+const filterIDsCommand = `wkreport filters 2>/dev/null | awk 'NR>1 {print $1}'`
+
+func runCompletion(args []string) error {
+	if len(args) != 1 {
+		return errors.New("usage: wkreport completion bash|zsh|fish")
+	}
+
+	flags := reportFlagNames()
+	var script string
+	switch args[0] {
+	case "bash":
+		script = bashCompletion(flags)
+	case "zsh":
+		script = zshCompletion(flags)
+	case "fish":
+		script = fishCompletion(flags)
+	default:
+		return fmt.Errorf("unsupported shell %q (expected bash, zsh, or fish)", args[0])
+	}
+
+	_, err := fmt.Fprint(os.Stdout, script)
+	return err
+}
+
+// reportFlagNames returns the report command's flags, sorted by name.
+func reportFlagNames() []*flag.Flag {
+	flags := make([]*flag.Flag, 0)
+	newReportFlagSet(&reportFlags{}).VisitAll(func(f *flag.Flag) {
+		if !hiddenReportFlags[f.Name] {
+			flags = append(flags, f)
+		}
+	})
+	sort.Slice(flags, func(i, j int) bool { return flags[i].Name < flags[j].Name })
+	return flags
+}
+
+func dashedNames(flags []*flag.Flag) string {
+	names := make([]string, 0, len(flags))
+	for _, f := range flags {
+		names = append(names, "-"+f.Name)
+	}
+	return strings.Join(names, " ")
+}
+
+func bashCompletion(flags []*flag.Flag) string {
+	return fmt.Sprintf(`# bash completion for wkreport
+_wkreport() {
+  local cur prev
+  cur="${COMP_WORDS[COMP_CWORD]}"
+  prev="${COMP_WORDS[COMP_CWORD-1]}"
+
+  if [[ "$prev" == "-f" ]]; then
+    COMPREPLY=( $(compgen -W "$(%s)" -- "$cur") )
+    return
+  fi
+  if [[ $COMP_CWORD -eq 1 && "$cur" != -* ]]; then
+    COMPREPLY=( $(compgen -W "%s" -- "$cur") )
+    return
+  fi
+  COMPREPLY=( $(compgen -W "%s" -- "$cur") )
+}
+complete -F _wkreport wkreport
+`, filterIDsCommand, strings.Join(subcommands, " "), dashedNames(flags))
+}
+
+func zshCompletion(flags []*flag.Flag) string {
+	return fmt.Sprintf(`#compdef wkreport
+_wkreport() {
+  local -a commands flags
+  commands=(%s)
+  flags=(%s)
+
+  if [[ ${words[CURRENT-1]} == -f ]]; then
+    compadd -- ${(f)"$(%s)"}
+    return
+  fi
+  if (( CURRENT == 2 )) && [[ ${words[CURRENT]} != -* ]]; then
+    compadd -- $commands
+    return
+  fi
+  compadd -- $flags
+}
+compdef _wkreport wkreport
+`, strings.Join(subcommands, " "), dashedNames(flags), filterIDsCommand)
+}
+
+func fishCompletion(flags []*flag.Flag) string {
+	var b strings.Builder
+	b.WriteString("# fish completion for wkreport\n")
+	b.WriteString("complete -c wkreport -f\n")
+	fmt.Fprintf(&b, "complete -c wkreport -n __fish_use_subcommand -a '%s'\n", strings.Join(subcommands, " "))
+	for _, f := range flags {
+		if f.Name == "f" {
+			fmt.Fprintf(&b, "complete -c wkreport -o f -x -a '(%s)' -d %q\n", strings.ReplaceAll(filterIDsCommand, "'", `\'`), f.Usage)
+			continue
+		}
+		fmt.Fprintf(&b, "complete -c wkreport -o %s -d %q\n", f.Name, f.Usage)
+	}
+	return b.String()
+}
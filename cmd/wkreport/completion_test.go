@@ -0,0 +1,49 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCompletionScripts(t *testing.T) {
+	flags := reportFlagNames()
+	for _, shell := range []string{"bash", "zsh", "fish"} {
+		t.Run(shell, func(t *testing.T) {
+			out, err := runCapture(t, "completion", shell)
+			if err != nil {
+				t.Fatalf("completion %s: %v", shell, err)
+			}
+			for _, want := range []string{strings.Join(subcommands, " "), "wkreport filters"} {
+				if !strings.Contains(out, want) {
+					t.Errorf("%s script is missing %q:\n%s", shell, want, out)
+				}
+			}
+			if shell != "fish" && !strings.Contains(out, dashedNames(flags)) {
+				t.Errorf("%s script does not complete the report flags:\n%s", shell, out)
+			}
+			for _, f := range flags {
+				if shell == "fish" && !strings.Contains(out, "complete -c wkreport -o "+f.Name+" ") {
+					t.Errorf("fish script does not complete -%s", f.Name)
+				}
+			}
+			for _, name := range []string{"f", "format", "ls-match"} {
+				want := " -" + name + " "
+				if shell == "fish" {
+					want = " -o " + name + " "
+				}
+				if !strings.Contains(out, want) {
+					t.Errorf("%s script does not complete -%s", shell, name)
+				}
+			}
+			if strings.Contains(out, "dump") {
+				t.Errorf("%s script completes the hidden -dump flag", shell)
+			}
+		})
+	}
+
+	for _, args := range [][]string{{"completion"}, {"completion", "powershell"}, {"completion", "bash", "zsh"}} {
+		if _, err := runCapture(t, args...); err == nil {
+			t.Errorf("%q succeeded, want an error", args)
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"cmp"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"wkreport/internal/config"
+	"wkreport/internal/jira"
+)
+
+// redacted replaces secret values in `config validate` output.
+const redacted = "<redacted>"
+
+// configEntry is one key of the effective configuration.
+type configEntry struct {
+	section string
+	key     string
+	value   string
+}
+
+// runConfig dispatches the config subcommands.
+func runConfig(args []string) error {
+	if len(args) == 0 || args[0] != "validate" {
+		return errors.New("usage: wkreport config validate [-config path] [-profile name]")
+	}
+	return runConfigValidate(args[1:])
+}
+
+// runConfigValidate loads the configuration the way a report would, without contacting
+// Jira, and prints the effective values with secrets redacted and where each came from.
+func runConfigValidate(args []string) error {
+	flags := flag.NewFlagSet("wkreport config validate", flag.ContinueOnError)
+	flags.SetOutput(os.Stdout)
+
+	var configPath, profile string
+	flags.StringVar(&configPath, "config", defaultConfigPath, "Path to configuration file")
+	flags.StringVar(&profile, "profile", "", "Config profile to use (defaults to default_profile)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	cfg, err := config.LoadProfile(configPath, profile)
+	if err == nil {
+		err = checkOutputConfig(cfg)
+	}
+	if err != nil {
+		return fmt.Errorf("%s is not valid: %w", configPath, err)
+	}
+
+	if cfg.Profile != "" {
+		fmt.Printf("%s is valid (profile %s).\n", configPath, cfg.Profile)
+	} else {
+		fmt.Printf("%s is valid.\n", configPath)
+	}
+	section := ""
+	for _, entry := range effectiveConfig(cfg) {
+		if entry.section != section {
+			section = entry.section
+			fmt.Printf("%s:\n", section)
+		}
+		source := cfg.Source(entry.section + "." + entry.key)
+		switch {
+		case entry.value == "":
+			fmt.Printf("  %s: (unset)\n", entry.key)
+		case source == config.SourceDefault:
+			fmt.Printf("  %s: %s (default)\n", entry.key, entry.value)
+		default:
+			fmt.Printf("  %s: %s (from %s)\n", entry.key, entry.value, source)
+		}
+	}
+	return nil
+}
+
+// checkOutputConfig validates the output keys that the report command interprets.
+func checkOutputConfig(cfg *config.Config) error {
+	if mode := cfg.Output.DefaultMode; mode != "" {
+		if _, err := parseFormat(mode); err != nil {
+			return fmt.Errorf("output.default_mode: %w", err)
+		}
+	}
+	if _, err := parseEllipsis(cfg.Output.Ellipsis); err != nil {
+		return fmt.Errorf("output.ellipsis: %w", err)
+	}
+	if err := checkWidths(cfg.Output.Widths); err != nil {
+		return fmt.Errorf("output.widths: %w", err)
+	}
+	if _, err := newStatusColors(cfg.Output.StatusColors); err != nil {
+		return fmt.Errorf("output.status_colors: %w", err)
+	}
+	return nil
+}
+
+// effectiveConfig lists every config key with its loaded value, redacting secrets. The
+// page size and connection pool keys show the client's defaults when unset.
+func effectiveConfig(cfg *config.Config) []configEntry {
+	pageSize := cmp.Or(cfg.Jira.PageSize, jira.MaxPageSize)
+	idleConns := cmp.Or(cfg.Jira.MaxIdleConnsPerHost, jira.DefaultMaxIdleConnsPerHost)
+	maxConns := cmp.Or(cfg.Jira.MaxConnsPerHost, jira.DefaultMaxConnsPerHost)
+	idleTimeout := cmp.Or(cfg.Jira.IdleConnTimeout, jira.DefaultIdleConnTimeout)
+
+	jira := cfg.Jira
+	entries := []configEntry{
+		{"jira", "url", jira.URL},
+		{"jira", "email", jira.Email},
+		{"jira", "api_token", secret(jira.APIToken)},
+		{"jira", "api_token_file", jira.APITokenFile},
+		{"jira", "sprint_field", jira.SprintField},
+		{"jira", "story_points_field", jira.StoryPointsField},
+		{"jira", "browse_url", jira.BrowseURL},
+		{"jira", "page_size", strconv.Itoa(pageSize)},
+		{"jira", "enhanced_search", strconv.FormatBool(jira.EnhancedSearch)},
+		{"jira", "max_issues", strconv.Itoa(jira.MaxIssues)},
+		{"jira", "extra_fields", strings.Join(jira.ExtraFields, ", ")},
+		{"jira", "audit_log", jira.AuditLog},
+		{"jira", "user_agent", jira.UserAgent},
+		{"jira", "atlassian_token_nocheck", strconv.FormatBool(jira.AtlassianTokenNoCheck)},
+		{"jira", "max_idle_conns_per_host", strconv.Itoa(idleConns)},
+		{"jira", "max_conns_per_host", strconv.Itoa(maxConns)},
+		{"jira", "idle_conn_timeout", idleTimeout.String()},
+		{"jira", "auth_type", jira.AuthType},
+	}
+	if jira.AuthType == config.AuthOAuth {
+		entries = append(entries,
+			configEntry{"jira", "access_token", secret(jira.AccessToken)},
+			configEntry{"jira", "refresh_token", secret(jira.RefreshToken)},
+			configEntry{"jira", "client_id", jira.ClientID},
+			configEntry{"jira", "client_secret", secret(jira.ClientSecret)},
+			configEntry{"jira", "token_url", jira.TokenURL},
+		)
+	}
+
+	aliases := make([]string, 0, len(cfg.Output.StatusAliases))
+	for from, to := range cfg.Output.StatusAliases {
+		aliases = append(aliases, fmt.Sprintf("%s -> %s", from, to))
+	}
+	sort.Strings(aliases)
+	colors := make([]string, 0, len(cfg.Output.StatusColors))
+	for status, color := range cfg.Output.StatusColors {
+		colors = append(colors, fmt.Sprintf("%s: %s", status, color))
+	}
+	sort.Strings(colors)
+	widths := make([]string, 0, len(cfg.Output.Widths))
+	for format, width := range cfg.Output.Widths {
+		widths = append(widths, fmt.Sprintf("%s: %d", format, width))
+	}
+	sort.Strings(widths)
+	return append(entries,
+		configEntry{"output", "default_mode", cfg.Output.DefaultMode},
+		configEntry{"output", "ellipsis", cfg.Output.Ellipsis},
+		configEntry{"output", "status_order", strings.Join(cfg.Output.StatusOrder, ", ")},
+		configEntry{"output", "allowed_statuses", strings.Join(cfg.Output.AllowedStatuses, ", ")},
+		configEntry{"output", "status_aliases", strings.Join(aliases, ", ")},
+		configEntry{"output", "status_colors", strings.Join(colors, ", ")},
+		configEntry{"output", "widths", strings.Join(widths, ", ")},
+	)
+}
+
+// secret redacts a set secret value, leaving an unset one empty.
+func secret(value string) string {
+	if value == "" {
+		return ""
+	}
+	return redacted
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"wkreport/internal/jira"
+)
+
+func TestConfigValidateShowsSources(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	content := "jira:\n  url: https://example.atlassian.net\n  email: user@example.com\n  token: secret\noutput:\n  ellipsis: ascii\n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("JIRA_MAX_ISSUES", "50")
+	t.Setenv("JIRA_PAGE_SIZE", "")
+
+	out, err := runCapture(t, "config", "validate", "-config", path)
+	if err != nil {
+		t.Fatalf("config validate: %v", err)
+	}
+	for _, want := range []string{
+		path + " is valid.",
+		"  url: https://example.atlassian.net (from file)",
+		"  api_token: <redacted> (from file)",
+		"  browse_url: (unset)",
+		"  page_size: 100 (default)",
+		"  max_issues: 50 (from JIRA_MAX_ISSUES env)",
+		fmt.Sprintf("  max_conns_per_host: %d (default)", jira.DefaultMaxConnsPerHost),
+		"  ellipsis: ascii (from file)",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output is missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "secret") {
+		t.Errorf("output shows the api token:\n%s", out)
+	}
+}
+
+func TestConfigValidateRejectsBadOutput(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	content := "jira:\n  url: https://example.atlassian.net\n  email: user@example.com\n  token: secret\noutput:\n  default_mode: nonsense\n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	_, err := runCapture(t, "config", "validate", "-config", path)
+	if err == nil || !strings.Contains(err.Error(), "output.default_mode") {
+		t.Errorf("config validate error = %v, want the bad default_mode reported", err)
+	}
+}
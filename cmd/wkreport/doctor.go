@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+
+	"wkreport/internal/config"
+	"wkreport/internal/jira"
+)
+
+// doctorCheck is one line of the doctor checklist.
+type doctorCheck struct {
+	name   string
+	ok     bool
+	detail string
+	hint   string
+}
+
+// runDoctor checks the configuration, connectivity, credentials, and filter access in
+// order, stopping at the first failure since the later checks depend on it.
+func runDoctor(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("wkreport doctor", flag.ContinueOnError)
+	flags.SetOutput(os.Stdout)
+
+	var configPath, profile string
+	flags.StringVar(&configPath, "config", defaultConfigPath, "Path to configuration file")
+	flags.StringVar(&profile, "profile", "", "Config profile to use (defaults to default_profile)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	checks := doctorChecks(ctx, configPath, profile)
+	failed := 0
+	for _, check := range checks {
+		mark := "PASS"
+		if !check.ok {
+			mark = "FAIL"
+			failed++
+		}
+		fmt.Printf("[%s] %s: %s\n", mark, check.name, check.detail)
+		if !check.ok && check.hint != "" {
+			fmt.Printf("       hint: %s\n", check.hint)
+		}
+	}
+
+	if failed > 0 {
+		return errors.New("doctor found a problem; fix it and rerun `wkreport doctor`")
+	}
+	fmt.Println("All checks passed.")
+	return nil
+}
+
+func doctorChecks(ctx context.Context, configPath, profile string) []doctorCheck {
+	var checks []doctorCheck
+
+	cfg, err := config.LoadProfile(configPath, profile)
+	if err != nil {
+		return append(checks, doctorCheck{
+			name:   "Config",
+			detail: err.Error(),
+			hint:   fmt.Sprintf("run `wkreport init -config %s` to create a starter file, or set JIRA_URL/JIRA_EMAIL/JIRA_API_TOKEN", configPath),
+		})
+	}
+	detail := fmt.Sprintf("%s (%s auth)", configPath, cfg.Jira.AuthType)
+	if cfg.Profile != "" {
+		detail = fmt.Sprintf("%s, profile %s (%s auth)", configPath, cfg.Profile, cfg.Jira.AuthType)
+	}
+	checks = append(checks, doctorCheck{name: "Config", ok: true, detail: detail})
+
+	client, err := newJiraClient(cfg)
+	if err != nil {
+		return append(checks, doctorCheck{name: "Config", detail: err.Error(), hint: "check jira.url is a valid https URL"})
+	}
+
+	info, err := client.ServerInfo(ctx)
+	if err != nil && !isAuthError(err) {
+		return append(checks, doctorCheck{
+			name:   "Reachable",
+			detail: err.Error(),
+			hint:   fmt.Sprintf("check that %s is your Jira site URL and that you can reach it from this network", cfg.Jira.URL),
+		})
+	}
+	reach := cfg.Jira.URL
+	if err == nil && info.Version != "" {
+		reach = fmt.Sprintf("%s (%s %s)", cfg.Jira.URL, info.DeploymentType, info.Version)
+	}
+	checks = append(checks, doctorCheck{name: "Reachable", ok: true, detail: reach})
+
+	user, err := client.CurrentUser(ctx)
+	if err != nil {
+		return append(checks, doctorCheck{name: "Authentication", detail: err.Error(), hint: authHint(cfg, err)})
+	}
+	account := user.DisplayName
+	if user.Email != "" {
+		account = fmt.Sprintf("%s <%s>", user.DisplayName, user.Email)
+	}
+	checks = append(checks, doctorCheck{name: "Authentication", ok: true, detail: fmt.Sprintf("signed in as %s (account %s)", account, user.AccountID)})
+
+	filters, err := client.ListFilters(ctx)
+	switch {
+	case err != nil:
+		hint := authHint(cfg, err)
+		if hint == "" {
+			hint = "check that the account can browse filters in Jira"
+		}
+		return append(checks, doctorCheck{name: "Filters", detail: err.Error(), hint: hint})
+	case len(filters) == 0:
+		return append(checks, doctorCheck{
+			name:   "Filters",
+			detail: "no filters are visible to this account",
+			hint:   "create or favourite a filter in Jira, or ask for it to be shared with you",
+		})
+	}
+	return append(checks, doctorCheck{name: "Filters", ok: true, detail: fmt.Sprintf("%d filter(s) listable", len(filters))})
+}
+
+func isAuthError(err error) bool {
+	var apiErr *jira.APIError
+	return errors.As(err, &apiErr) && (apiErr.StatusCode == http.StatusUnauthorized || apiErr.StatusCode == http.StatusForbidden)
+}
+
+func authHint(cfg *config.Config, err error) string {
+	if !isAuthError(err) {
+		return ""
+	}
+	var apiErr *jira.APIError
+	if errors.As(err, &apiErr) && apiErr.MissingScope != "" {
+		return fmt.Sprintf("grant the %s scope to the token or OAuth app; wkreport only needs read access", apiErr.MissingScope)
+	}
+	if cfg.Jira.AuthType == config.AuthOAuth {
+		return "the OAuth access token was rejected; check jira.refresh_token, client_id, and client_secret"
+	}
+	return "check jira.email and the API token; tokens are created at https://id.atlassian.com/manage-profile/security/api-tokens"
+}
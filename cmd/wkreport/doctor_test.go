@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// doctorServer answers the doctor's requests, replacing the response of any path in
+// fail with a 401 and listing filters only when hasFilters is set.
+func doctorServer(t *testing.T, hasFilters bool, fail ...string) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for _, path := range fail {
+			if r.URL.Path == path {
+				http.Error(w, `{"errorMessages":["Unauthorized"]}`, http.StatusUnauthorized)
+				return
+			}
+		}
+		switch r.URL.Path {
+		case "/rest/api/3/serverInfo":
+			writeTestJSON(w, map[string]string{"version": "1001.0.0", "deploymentType": "Cloud"})
+		case "/rest/api/3/myself":
+			writeTestJSON(w, map[string]string{"accountId": "abc", "displayName": "Pat Lee", "emailAddress": "pat@example.com"})
+		case "/rest/api/3/filter/search":
+			values := []map[string]string{}
+			if hasFilters {
+				values = append(values, map[string]string{"id": "100", "name": "Weekly"})
+			}
+			writeTestJSON(w, map[string]any{"values": values, "isLast": true})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestDoctor(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		hasFilters bool
+		fail       []string
+		want       []string
+		wantErr    bool
+	}{
+		{
+			name:       "healthy",
+			hasFilters: true,
+			want: []string{
+				"[PASS] Reachable: ",
+				"(Cloud 1001.0.0)",
+				"[PASS] Authentication: signed in as Pat Lee <pat@example.com> (account abc)",
+				"[PASS] Filters: 1 filter(s) listable",
+				"All checks passed.",
+			},
+		},
+		{
+			name:       "rejected credentials",
+			hasFilters: true,
+			fail:       []string{"/rest/api/3/serverInfo", "/rest/api/3/myself"},
+			want: []string{
+				"[PASS] Reachable: ",
+				"[FAIL] Authentication: ",
+				"hint: check jira.email and the API token",
+			},
+			wantErr: true,
+		},
+		{
+			name:    "no filters",
+			want:    []string{"[PASS] Authentication: ", "[FAIL] Filters: no filters are visible to this account"},
+			wantErr: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := doctorServer(t, tc.hasFilters, tc.fail...)
+			out, err := runCapture(t, "doctor", "-config", writeTestConfig(t, srv.URL))
+			if (err != nil) != tc.wantErr {
+				t.Errorf("doctor error = %v, want error %v", err, tc.wantErr)
+			}
+			for _, want := range tc.want {
+				if !strings.Contains(out, want) {
+					t.Errorf("output is missing %q:\n%s", want, out)
+				}
+			}
+			if tc.wantErr && strings.Contains(out, "All checks passed.") {
+				t.Errorf("failing doctor reported success:\n%s", out)
+			}
+		})
+	}
+}
+
+func TestDoctorStopsAtConfigErrors(t *testing.T) {
+	checks := doctorChecks(context.Background(), filepath.Join(t.TempDir(), "missing.yaml"), "")
+	if len(checks) != 1 || checks[0].ok || checks[0].name != "Config" || !strings.Contains(checks[0].hint, "wkreport init") {
+		t.Errorf("checks = %+v, want one failed Config check suggesting init", checks)
+	}
+}
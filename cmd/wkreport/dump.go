@@ -0,0 +1,96 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"wkreport/internal/jira"
+)
+
+// hiddenReportFlags are developer aids left out of -h output and shell completion.
+var hiddenReportFlags = map[string]bool{
+	"dump": true,
+}
+
+// printVisibleDefaults is the report flag set's usage, listing every flag that is not
+// hidden.
+func printVisibleDefaults(flags *flag.FlagSet) {
+	visible := flag.NewFlagSet(flags.Name(), flag.ContinueOnError)
+	visible.SetOutput(flags.Output())
+	flags.VisitAll(func(f *flag.Flag) {
+		if hiddenReportFlags[f.Name] {
+			return
+		}
+		visible.Var(f.Value, f.Name, f.Usage)
+		visible.Lookup(f.Name).DefValue = f.DefValue
+	})
+	fmt.Fprintf(flags.Output(), "Usage of %s:\n", flags.Name())
+	visible.PrintDefaults()
+}
+
+// rawIssues collects the search response issues for -raw.
+type rawIssues struct {
+	issues []json.RawMessage
+}
+
+func (r *rawIssues) add(issue json.RawMessage) {
+	r.issues = append(r.issues, issue)
+}
+
+// write prints the collected issues as one JSON array, each issue as Jira sent it.
+func (r *rawIssues) write(out io.Writer) error {
+	var b bytes.Buffer
+	b.WriteString("[")
+	for i, issue := range r.issues {
+		if i > 0 {
+			b.WriteString(",")
+		}
+		b.WriteString("\n  ")
+		b.Write(issue)
+	}
+	if len(r.issues) > 0 {
+		b.WriteString("\n")
+	}
+	b.WriteString("]\n")
+	_, err := b.WriteTo(out)
+	return err
+}
+
+// checkRawFlags rejects -raw combinations that expect decoded issues.
+func checkRawFlags(rf *reportFlags, format reportFormat) error {
+	switch {
+	case format != "":
+		return errors.New("-raw prints Jira's own JSON and cannot be combined with -format or a mode flag")
+	case rf.stream || rf.watch != 0:
+		return errors.New("-raw cannot be combined with -stream or -watch")
+	case rf.outputPath != "" || rf.xlsxPath != "" || rf.templateFlag != "" || rf.sortSpec != "":
+		return errors.New("-raw cannot be combined with -o, -xlsx, -template, or -sort")
+	case rf.withComments || rf.withDesc || rf.fieldsSpec != "":
+		return errors.New("-raw cannot be combined with -with-comments, -with-description, or -fields")
+	case rf.resolveParent || rf.savePath != "" || rf.resume:
+		return errors.New("-raw cannot be combined with -resolve-parents, -save, or -resume")
+	}
+	return nil
+}
+
+// dumpIssue prints the raw JSON Jira returns for key with every field, to help find
+// custom field ids.
+func dumpIssue(ctx context.Context, client *jira.Client, key string) error {
+	raw, err := client.RawIssue(ctx, key)
+	if err != nil {
+		return fmt.Errorf("dump issue %s: %w", key, err)
+	}
+	var pretty bytes.Buffer
+	if err := json.Indent(&pretty, raw, "", "  "); err != nil {
+		return fmt.Errorf("format issue %s: %w", key, err)
+	}
+	pretty.WriteByte('\n')
+	_, err = pretty.WriteTo(os.Stdout)
+	return err
+}
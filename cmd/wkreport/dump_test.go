@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDumpPrintsRawIssue(t *testing.T) {
+	var fields string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/api/3/issue/ABC-1" {
+			http.NotFound(w, r)
+			return
+		}
+		fields = r.URL.Query().Get("fields")
+		io.WriteString(w, `{"id":"1","key":"ABC-1","fields":{"summary":"Raw","customfield_10016":5,"customfield_10020":null}}`)
+	}))
+	t.Cleanup(srv.Close)
+
+	out, err := runCapture(t, "-config", writeTestConfig(t, srv.URL), "-dump", " ABC-1 ")
+	if err != nil {
+		t.Fatalf("-dump: %v", err)
+	}
+	if fields != "*all" {
+		t.Errorf("fields = %q, want *all", fields)
+	}
+	want := `{
+  "id": "1",
+  "key": "ABC-1",
+  "fields": {
+    "summary": "Raw",
+    "customfield_10016": 5,
+    "customfield_10020": null
+  }
+}
+`
+	if out != want {
+		t.Errorf("-dump printed:\n%s\nwant\n%s", out, want)
+	}
+}
+
+func TestRawPassesSearchIssuesThrough(t *testing.T) {
+	fake := newFakeJira(t,
+		fakeIssue{id: "1", key: "ABC-1", status: "Done"},
+		fakeIssue{id: "2", key: "ABC-2", status: "To Do"},
+	)
+	out, err := runCapture(t, "-config", writeTestConfig(t, fake.URL), "-f", fmt.Sprint(fakeFilterID), "-raw")
+	if err != nil {
+		t.Fatalf("-raw: %v", err)
+	}
+	// fakeJira's search issues are bare {"id": ...} objects.
+	if want := "[\n  {\"id\":\"1\"},\n  {\"id\":\"2\"}\n]\n"; out != want {
+		t.Errorf("-raw printed:\n%s\nwant\n%s", out, want)
+	}
+	if got := fake.detailRequests(); len(got) != 0 {
+		t.Errorf("-raw fetched issue details %v, want none", got)
+	}
+
+	var empty strings.Builder
+	if err := (&rawIssues{}).write(&empty); err != nil || empty.String() != "[]\n" {
+		t.Errorf("empty -raw output = %q, %v; want []", empty.String(), err)
+	}
+}
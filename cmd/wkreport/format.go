@@ -0,0 +1,362 @@
+package main
+
+import (
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"wkreport/internal/jira"
+)
+
+// reportFormat names an output mode selectable with -format.
+type reportFormat string
+
+const (
+	formatTable      reportFormat = "table"
+	formatTSV        reportFormat = "tsv"
+	formatCSV        reportFormat = "csv"
+	formatJSON       reportFormat = "json"
+	formatJSONL      reportFormat = "jsonl"
+	formatMarkdown   reportFormat = "md"
+	formatDocs       reportFormat = "docs"
+	formatSlides     reportFormat = "slides"
+	formatHTML       reportFormat = "html"
+	formatConfluence reportFormat = "confluence"
+	formatSlack      reportFormat = "slack"
+	formatBrief      reportFormat = "brief"
+	formatDigest     reportFormat = "digest"
+	formatSheets     reportFormat = "sheets"
+	formatPorcelain  reportFormat = "porcelain"
+)
+
+// reportFormats lists the accepted -format values in help order.
+var reportFormats = []reportFormat{
+	formatTable, formatTSV, formatCSV, formatJSON, formatJSONL, formatMarkdown,
+	formatDocs, formatSlides, formatHTML, formatConfluence, formatSlack, formatBrief,
+	formatDigest, formatSheets, formatPorcelain,
+}
+
+// legacyFormatFlags maps the deprecated boolean mode flags onto their -format value.
+var legacyFormatFlags = map[string]reportFormat{
+	"tabs":   formatTSV,
+	"docs":   formatDocs,
+	"slides": formatSlides,
+	"slack":  formatSlack,
+	"json":   formatJSON,
+	"jsonl":  formatJSONL,
+}
+
+// shorthandFormatFlags are boolean mode flags kept as supported shortcuts for -format.
+var shorthandFormatFlags = map[string]reportFormat{
+	"brief":     formatBrief,
+	"digest":    formatDigest,
+	"sheets":    formatSheets,
+	"porcelain": formatPorcelain,
+}
+
+// defaultSummaryWidth truncates summaries in the formats with no width of their own.
+const defaultSummaryWidth = 150
+
+// unsizedFormats are the formats that print whole summaries, which output.widths and
+// -width do not apply to.
+var unsizedFormats = map[reportFormat]bool{
+	formatJSON:      true,
+	formatJSONL:     true,
+	formatDigest:    true,
+	formatPorcelain: true,
+}
+
+// resolveSummaryWidth returns the summary truncation width of format: -width when it
+// was given, then -bullet-width for slides, then output.widths, then the format's
+// default. csv does not truncate by default, and slides default to -bullet-width.
+func resolveSummaryWidth(format reportFormat, widths map[string]int, rf *reportFlags, flags *flag.FlagSet) int {
+	if format == "" {
+		format = formatTable
+	}
+	switch {
+	case flagPassed(flags, "width"):
+		return rf.width
+	case format == formatSlides && flagPassed(flags, "bullet-width"):
+		return rf.bulletWidth
+	}
+	for name, width := range widths {
+		if configured, err := parseFormat(name); err == nil && configured == format {
+			return width
+		}
+	}
+	switch format {
+	case formatSlides:
+		return rf.bulletWidth
+	case formatCSV:
+		return 0
+	}
+	return defaultSummaryWidth
+}
+
+// checkWidths validates the output.widths formats and widths.
+func checkWidths(widths map[string]int) error {
+	for name, width := range widths {
+		format, err := parseFormat(name)
+		if err != nil {
+			return err
+		}
+		if unsizedFormats[format] {
+			return fmt.Errorf("%s prints whole summaries and takes no width", name)
+		}
+		if width > 0 && width < 4 {
+			return fmt.Errorf("%s must be 0 (no limit) or at least 4, got %d", name, width)
+		}
+	}
+	return nil
+}
+
+// parseFormat validates a -format or output.default_mode value. "tabs" is accepted as
+// an alias for tsv so existing configs keep working.
+func parseFormat(name string) (reportFormat, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "tabs" {
+		return formatTSV, nil
+	}
+	for _, format := range reportFormats {
+		if reportFormat(name) == format {
+			return format, nil
+		}
+	}
+	return "", fmt.Errorf("unknown format %q (expected %s)", name, formatNames())
+}
+
+func formatNames() string {
+	names := make([]string, len(reportFormats))
+	for i, format := range reportFormats {
+		names[i] = string(format)
+	}
+	return strings.Join(names, ", ")
+}
+
+// resolveFormat combines -format with any deprecated mode flags that were set. It
+// returns an empty format when neither was given so callers can apply a default.
+func resolveFormat(flags *flag.FlagSet, formatFlag string, debug bool) (reportFormat, error) {
+	var format reportFormat
+	var source string
+	if strings.TrimSpace(formatFlag) != "" {
+		parsed, err := parseFormat(formatFlag)
+		if err != nil {
+			return "", err
+		}
+		format, source = parsed, "-format"
+	}
+
+	var conflict error
+	flags.Visit(func(f *flag.Flag) {
+		legacy, ok := legacyFormatFlags[f.Name]
+		shorthand, isShorthand := shorthandFormatFlags[f.Name]
+		if isShorthand {
+			legacy = shorthand
+		}
+		if (!ok && !isShorthand) || f.Value.String() != "true" || conflict != nil {
+			return
+		}
+		if debug && !isShorthand {
+			fmt.Fprintf(os.Stderr, "Note: -%s is deprecated; use -format %s.\n", f.Name, legacy)
+		}
+		if format != "" && format != legacy {
+			conflict = fmt.Errorf("choose only one output format: %s and -%s conflict", source, f.Name)
+			return
+		}
+		format, source = legacy, "-"+f.Name
+	})
+	if conflict != nil {
+		return "", conflict
+	}
+	return format, nil
+}
+
+func buildCSV(issues []jira.Issue, columns []column, sf summaryFormat, width int) (string, error) {
+	var b strings.Builder
+	w := csv.NewWriter(&b)
+	if err := w.Write(columnHeaders(columns)); err != nil {
+		return "", err
+	}
+	for i, issue := range issues {
+		if err := w.Write(columnValues(issue, i+1, columns, sf, width)); err != nil {
+			return "", err
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+func buildMarkdown(issues []jira.Issue, columns []column, sf summaryFormat, width int) string {
+	var b strings.Builder
+	titles := make([]string, len(columns))
+	rules := make([]string, len(columns))
+	for i, col := range columns {
+		titles[i], rules[i] = col.title, "---"
+	}
+	fmt.Fprintf(&b, "| %s |\n", strings.Join(titles, " | "))
+	fmt.Fprintf(&b, "| %s |\n", strings.Join(rules, " | "))
+	for row, issue := range issues {
+		cells := columnValues(issue, row+1, columns, sf, width)
+		for i, col := range columns {
+			cells[i] = escapeMarkdownCell(cells[i])
+			if url := strings.TrimSpace(issue.URL); col.name == "key" && url != "" {
+				cells[i] = fmt.Sprintf("[%s](%s)", cells[i], url)
+			}
+		}
+		fmt.Fprintf(&b, "| %s |\n", strings.Join(cells, " | "))
+	}
+	return b.String()
+}
+
+// dateOnlyLayout is the date layout of -date-only.
+const dateOnlyLayout = "2006-01-02"
+
+// truncateDate drops the time of day from a resolved or updated date for -date-only.
+// Values that are not dates, such as a resolution name, are returned unchanged.
+func truncateDate(value string) string {
+	parsed, err := time.Parse(jira.ResolvedLayout, value)
+	if err != nil {
+		return value
+	}
+	return parsed.Format(dateOnlyLayout)
+}
+
+// truncateDates applies truncateDate to the resolved and updated dates of every issue.
+func truncateDates(issues []jira.Issue) {
+	for i := range issues {
+		issues[i].Resolved = truncateDate(issues[i].Resolved)
+		issues[i].Updated = truncateDate(issues[i].Updated)
+	}
+}
+
+// buildBrief renders one "KEY<tab>Summary" line per issue, without the other columns or
+// any padding.
+func buildBrief(issues []jira.Issue, sf summaryFormat, width int) string {
+	var b strings.Builder
+	for _, issue := range issues {
+		fmt.Fprintf(&b, "%s\t%s\n", issue.Key, sf.summary(issue, width))
+	}
+	return b.String()
+}
+
+// buildDigest renders one "Status (count): KEY-1, KEY-2" line per status of
+// status-sorted issues. A status with more than keysPerLine keys continues on indented
+// lines; zero or less keeps each status on one line.
+func buildDigest(issues []jira.Issue, keysPerLine int) string {
+	var b strings.Builder
+	for _, group := range groupByStatus(issues) {
+		keys := make([]string, len(group.issues))
+		for i, issue := range group.issues {
+			keys[i] = issue.Key
+		}
+		fmt.Fprintf(&b, "%s (%d): ", group.name, len(keys))
+		for len(keys) > keysPerLine && keysPerLine > 0 {
+			fmt.Fprintf(&b, "%s,\n  ", strings.Join(keys[:keysPerLine], ", "))
+			keys = keys[keysPerLine:]
+		}
+		fmt.Fprintf(&b, "%s\n", strings.Join(keys, ", "))
+	}
+	return b.String()
+}
+
+// buildSheets renders tab-delimited rows for pasting into Google Sheets: keys become
+// =HYPERLINK formulas and dates are written as YYYY-MM-DD, which Sheets parses as dates.
+func buildSheets(issues []jira.Issue, columns []column, sf summaryFormat, width int) string {
+	var b strings.Builder
+	b.WriteString(strings.Join(columnHeaders(columns), "\t") + "\n")
+	for row, issue := range issues {
+		values := columnValues(issue, row+1, columns, sf, width)
+		for i, col := range columns {
+			switch col.name {
+			case "key":
+				if url := strings.TrimSpace(issue.URL); url != "" {
+					values[i] = fmt.Sprintf("=HYPERLINK(%s,%s)", sheetsString(url), sheetsString(values[i]))
+				}
+			case "resolved", "updated":
+				values[i] = truncateDate(values[i])
+			}
+		}
+		b.WriteString(strings.Join(values, "\t") + "\n")
+	}
+	return b.String()
+}
+
+// porcelainFields are the fields of every porcelain line, in order. The order is part
+// of the format's stability promise: fields may be added at the end, never reordered
+// or removed.
+var porcelainFields = []func(issue jira.Issue) string{
+	func(issue jira.Issue) string { return issue.Key },
+	func(issue jira.Issue) string { return issue.Status },
+	func(issue jira.Issue) string { return issue.Resolved },
+	func(issue jira.Issue) string { return issue.Updated },
+	func(issue jira.Issue) string { return issue.Parent },
+	func(issue jira.Issue) string { return issue.Assignee },
+	func(issue jira.Issue) string { return issue.Type },
+	func(issue jira.Issue) string { return issue.Priority },
+	func(issue jira.Issue) string { return issue.URL },
+	func(issue jira.Issue) string { return issue.Summary },
+}
+
+// buildPorcelain renders one tab-separated line per issue for scripts, with no header,
+// no truncation or parent prefix, and empty fields left empty. Tabs and line breaks
+// inside values become spaces so every issue stays on one line.
+func buildPorcelain(issues []jira.Issue) string {
+	var b strings.Builder
+	values := make([]string, len(porcelainFields))
+	for _, issue := range issues {
+		for i, field := range porcelainFields {
+			values[i] = porcelainValue.Replace(strings.TrimSpace(field(issue)))
+		}
+		b.WriteString(strings.Join(values, "\t") + "\n")
+	}
+	return b.String()
+}
+
+// porcelainValue flattens the characters that would break a porcelain line.
+var porcelainValue = strings.NewReplacer("\t", " ", "\r\n", " ", "\n", " ", "\r", " ")
+
+// sheetsString quotes s as a spreadsheet formula string literal.
+func sheetsString(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, `""`) + `"`
+}
+
+func escapeMarkdownCell(value string) string {
+	return strings.ReplaceAll(value, "|", `\|`)
+}
+
+// buildConfluence renders a Confluence wiki-markup table with linked keys.
+func buildConfluence(issues []jira.Issue, columns []column, sf summaryFormat, width int) string {
+	var b strings.Builder
+	titles := make([]string, len(columns))
+	for i, col := range columns {
+		titles[i] = col.title
+	}
+	fmt.Fprintf(&b, "||%s||\n", strings.Join(titles, "||"))
+	for row, issue := range issues {
+		cells := columnValues(issue, row+1, columns, sf, width)
+		for i, col := range columns {
+			cells[i] = escapeConfluenceCell(cells[i])
+			if url := strings.TrimSpace(issue.URL); col.name == "key" && url != "" {
+				cells[i] = fmt.Sprintf("[%s|%s]", cells[i], url)
+			}
+		}
+		fmt.Fprintf(&b, "|%s|\n", strings.Join(cells, "|"))
+	}
+	return b.String()
+}
+
+// escapeConfluenceCell keeps cell text from being read as table or link markup. Empty
+// cells get a single space so Confluence does not collapse them.
+func escapeConfluenceCell(value string) string {
+	if value == "" {
+		return " "
+	}
+	replacer := strings.NewReplacer("|", `\|`, "[", `\[`, "]", `\]`, "{", `\{`, "}", `\}`)
+	return replacer.Replace(value)
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"wkreport/internal/jira"
+)
+
+// checkInputFlags rejects -input combinations that need Jira.
+func checkInputFlags(rf *reportFlags) error {
+	switch {
+	case strings.TrimSpace(rf.filterRef) != "" || rf.me:
+		return errors.New("-input replaces -f and -me; the issues come from the file")
+	case rf.listFilters || rf.browseOnly || rf.dumpKey != "" || rf.showJQL:
+		return errors.New("-input cannot be combined with -ls, -browse, -dump, or -show-jql")
+	case rf.watch != 0 || rf.stream || rf.raw || rf.sectioned:
+		return errors.New("-input cannot be combined with -watch, -stream, -raw, or -sectioned")
+	case rf.sinceLastRun || rf.resetSince || rf.jiraOrder != "" || rf.resolveParent:
+		return errors.New("-input cannot be combined with -since-last-run, -reset-since, -jira-order, or -resolve-parents")
+	}
+	return nil
+}
+
+// loadSavedIssues reads issues saved with -format json (an array) or -format jsonl
+// (one object per line).
+func loadSavedIssues(path string) ([]jira.Issue, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read -input: %w", err)
+	}
+
+	var issues []jira.Issue
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '[' {
+		if err := json.Unmarshal(data, &issues); err != nil {
+			return nil, fmt.Errorf("decode %s: %w", path, err)
+		}
+		return issues, nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	for {
+		var issue jira.Issue
+		if err := dec.Decode(&issue); err == io.EOF {
+			return issues, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("decode %s: %w", path, err)
+		}
+		issues = append(issues, issue)
+	}
+}
+
+// saveIssues writes issues for -save in the -format json layout that -input reads.
+func saveIssues(path string, issues []jira.Issue) error {
+	if issues == nil {
+		issues = []jira.Issue{}
+	}
+	payload, err := buildJSON(issues)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, []byte(payload+"\n"), 0o644); err != nil {
+		return fmt.Errorf("write -save file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"wkreport/internal/jira"
+)
+
+// issueJournal records the issue details fetched for a saved filter, so that -resume can
+// continue a report whose search failed or was interrupted without fetching those
+// issues again. Each filter has its own journal in the cache directory; it is removed
+// once the filter's search completes without skipping any issue.
+type issueJournal struct {
+	// resume reuses the details recorded by the previous run.
+	resume bool
+	// fields are the fields the client requests. A journal recorded with other fields
+	// is not reused, since its issues would lack some values.
+	fields string
+
+	path    string
+	file    *os.File
+	saved   map[string]jira.Issue
+	resumed int
+}
+
+// journalEntry is one line of a journal: a header with the fields, then one issue each.
+type journalEntry struct {
+	Fields string      `json:"fields,omitempty"`
+	ID     string      `json:"id,omitempty"`
+	Issue  *jira.Issue `json:"issue,omitempty"`
+}
+
+func journalPath(filterID int) (string, error) {
+	dir, err := cacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, fmt.Sprintf("journal-%d.jsonl", filterID)), nil
+}
+
+// open starts recording filterID's search. With resume, the issues recorded by the
+// previous run are loaded and recording continues after them; otherwise the journal
+// starts over.
+func (j *issueJournal) open(filterID int) error {
+	path, err := journalPath(filterID)
+	if err != nil {
+		return err
+	}
+	j.path, j.saved, j.resumed = path, nil, 0
+	if j.resume {
+		if j.saved, err = loadJournal(path, j.fields); err != nil {
+			return err
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("create cache dir: %w", err)
+	}
+	flags := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+	if j.saved != nil {
+		flags = os.O_WRONLY | os.O_APPEND
+	}
+	file, err := os.OpenFile(path, flags, 0o600)
+	if err != nil {
+		return fmt.Errorf("open issue journal: %w", err)
+	}
+	j.file = file
+	if j.saved == nil {
+		j.write(journalEntry{Fields: j.fields})
+	}
+	return nil
+}
+
+// loadJournal reads the issues recorded at path, returning nil when there is no journal
+// or it was recorded with other fields. A final line cut short by the interruption is
+// ignored.
+func loadJournal(path, fields string) (map[string]jira.Issue, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read issue journal: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16<<20)
+	var header journalEntry
+	if !scanner.Scan() || json.Unmarshal(scanner.Bytes(), &header) != nil || header.Fields != fields {
+		return nil, nil
+	}
+	saved := make(map[string]jira.Issue)
+	for scanner.Scan() {
+		var entry journalEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil || entry.Issue == nil {
+			continue
+		}
+		saved[entry.ID] = *entry.Issue
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read issue journal: %w", err)
+	}
+	return saved, nil
+}
+
+// lookup returns the recorded details of issueID, for the client's WithIssueJournal.
+func (j *issueJournal) lookup(issueID string) (jira.Issue, bool) {
+	issue, ok := j.saved[issueID]
+	if ok {
+		j.resumed++
+	}
+	return issue, ok
+}
+
+// record appends a fetched issue, for the client's WithIssueJournal.
+func (j *issueJournal) record(issueID string, issue jira.Issue) {
+	j.write(journalEntry{ID: issueID, Issue: &issue})
+}
+
+// write appends one entry. A journal that cannot be written is abandoned with a
+// warning rather than failing the report.
+func (j *issueJournal) write(entry journalEntry) {
+	if j.file == nil {
+		return
+	}
+	line, err := json.Marshal(entry)
+	if err == nil {
+		_, err = j.file.Write(append(line, '\n'))
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not write the issue journal %s (%v); -resume will refetch this run's issues.\n", j.path, err)
+		j.file.Close()
+		j.file = nil
+	}
+}
+
+// close stops recording. A complete search has no use for its journal, which is removed.
+func (j *issueJournal) close(complete bool) {
+	if j.file != nil {
+		j.file.Close()
+		j.file = nil
+	}
+	if complete {
+		if err := os.Remove(j.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			fmt.Fprintf(os.Stderr, "Warning: could not remove the issue journal %s: %v\n", j.path, err)
+		}
+	}
+}
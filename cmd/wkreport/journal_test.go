@@ -0,0 +1,75 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"wkreport/internal/jira"
+)
+
+func TestResumeReusesJournaledIssues(t *testing.T) {
+	fake := newFakeJira(t,
+		fakeIssue{id: "1", key: "ABC-1", status: "To Do"},
+		fakeIssue{id: "2", key: "ABC-2", status: "To Do"},
+		fakeIssue{id: "3", key: "ABC-3", status: "Done"},
+	)
+	cfgPath := writeTestConfig(t, fake.URL)
+	args := []string{"-config", cfgPath, "-f", fmt.Sprint(fakeFilterID), "-format", "porcelain", "-best-effort"}
+
+	// The first run skips ABC-3, so its search is incomplete and the journal is kept.
+	fake.mu.Lock()
+	fake.failing["3"] = true
+	fake.mu.Unlock()
+	if _, err := runCapture(t, args...); err == nil {
+		t.Fatal("first run succeeded, want the skipped issue reported")
+	}
+	path, err := journalPath(fakeFilterID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("journal after the incomplete run: %v", err)
+	}
+
+	fake.mu.Lock()
+	fake.failing["3"] = false
+	fake.mu.Unlock()
+	out, err := runCapture(t, append(args, "-resume")...)
+	if err != nil {
+		t.Fatalf("resumed run: %v", err)
+	}
+	for _, key := range []string{"ABC-1", "ABC-2", "ABC-3"} {
+		if !strings.Contains(out, key) {
+			t.Errorf("resumed report is missing %s:\n%s", key, out)
+		}
+	}
+	want := map[string]int{"1": 1, "2": 1, "3": 2}
+	if got := fake.detailRequests(); fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("detail requests = %v, want %v", got, want)
+	}
+	if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("journal after the complete run: %v, want it removed", err)
+	}
+}
+
+func TestResumeIgnoresJournalWithOtherFields(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	recorder := &issueJournal{fields: "summary,status"}
+	if err := recorder.open(fakeFilterID); err != nil {
+		t.Fatal(err)
+	}
+	recorder.record("1", jira.Issue{Key: "ABC-1"})
+	recorder.close(false)
+
+	resumed := &issueJournal{resume: true, fields: "summary,status,comment"}
+	if err := resumed.open(fakeFilterID); err != nil {
+		t.Fatal(err)
+	}
+	defer resumed.close(true)
+	if _, ok := resumed.lookup("1"); ok {
+		t.Error("issue recorded with other fields was reused")
+	}
+}
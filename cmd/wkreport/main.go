@@ -1,211 +1,1637 @@
 package main
 
 import (
+	"bufio"
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"flag"
 	"fmt"
 	"html"
+	"io"
 	"os"
 	"os/exec"
+	"os/signal"
+	"path/filepath"
 	"runtime"
 	"sort"
 	"strconv"
 	"strings"
+	"syscall"
+	"time"
 
 	"wkreport/internal/config"
 	"wkreport/internal/jira"
 )
 
 func main() {
-	if err := run(context.Background(), os.Args[1:]); err != nil {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	err := run(ctx, os.Args[1:])
+	stop()
+	var exitErr *exitCodeError
+	if errors.As(err, &exitErr) {
+		if exitErr.err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", exitErr.err)
+		}
+		os.Exit(exitErr.code)
+	}
+	if err != nil {
 		fmt.Fprintln(os.Stderr, "Error:", err)
 		os.Exit(1)
 	}
 }
 
+// version is reported in the User-Agent header. Release builds set it with
+// -ldflags "-X main.version=1.2.3".
+var version = "dev"
+
+// exitEmpty is the exit status of -fail-if-empty when the report has no issues.
+const exitEmpty = 3
+
+// exitCodeError makes main exit with code instead of 1. A nil err exits without
+// printing anything further.
+type exitCodeError struct {
+	code int
+	err  error
+}
+
+func (e *exitCodeError) Error() string {
+	if e.err == nil {
+		return fmt.Sprintf("exit status %d", e.code)
+	}
+	return e.err.Error()
+}
+
+func (e *exitCodeError) Unwrap() error { return e.err }
+
+// run dispatches to a subcommand. Arguments that do not start with a known subcommand
+// are treated as the legacy flag-only report invocation.
 func run(ctx context.Context, args []string) error {
-	normalizedArgs := normalizeFilterFlag(args)
+	if len(args) > 0 {
+		switch args[0] {
+		case "report":
+			return runReport(ctx, args[1:])
+		case "filters":
+			return runFilters(ctx, args[1:])
+		case "init":
+			return runInit(args[1:])
+		case "open":
+			return runOpen(ctx, args[1:])
+		case "doctor":
+			return runDoctor(ctx, args[1:])
+		case "config":
+			return runConfig(args[1:])
+		case "completion":
+			return runCompletion(args[1:])
+		case "help":
+			printUsage()
+			return nil
+		}
+	}
+	return runReport(ctx, args)
+}
+
+// defaultIssueTimeout is the -issue-timeout default.
+const defaultIssueTimeout = 10 * time.Second
+
+// myIssuesJQL is the query run by -me.
+const myIssuesJQL = "assignee = currentUser() AND resolution = Unresolved ORDER BY updated DESC"
+
+// reportFlags holds the values of the report command's flags.
+type reportFlags struct {
+	filterRef     string
+	configPath    string
+	profile       string
+	listFilters   bool
+	tabDelimited  bool
+	docsOutput    bool
+	slidesOutput  bool
+	noColor       bool
+	templateFlag  string
+	slackOutput   bool
+	jsonOutput    bool
+	jsonLines     bool
+	sinceLastRun  bool
+	sortSpec      string
+	resetSince    bool
+	bestEffort    bool
+	ignoreErrors  bool
+	deadline      time.Duration
+	currentSprint bool
+	browseOnly    bool
+	showJQL       bool
+	lsMatch       string
+	lsSort        string
+	lsJQL         bool
+	lsLimit       int
+	xlsxPath      string
+	outputPath    string
+	appendOutput  bool
+	parentSummary bool
+	noClipboard   bool
+	formatFlag    string
+	debug         bool
+	me            bool
+	statusFilter  string
+	bulletWidth   int
+	width         int
+	ellipsis      string
+	tableStyle    string
+	lsDetails     bool
+	lsFavourites  bool
+	failIfEmpty   bool
+	watch         time.Duration
+	force         bool
+	stream        bool
+	dumpKey       string
+	raw           bool
+	withComments  bool
+	withDesc      bool
+	resolveParent bool
+	updatedSince  string
+	sinceDays     int
+	sinceField    string
+	// resolvedSince is the first resolved date -since-days keeps, set by runReport.
+	resolvedSince string
+	fieldsSpec    string
+	brief         bool
+	digest        bool
+	sheets        bool
+	porcelain     bool
+	numbered      bool
+	inputPath     string
+	savePath      string
+	docsTemplate  string
+	countBy       string
+	countReport   bool
+	resume        bool
+	noLinks       bool
+	strict        bool
+	openOnly      bool
+	resolvedOnly  bool
+	digestKeys    int
+	jiraOrder     string
+	dateOnly      bool
+	slideBreak    string
+	verifyClip    bool
+	noParentPref  bool
+	sectioned     bool
+	groupBy       string
+	issueTimeout  time.Duration
+}
 
-	flags := flag.NewFlagSet("wkreport", flag.ContinueOnError)
+// summaryFormat returns the summary prefixing chosen by the flags, truncating with the
+// ellipsis marker.
+func (f *reportFlags) summaryFormat(ellipsis string) summaryFormat {
+	return summaryFormat{parentSummary: f.parentSummary, noParentPrefix: f.noParentPref, ellipsis: ellipsis}
+}
+
+// newReportFlagSet registers the report command's flags, storing their values in f.
+func newReportFlagSet(f *reportFlags) *flag.FlagSet {
+	flags := flag.NewFlagSet("wkreport report", flag.ContinueOnError)
 	flags.SetOutput(os.Stdout)
 
-	var filterRef string
-	var configPath string
-	var listFilters bool
-	var tabDelimited bool
-	var docsOutput bool
-	var slidesOutput bool
+	flags.StringVar(&f.filterRef, "f", "", "Jira filter identifier (name, numeric id, or filter URL; supports -f123 shorthand); comma-separate ids to combine filters")
+	flags.BoolVar(&f.sectioned, "sectioned", false, "With several -f ids, report each filter in its own labeled section instead of one merged list")
+	flags.StringVar(&f.inputPath, "input", "", "Render issues saved with -format json or jsonl instead of fetching them from Jira")
+	flags.StringVar(&f.savePath, "save", "", "Also write the fetched issues as JSON to this file, for rendering later with -input")
+	flags.StringVar(&f.configPath, "config", defaultConfigPath, "Path to configuration file")
+	flags.StringVar(&f.profile, "profile", "", "Config profile to use (defaults to default_profile)")
+	flags.BoolVar(&f.me, "me", false, "Report your unresolved assigned issues instead of a filter")
+	flags.BoolVar(&f.strict, "strict", false, "Fail, listing the offenders, when an issue's status is not in output.allowed_statuses")
+	flags.BoolVar(&f.openOnly, "open-only", false, "Only include unresolved issues")
+	flags.BoolVar(&f.resolvedOnly, "resolved-only", false, "Only include resolved issues")
+	flags.StringVar(&f.countBy, "count-by", "", "Print how many issues have each value of this field (status, assignee, type, priority, or parent) instead of the report")
+	flags.BoolVar(&f.countReport, "count-with-report", false, "With -count-by, render the report and then the tally")
+	flags.StringVar(&f.statusFilter, "status", "", "Only include issues with one of these comma-separated statuses")
+	flags.BoolVar(&f.listFilters, "ls", false, "List available Jira filters and exit")
+	flags.StringVar(&f.lsSort, "ls-sort", "name", "With -ls, sort filters by name or id")
+	flags.BoolVar(&f.lsJQL, "ls-jql", false, "With -ls, include each filter's JQL")
+	flags.BoolVar(&f.lsFavourites, "ls-favourites", false, "With -ls, only list your favourite (starred) filters")
+	flags.BoolVar(&f.lsDetails, "ls-details", false, "With -ls, include each filter's owner and a * for favourites")
+	flags.IntVar(&f.lsLimit, "ls-limit", 0, "With -ls, show at most this many filters (0 for all)")
+	flags.StringVar(&f.lsMatch, "ls-match", "", "With -ls, only list filters whose name contains this text (case-insensitive)")
+	flags.StringVar(&f.formatFlag, "format", "", "Output format: "+formatNames())
+	flags.BoolVar(&f.debug, "debug", false, "Print diagnostic notes, such as deprecated flag usage, to stderr")
+	flags.BoolVar(&f.tabDelimited, "tabs", false, "Deprecated: use -format tsv")
+	flags.BoolVar(&f.docsOutput, "docs", false, "Deprecated: use -format docs")
+	flags.BoolVar(&f.slidesOutput, "slides", false, "Deprecated: use -format slides")
+	flags.BoolVar(&f.noLinks, "no-links", false, "Print issue keys as plain text instead of links, and leave the URLs out of json and jsonl")
+	flags.StringVar(&f.tableStyle, "table-style", defaultTableStyle, "HTML table styling for the docs and html formats: bordered, minimal, or plain")
+	flags.StringVar(&f.docsTemplate, "docs-template", "", "Embed the docs and html tables in this HTML template file at its {{.Table}} ({{.FilterName}} names the filter)")
+	flags.StringVar(&f.ellipsis, "ellipsis", "", "Truncation marker: ascii (...) or unicode (…); overrides output.ellipsis")
+	flags.StringVar(&f.groupBy, "group-by", groupByStatusName, "Group slide bullets by status or assignee")
+	flags.StringVar(&f.slideBreak, "slide-break", "", "Separate slide status groups with this line (ff for a form feed) and HTML page breaks")
+	flags.IntVar(&f.bulletWidth, "bullet-width", 80, "Truncate slide bullet summaries to this many characters")
+	flags.IntVar(&f.width, "width", 0, "Truncate summaries to this many characters in every format, overriding output.widths and -bullet-width (0 for no limit)")
+	flags.BoolVar(&f.noColor, "no-color", false, "Disable colored status output in the terminal table")
+	flags.BoolVar(&f.slackOutput, "slack", false, "Deprecated: use -format slack")
+	flags.BoolVar(&f.jsonOutput, "json", false, "Deprecated: use -format json")
+	flags.BoolVar(&f.verifyClip, "verify-clipboard", false, "Read the clipboard back after copying and fall back to stdout if it is empty")
+	flags.BoolVar(&f.noClipboard, "no-clipboard", false, "Print -docs, -slides, and -tabs output to stdout even in a terminal")
+	flags.BoolVar(&f.noParentPref, "no-parent-prefix", false, "Do not prefix summaries with their parent; rely on the parent column")
+	flags.BoolVar(&f.parentSummary, "parent-summary", false, "Prefix summaries with the parent's summary instead of its key")
+	flags.BoolVar(&f.resolveParent, "resolve-parents", false, "Look up parent summaries Jira did not include, once per distinct parent")
+	flags.StringVar(&f.outputPath, "o", "", "Write the report to this file instead of stdout or the clipboard")
+	flags.BoolVar(&f.appendOutput, "append", false, "With -o, append to the file with a timestamped separator instead of overwriting")
+	flags.StringVar(&f.xlsxPath, "xlsx", "", "Write the report to an Excel .xlsx file at this path")
+	flags.BoolVar(&f.jsonLines, "jsonl", false, "Deprecated: use -format jsonl")
+	flags.BoolVar(&f.brief, "brief", false, "Print only \"KEY<tab>Summary\" lines (same as -format brief)")
+	flags.BoolVar(&f.digest, "digest", false, "Print one \"Status (count): KEY-1, KEY-2\" line per status (same as -format digest)")
+	flags.BoolVar(&f.sheets, "sheets", false, "Print tab-delimited rows for Google Sheets, with HYPERLINK keys and YYYY-MM-DD dates (same as -format sheets)")
+	flags.BoolVar(&f.porcelain, "porcelain", false, "Print stable tab-separated lines for scripts, with fixed fields and no header (same as -format porcelain)")
+	flags.IntVar(&f.digestKeys, "digest-keys", 10, "With the digest format, wrap a status's keys onto further lines after this many (0 for no wrapping)")
+	flags.StringVar(&f.jiraOrder, "jira-order", "", "Have Jira sort the results with this ORDER BY (e.g. \"updated DESC\") and keep its order")
+	flags.BoolVar(&f.dateOnly, "date-only", false, "Show resolved and updated dates without the time of day (2006-01-02)")
+	flags.StringVar(&f.sortSpec, "sort", "", "Comma-separated sort keys (key, summary, status, parent, resolved, assignee); prefix with - for descending")
+	flags.StringVar(&f.updatedSince, "updated-since", "", "Only include issues updated on or after this date (YYYY-MM-DD), filtered after fetching")
+	flags.IntVar(&f.sinceDays, "since-days", 0, "Only include issues resolved (or, with -since-field updated, updated) within the last N days; 0 disables")
+	flags.StringVar(&f.sinceField, "since-field", sinceFieldResolved, "With -since-days, the date to compare: resolved or updated")
+	flags.BoolVar(&f.sinceLastRun, "since-last-run", false, "Only fetch issues updated since the last successful -since-last-run report for this filter")
+	flags.BoolVar(&f.resetSince, "reset-since", false, "Clear the stored -since-last-run timestamp for the filter before running")
+	flags.BoolVar(&f.bestEffort, "best-effort", false, "Skip issues whose details cannot be fetched and report the failures at the end")
+	flags.BoolVar(&f.resume, "resume", false, "Reuse the issue details fetched by this filter's last failed or interrupted run instead of fetching them again")
+	flags.BoolVar(&f.ignoreErrors, "ignore-errors", false, "With -best-effort, exit successfully even if some issues failed")
+	flags.BoolVar(&f.numbered, "numbered", false, "Prepend a # column numbering the rows, after sorting and filtering (table, tsv, sheets, and csv)")
+	flags.StringVar(&f.fieldsSpec, "fields", "", "Comma-separated columns for the tabular outputs (key, summary, status, parent, parent-summary, resolved, updated, assignee, type, priority, comments, description)")
+	flags.BoolVar(&f.withComments, "with-comments", false, "Fetch each issue's comment count and add a comments column")
+	flags.BoolVar(&f.withDesc, "with-description", false, "Fetch each issue's description as plain text, for templates, JSON, and a description column")
+	flags.BoolVar(&f.stream, "stream", false, "Print table rows as each issue arrives, in Jira's order (no -sort)")
+	flags.BoolVar(&f.force, "force", false, "Fetch the report even if it matches more than jira.max_issues issues")
+	flags.BoolVar(&f.failIfEmpty, "fail-if-empty", false, "Exit with status 3 when the report has no issues")
+	flags.DurationVar(&f.watch, "watch", 0, "Redraw the report on this interval (e.g. 1m) until interrupted; needs a terminal")
+	flags.DurationVar(&f.issueTimeout, "issue-timeout", defaultIssueTimeout, "Give up on an issue whose details take longer than this (skipped with -best-effort); 0 disables")
+	flags.DurationVar(&f.deadline, "deadline", 0, "Abort the whole report if it takes longer than this (e.g. 2m); 0 disables")
+	flags.BoolVar(&f.currentSprint, "current-sprint", false, "Only include issues in an active sprint (requires jira.sprint_field)")
+	flags.BoolVar(&f.browseOnly, "browse", false, "Print the Jira web URL for the filter's JQL instead of fetching issues")
+	flags.BoolVar(&f.showJQL, "show-jql", false, "Print the resolved filter's JQL to stderr")
+	flags.StringVar(&f.templateFlag, "template", "", "Render issues with a Go text/template (inline or @file)")
+	flags.BoolVar(&f.raw, "raw", false, "Print the issues of Jira's search response as raw JSON, without fetching or formatting them")
+	flags.StringVar(&f.dumpKey, "dump", "", "Print one issue's raw JSON with every field and exit")
+
+	flags.Usage = func() { printVisibleDefaults(flags) }
+	return flags
+}
 
-	flags.StringVar(&filterRef, "f", "", "Jira filter identifier (name or numeric id, supports -f123 shorthand)")
-	flags.StringVar(&configPath, "config", "cfg/config.yaml", "Path to configuration file")
-	flags.BoolVar(&listFilters, "ls", false, "List available Jira filters and exit")
-	flags.BoolVar(&tabDelimited, "tabs", false, "Output report using tab-separated fields")
-	flags.BoolVar(&docsOutput, "docs", false, "Output report formatted for Google Docs tables")
-	flags.BoolVar(&slidesOutput, "slides", false, "Output report formatted for Google Slides bullets")
+// validate rejects flag values and combinations that cannot work together in a report
+// rendered in format, the format after output.default_mode, normalizing the values it
+// checks. It only looks at the flags, so runReport calls it before contacting Jira.
+func (f *reportFlags) validate(flags *flag.FlagSet, format reportFormat) error {
+	switch {
+	case f.me && strings.TrimSpace(f.filterRef) != "":
+		return errors.New("choose either -me or -f, not both")
+	case f.me && (f.sinceLastRun || f.resetSince):
+		return errors.New("-since-last-run and -reset-since need a saved filter (-f), not -me")
+	case f.resume && (f.me || f.inputPath != ""):
+		return errors.New("-resume needs a saved filter (-f), not -me or -input")
+	case f.issueTimeout < 0:
+		return errors.New("-issue-timeout cannot be negative")
+	case f.deadline < 0:
+		return errors.New("-deadline must not be negative")
+	case f.openOnly && f.resolvedOnly:
+		return errors.New("choose either -open-only or -resolved-only, not both")
+	case f.noParentPref && f.parentSummary:
+		return errors.New("choose either -parent-summary or -no-parent-prefix, not both")
+	case f.digestKeys < 0:
+		return errors.New("-digest-keys cannot be negative")
+	case f.bulletWidth < 4:
+		return errors.New("-bullet-width must be at least 4")
+	case f.width < 0 || f.width > 0 && f.width < 4:
+		return errors.New("-width must be 0 (no limit) or at least 4")
+	case f.appendOutput && f.outputPath == "":
+		return errors.New("-append requires -o")
+	case strings.TrimSpace(f.jiraOrder) != "" && f.sortSpec != "":
+		return errors.New("choose either -jira-order or -sort, not both")
+	case f.countReport && f.countBy == "":
+		return errors.New("-count-with-report requires -count-by")
+	case format == formatPorcelain && f.fieldsSpec != "":
+		return errors.New("-fields does not apply to -format porcelain, whose fields are fixed")
+	}
+
+	if f.updatedSince = strings.TrimSpace(f.updatedSince); f.updatedSince != "" {
+		if _, err := time.Parse(dateOnlyLayout, f.updatedSince); err != nil {
+			return fmt.Errorf("-updated-since %q is not a YYYY-MM-DD date", f.updatedSince)
+		}
+	}
+	f.tableStyle = strings.ToLower(strings.TrimSpace(f.tableStyle))
+	if _, ok := tableStyles[f.tableStyle]; !ok {
+		return fmt.Errorf("unknown -table-style %q (expected bordered, minimal, or plain)", f.tableStyle)
+	}
+	switch f.groupBy = strings.ToLower(strings.TrimSpace(f.groupBy)); f.groupBy {
+	case groupByStatusName:
+	case groupByAssigneeName:
+		if format != formatSlides {
+			return errors.New("-group-by assignee only applies to -format slides")
+		}
+	default:
+		return fmt.Errorf("unknown -group-by %q (expected %s or %s)", f.groupBy, groupByStatusName, groupByAssigneeName)
+	}
+	if f.numbered {
+		switch format {
+		case "", formatTable, formatTSV, formatSheets, formatCSV:
+		default:
+			return fmt.Errorf("-numbered only applies to the table, tsv, sheets, and csv formats, not %s", format)
+		}
+	}
+
+	var err error
+	if f.watch != 0 {
+		err = checkWatchFlags(f)
+	}
+	if err == nil && f.countBy != "" {
+		err = checkCountByFlags(f, flags)
+	}
+	if err == nil && f.docsTemplate != "" {
+		err = checkDocsTemplateFlags(f, format)
+	}
+	if err == nil && f.inputPath != "" {
+		err = checkInputFlags(f)
+	}
+	if err == nil && f.stream {
+		err = checkStreamFlags(f, format)
+	}
+	if err == nil && f.raw {
+		err = checkRawFlags(f, format)
+	}
+	if err == nil && len(splitFilterRefs(f.filterRef)) > 1 {
+		err = checkMultiFilterFlags(f)
+	}
+	if err == nil && f.sectioned {
+		err = checkSectionedFlags(f, format)
+	}
+	return err
+}
+
+func runReport(ctx context.Context, args []string) error {
+	normalizedArgs := normalizeFilterFlag(args)
+
+	var rf reportFlags
+	flags := newReportFlagSet(&rf)
 
 	if err := flags.Parse(normalizedArgs); err != nil {
 		return err
 	}
 
-	if docsOutput && slidesOutput {
-		return errors.New("choose either -docs or -slides, not both")
+	format, err := resolveFormat(flags, rf.formatFlag, rf.debug)
+	if err != nil {
+		return err
+	}
+
+	if strings.TrimSpace(rf.filterRef) == stdinFilterRef {
+		if rf.filterRef, err = readFilterRef(os.Stdin); err != nil {
+			return err
+		}
 	}
 
-	cfg, err := config.Load(configPath)
+	load := config.LoadProfile
+	if rf.inputPath != "" {
+		load = config.LoadOffline
+	}
+	cfg, err := load(rf.configPath, rf.profile)
 	if err != nil {
 		return fmt.Errorf("load config: %w", err)
 	}
 
-	client, err := jira.NewClient(cfg.Jira.URL, cfg.Jira.Email, cfg.Jira.APIToken)
+	if mode := cfg.Output.DefaultMode; mode != "" && !modeFlagSet(flags) {
+		format, err = parseFormat(mode)
+		if err != nil {
+			return fmt.Errorf("output.default_mode: %w", err)
+		}
+	}
+
+	if err := rf.validate(flags, format); err != nil {
+		return err
+	}
+	if err := applySinceDays(&rf, flags, time.Now()); err != nil {
+		return err
+	}
+
+	if rf.deadline > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, rf.deadline)
+		defer cancel()
+	}
+
+	tableOrder, statusOrder, err := resolveSortOrders(rf.sortSpec)
 	if err != nil {
-		return fmt.Errorf("create jira client: %w", err)
+		return err
+	}
+	if strings.TrimSpace(rf.jiraOrder) != "" {
+		// Keep Jira's order; the grouped outputs still need their status groups
+		// contiguous, which a stable sort on status alone preserves within each group.
+		tableOrder, statusOrder = nil, []sortKey{{field: "status"}}
+	}
+	columns, err := parseColumns(rf.fieldsSpec, rf.withComments)
+	if err != nil {
+		return err
+	}
+
+	var templateSource string
+	if rf.templateFlag != "" {
+		source, err := loadTemplateSource(rf.templateFlag)
+		if err != nil {
+			return err
+		}
+		templateSource = source
+	}
+
+	ellipsisStyle := cfg.Output.Ellipsis
+	if rf.ellipsis != "" {
+		ellipsisStyle = rf.ellipsis
+	}
+	ellipsis, err := parseEllipsis(ellipsisStyle)
+	if err != nil {
+		return err
+	}
+	summary := rf.summaryFormat(ellipsis)
+	colors, err := newStatusColors(cfg.Output.StatusColors)
+	if err != nil {
+		return fmt.Errorf("output.status_colors: %w", err)
+	}
+	if err := checkWidths(cfg.Output.Widths); err != nil {
+		return fmt.Errorf("output.widths: %w", err)
 	}
 
-	if listFilters {
-		return displayFilters(ctx, client)
+	var countBy *column
+	if rf.countBy != "" {
+		col, err := parseCountBy(rf.countBy)
+		if err != nil {
+			return err
+		}
+		countBy = &col
+	}
+	var wrapper *docsWrapper
+	if rf.docsTemplate != "" {
+		if wrapper, err = loadDocsWrapper(rf.docsTemplate); err != nil {
+			return err
+		}
 	}
 
-	if strings.TrimSpace(filterRef) == "" {
-		return errors.New("filter identifier (-f) is required")
+	width := resolveSummaryWidth(format, cfg.Output.Widths, &rf, flags)
+	if format == "" || format == formatTable {
+		columns = withSummaryWidth(columns, width)
 	}
 
-	filter, err := client.ResolveFilter(ctx, strings.TrimSpace(filterRef))
+	tableOrder = withStatusOrder(tableOrder, cfg.Output.StatusOrder)
+	statusOrder = withStatusOrder(statusOrder, cfg.Output.StatusOrder)
+
+	if rf.strict && len(cfg.Output.AllowedStatuses) == 0 {
+		return errors.New("-strict requires output.allowed_statuses in the config")
+	}
+
+	if rf.inputPath != "" {
+		// With no filters the run loads issues from the file instead of searching.
+		run := &reportRun{
+			flags:          &rf,
+			cfg:            cfg,
+			filter:         &jira.Filter{Name: filepath.Base(rf.inputPath)},
+			format:         format,
+			templateSource: templateSource,
+			docsWrapper:    wrapper,
+			summary:        summary,
+			colors:         colors,
+			summaryWidth:   width,
+			countBy:        countBy,
+			tableOrder:     tableOrder,
+			statusOrder:    statusOrder,
+			columns:        columns,
+		}
+		return run.fetchAndRender(ctx)
+	}
+
+	if rf.currentSprint && cfg.Jira.SprintField == "" {
+		return errors.New("-current-sprint requires jira.sprint_field in the config")
+	}
+
+	maxIssues := cfg.Jira.MaxIssues
+	if rf.force {
+		maxIssues = 0
+	}
+	clientOpts := []jira.Option{
+		jira.WithBestEffort(rf.bestEffort),
+		jira.WithSprintField(cfg.Jira.SprintField),
+		jira.WithStoryPointsField(cfg.Jira.StoryPointsField),
+		jira.WithMaxIssues(maxIssues),
+		jira.WithComments(rf.withComments),
+		jira.WithDescription(rf.withDesc),
+		jira.WithExtraFields(cfg.Jira.ExtraFields),
+		jira.WithIssueTimeout(rf.issueTimeout),
+	}
+	var stream *tableStream
+	if rf.stream {
+		stream = &tableStream{
+			out:           os.Stdout,
+			summary:       summary,
+			columns:       columns,
+			colors:        terminalColors(colors, os.Stdout, rf.noColor),
+			statuses:      newStatusNormalizer(cfg.Output.StatusAliases),
+			statusFilter:  rf.statusFilter,
+			currentSprint: rf.currentSprint,
+			updatedSince:  rf.updatedSince,
+			resolvedSince: rf.resolvedSince,
+			openOnly:      rf.openOnly,
+			resolvedOnly:  rf.resolvedOnly,
+			dateOnly:      rf.dateOnly,
+			noLinks:       rf.noLinks,
+		}
+		clientOpts = append(clientOpts, jira.WithIssueCallback(stream.add))
+	}
+	var raw *rawIssues
+	if rf.raw {
+		raw = &rawIssues{}
+		clientOpts = append(clientOpts, jira.WithRawSearch(raw.add))
+	}
+	// Filter searches keep a journal of the fetched issues for -resume.
+	var journal *issueJournal
+	if !rf.me && raw == nil {
+		journal = &issueJournal{resume: rf.resume}
+		clientOpts = append(clientOpts, jira.WithIssueJournal(journal.lookup, journal.record))
+	}
+	client, err := newJiraClient(cfg, clientOpts...)
 	if err != nil {
-		return fmt.Errorf("resolve filter %q: %w", filterRef, err)
+		return fmt.Errorf("create jira client: %w", err)
+	}
+	if journal != nil {
+		journal.fields = strings.Join(client.IssueFields(), ",")
+	}
+	if err := checkColumnFields(columns, client.IssueFields()); err != nil {
+		return err
+	}
+	if rf.debug {
+		defer printClientStats(client)
 	}
 
-	issues, err := client.SearchByFilter(ctx, filter)
+	if key := strings.TrimSpace(rf.dumpKey); key != "" {
+		return contextError(ctx, rf.deadline, dumpIssue(ctx, client, key))
+	}
+
+	if rf.listFilters {
+		return contextError(ctx, rf.deadline, displayFilters(ctx, client, listOptions{
+			match:      rf.lsMatch,
+			sortBy:     rf.lsSort,
+			showJQL:    rf.lsJQL,
+			details:    rf.lsDetails,
+			favourites: rf.lsFavourites,
+			limit:      rf.lsLimit,
+		}))
+	}
+
+	var filters []*jira.Filter
+	if rf.me {
+		filters = []*jira.Filter{{Name: "my open issues", JQL: myIssuesJQL}}
+	} else {
+		if strings.TrimSpace(rf.filterRef) == "" {
+			return errors.New("filter identifier (-f) is required")
+		}
+		for _, ref := range splitFilterRefs(rf.filterRef) {
+			filter, err := client.ResolveFilter(ctx, ref)
+			if err != nil {
+				return contextError(ctx, rf.deadline, fmt.Errorf("resolve filter %q: %w", ref, err))
+			}
+			if rf.debug {
+				logResolvedFilter(ref, filter)
+			}
+			filters = append(filters, filter)
+		}
+	}
+
+	if rf.showJQL {
+		for _, filter := range filters {
+			jql := strings.TrimSpace(filter.JQL)
+			if jql == "" {
+				jql = "(no JQL returned by Jira)"
+			}
+			if rf.me {
+				fmt.Fprintf(os.Stderr, "-me JQL: %s\n", jql)
+			} else {
+				fmt.Fprintf(os.Stderr, "Filter %d (%s) JQL: %s\n", filter.ID, filter.Name, jql)
+			}
+		}
+	}
+
+	filter := filters[0]
+	if len(filters) > 1 && !rf.sectioned {
+		filter = mergedFilter(filters)
+	}
+
+	if rf.browseOnly {
+		if strings.TrimSpace(filter.JQL) == "" {
+			return fmt.Errorf("filter %q has no JQL to browse", filter.Name)
+		}
+		fmt.Println(client.BrowseJQLURL(filter.JQL))
+		return nil
+	}
+
+	if rf.resetSince {
+		for _, filter := range filters {
+			if err := resetLastRun(filter.ID); err != nil {
+				return err
+			}
+			fmt.Fprintf(os.Stderr, "Cleared -since-last-run state for filter %d.\n", filter.ID)
+		}
+	}
+
+	run := &reportRun{
+		flags:          &rf,
+		cfg:            cfg,
+		client:         client,
+		filter:         filter,
+		filters:        filters,
+		format:         format,
+		templateSource: templateSource,
+		docsWrapper:    wrapper,
+		summary:        summary,
+		colors:         colors,
+		summaryWidth:   width,
+		countBy:        countBy,
+		tableOrder:     tableOrder,
+		statusOrder:    statusOrder,
+		columns:        columns,
+		stream:         stream,
+		raw:            raw,
+		journal:        journal,
+	}
+	if rf.sectioned {
+		return run.renderSections(ctx)
+	}
+	if rf.watch > 0 {
+		return watchReport(ctx, rf.watch, time.After, run)
+	}
+	return run.fetchAndRender(ctx)
+}
+
+// reportRun holds everything resolved before issues are fetched, so the fetch and
+// render steps can be repeated by -watch.
+type reportRun struct {
+	flags  *reportFlags
+	cfg    *config.Config
+	client *jira.Client
+	// filter names the report; filters are the filters searched, usually just filter.
+	filter         *jira.Filter
+	filters        []*jira.Filter
+	format         reportFormat
+	templateSource string
+	docsWrapper    *docsWrapper
+	summary        summaryFormat
+	colors         statusColors
+	// summaryWidth is the format's summary truncation width, 0 for no limit.
+	summaryWidth int
+	// countBy, when set, is the column -count-by tallies.
+	countBy     *column
+	tableOrder  []sortKey
+	statusOrder []sortKey
+	columns     []column
+	// stream, when set, has already printed the rows as they were fetched.
+	stream *tableStream
+	// raw, when set, collects the search response issues for -raw.
+	raw *rawIssues
+	// journal, when set, records each filter's fetched issues for -resume.
+	journal *issueJournal
+	// user is the audit log's user, looked up on first use.
+	user string
+}
+
+// filterResult is what searchFilter fetched for one filter.
+type filterResult struct {
+	issues []jira.Issue
+	// partial reports the issues a best-effort search skipped.
+	partial *jira.PartialResultError
+	// interrupted is set when a best-effort search was interrupted after fetching issues.
+	interrupted error
+}
+
+// searchFilter fetches one filter's issues.
+func (r *reportRun) searchFilter(ctx context.Context, filter *jira.Filter, runStarted time.Time) (filterResult, error) {
+	// jql stays empty when the filter's own search URL can be used as is.
+	var jql string
+	if r.flags.me {
+		jql = filter.JQL
+	} else if r.flags.sinceLastRun {
+		lastRun, err := loadLastRun(filter.ID)
+		if err != nil {
+			return filterResult{}, err
+		}
+		if !lastRun.IsZero() {
+			if strings.TrimSpace(filter.JQL) == "" {
+				return filterResult{}, fmt.Errorf("filter %q has no JQL to narrow for -since-last-run", filter.Name)
+			}
+			fmt.Fprintf(os.Stderr, "Showing issues updated since %s.\n", lastRun.Local().Format(jqlTimeLayout))
+			jql = jira.AndJQL(filter.JQL, updatedSinceClause(lastRun, time.Now()))
+		}
+	}
+	if order := strings.TrimSpace(r.flags.jiraOrder); order != "" {
+		if jql == "" {
+			if strings.TrimSpace(filter.JQL) == "" {
+				return filterResult{}, fmt.Errorf("filter %q has no JQL to reorder for -jira-order", filter.Name)
+			}
+			jql = filter.JQL
+		}
+		jql = jira.OrderJQL(jql, order)
+	}
+
+	complete := false
+	if r.journal != nil && filter.ID > 0 {
+		if err := r.journal.open(filter.ID); err != nil {
+			return filterResult{}, err
+		}
+		defer func() { r.journal.close(complete) }()
+	}
+
+	var issues []jira.Issue
+	var err error
+	if jql == "" {
+		issues, err = r.client.SearchByFilter(ctx, filter)
+	} else {
+		issues, err = r.client.SearchByJQL(ctx, jql)
+	}
+
+	var partial *jira.PartialResultError
+	if errors.As(err, &partial) {
+		err = nil
+	}
+	var interruptErr error
+	if errors.Is(err, jira.ErrInterrupted) && r.flags.bestEffort && len(issues) > 0 {
+		interruptErr = fmt.Errorf("search jira issues: %w; the report above is partial", err)
+		fmt.Fprintf(os.Stderr, "Interrupted: showing the %d issue(s) fetched so far.\n", len(issues))
+		err = nil
+	}
+	var tooMany *jira.TooManyIssuesError
+	if errors.As(err, &tooMany) {
+		matched := fmt.Sprintf("%d", tooMany.Matched)
+		if !tooMany.Exact {
+			matched = fmt.Sprintf("more than %d", tooMany.Limit)
+		}
+		return filterResult{}, fmt.Errorf("filter matched %s issues, exceeding max_issues (%d); narrow your filter or raise the cap, or rerun with -force", matched, tooMany.Limit)
+	}
 	if err != nil {
-		return fmt.Errorf("search jira issues: %w", err)
+		return filterResult{}, contextError(ctx, r.flags.deadline, fmt.Errorf("search jira issues: %w", err))
+	}
+	if r.journal != nil && r.journal.resumed > 0 {
+		fmt.Fprintf(os.Stderr, "Resumed %d issue(s) fetched by the previous run of %s.\n", r.journal.resumed, filter.Name)
+	}
+	complete = partial == nil && interruptErr == nil
+	if r.flags.sinceLastRun && complete {
+		if err := saveLastRun(filter.ID, runStarted); err != nil {
+			return filterResult{}, err
+		}
+	}
+	return filterResult{issues: issues, partial: partial, interrupted: interruptErr}, nil
+}
+
+// fetchAndRender searches for the filters' issues and renders them. Issues matched by
+// several filters are reported once.
+func (r *reportRun) fetchAndRender(ctx context.Context) error {
+	runStarted := time.Now()
+	var issues []jira.Issue
+	var partial *jira.PartialResultError
+	var interruptErr error
+	if r.flags.inputPath != "" {
+		saved, err := loadSavedIssues(r.flags.inputPath)
+		if err != nil {
+			return err
+		}
+		issues = saved
+	}
+	seen := make(map[string]bool)
+	for _, filter := range r.filters {
+		result, err := r.searchFilter(ctx, filter, runStarted)
+		if err != nil {
+			return err
+		}
+		for _, issue := range result.issues {
+			if !seen[issue.Key] {
+				seen[issue.Key] = true
+				issues = append(issues, issue)
+			}
+		}
+		if result.partial != nil {
+			if partial == nil {
+				partial = &jira.PartialResultError{}
+			}
+			partial.Failures = append(partial.Failures, result.partial.Failures...)
+		}
+		if result.interrupted != nil {
+			interruptErr = result.interrupted
+			break
+		}
+	}
+
+	if r.raw != nil {
+		return r.raw.write(os.Stdout)
+	}
+	if r.flags.savePath != "" {
+		if err := saveIssues(r.flags.savePath, issues); err != nil {
+			return err
+		}
+	}
+
+	if r.stream != nil {
+		issues = r.stream.issues
+	} else {
+		normalizeStatuses(issues, r.cfg.Output.StatusAliases)
+		if r.flags.dateOnly {
+			truncateDates(issues)
+		}
+		if r.flags.currentSprint {
+			issues = filterCurrentSprint(issues)
+		}
+		if r.flags.statusFilter != "" {
+			issues = filterStatuses(issues, r.flags.statusFilter)
+		}
+		if r.flags.updatedSince != "" {
+			issues = filterUpdatedSince(issues, r.flags.updatedSince)
+		}
+		if r.flags.resolvedSince != "" {
+			issues = filterResolvedSince(issues, r.flags.resolvedSince)
+		}
+		if r.flags.openOnly || r.flags.resolvedOnly {
+			issues = filterResolution(issues, r.flags.resolvedOnly)
+		}
+		if r.flags.resolveParent {
+			resolveParentSummaries(ctx, r.client, issues)
+		}
+		if r.flags.noLinks {
+			dropLinks(issues)
+		}
 	}
+	if r.flags.strict {
+		if err := checkAllowedStatuses(issues, r.cfg.Output.AllowedStatuses); err != nil {
+			return err
+		}
+	}
+	r.writeAudit(ctx, len(issues))
 
 	if len(issues) == 0 {
-		fmt.Println("No issues found.")
+		// Porcelain output stays parseable: no issues is no lines.
+		if r.format != formatPorcelain {
+			fmt.Println("No issues found.")
+		}
+		if partial != nil {
+			reportFetchFailures(partial)
+			if !r.flags.ignoreErrors {
+				return partial
+			}
+		}
+		if r.flags.failIfEmpty {
+			return &exitCodeError{code: exitEmpty}
+		}
 		return nil
 	}
+	if r.stream != nil {
+		writeTableFooter(issues, reportOptions{out: os.Stdout, points: r.cfg.Jira.StoryPointsField != "", statusOrder: r.statusOrder})
+		return finishReport(nil, partial, interruptErr, r.flags.ignoreErrors)
+	}
 
-	if docsOutput {
-		sortIssues(issues, false)
-		tableHTML := buildDocsHTML(issues)
-		rtfPayload, rtfErr := convertHTMLToRTF(tableHTML)
+	opts := reportOptions{
+		out:            os.Stdout,
+		interactive:    copiesToClipboard(os.Stdout, r.flags),
+		colors:         terminalColors(r.colors, os.Stdout, r.flags.noColor),
+		hints:          !r.flags.noClipboard && r.flags.watch == 0 && !r.flags.sectioned,
+		verifyCopy:     r.flags.verifyClip,
+		summary:        r.summary,
+		summaryWidth:   r.summaryWidth,
+		countBy:        r.countBy,
+		countReport:    r.flags.countReport,
+		slideBreak:     slideSeparator(r.flags.slideBreak),
+		groupBy:        r.flags.groupBy,
+		digestKeys:     r.flags.digestKeys,
+		tableAttrs:     tableStyles[r.flags.tableStyle],
+		docsWrapper:    r.docsWrapper,
+		filterName:     r.filter.Name,
+		numbered:       r.flags.numbered,
+		points:         r.cfg.Jira.StoryPointsField != "" || r.flags.inputPath != "",
+		format:         r.format,
+		xlsxPath:       r.flags.xlsxPath,
+		templateSource: r.templateSource,
+		tableOrder:     r.tableOrder,
+		statusOrder:    r.statusOrder,
+		columns:        r.columns,
+	}
+
+	if r.flags.outputPath != "" {
+		file, err := openReportFile(r.flags.outputPath, r.flags.appendOutput, &opts)
+		if err != nil {
+			return err
+		}
+		opts.out, opts.interactive, opts.colors, opts.hints = file, false, nil, false
+		renderErr := renderReport(issues, opts)
+		if err := file.Close(); err != nil && renderErr == nil {
+			renderErr = fmt.Errorf("close output file: %w", err)
+		}
+		if renderErr == nil {
+			fmt.Fprintf(os.Stderr, "Report written to %s.\n", r.flags.outputPath)
+		}
+		return finishReport(renderErr, partial, interruptErr, r.flags.ignoreErrors)
+	}
+
+	renderErr := renderReport(issues, opts)
+	return finishReport(renderErr, partial, interruptErr, r.flags.ignoreErrors)
+}
+
+// finishReport combines the render result with any best-effort failures or interruption.
+func finishReport(renderErr error, partial *jira.PartialResultError, interruptErr error, ignoreErrors bool) error {
+	if partial != nil {
+		reportFetchFailures(partial)
+		if renderErr == nil && !ignoreErrors {
+			renderErr = fmt.Errorf("%w (use -ignore-errors to exit successfully)", partial)
+		}
+	}
+	if interruptErr != nil {
+		return interruptErr
+	}
+	return renderErr
+}
+
+// filterStatuses keeps the issues whose status is in the comma-separated list, ignoring case.
+func filterStatuses(issues []jira.Issue, list string) []jira.Issue {
+	wanted := make(map[string]bool)
+	for _, status := range strings.Split(list, ",") {
+		if status = strings.ToLower(strings.TrimSpace(status)); status != "" {
+			wanted[status] = true
+		}
+	}
+	if len(wanted) == 0 {
+		return issues
+	}
+
+	filtered := make([]jira.Issue, 0, len(issues))
+	for _, issue := range issues {
+		if wanted[strings.ToLower(strings.TrimSpace(issue.Status))] {
+			filtered = append(filtered, issue)
+		}
+	}
+	return filtered
+}
+
+// checkAllowedStatuses fails -strict runs with an issue whose status is not in allowed,
+// listing the offending keys under each unexpected status.
+func checkAllowedStatuses(issues []jira.Issue, allowed []string) error {
+	known := make(map[string]bool, len(allowed))
+	for _, status := range allowed {
+		known[strings.ToLower(strings.TrimSpace(status))] = true
+	}
+
+	var statuses []string
+	offenders := make(map[string][]string)
+	count := 0
+	for _, issue := range issues {
+		status := strings.TrimSpace(issue.Status)
+		if known[strings.ToLower(status)] {
+			continue
+		}
+		if _, seen := offenders[status]; !seen {
+			statuses = append(statuses, status)
+		}
+		offenders[status] = append(offenders[status], issue.Key)
+		count++
+	}
+	if count == 0 {
+		return nil
+	}
+
+	groups := make([]string, len(statuses))
+	for i, status := range statuses {
+		label := status
+		if label == "" {
+			label = "(no status)"
+		}
+		groups[i] = fmt.Sprintf("%s (%s)", label, strings.Join(offenders[status], ", "))
+	}
+	return fmt.Errorf("-strict: %d issue(s) have a status outside output.allowed_statuses: %s", count, strings.Join(groups, "; "))
+}
 
-		if isTerminal(os.Stdout) {
-			if rtfErr == nil {
-				if err := copyToClipboard("rtf", rtfPayload); err == nil {
-					fmt.Fprintln(os.Stderr, "Google Docs table copied to clipboard. Paste directly into your document.")
-					return nil
-				}
+// filterResolution keeps the resolved issues when resolved is set, and otherwise the
+// unresolved ones: those with no resolution, or one named "Unresolved" as some
+// workflows set.
+func filterResolution(issues []jira.Issue, resolved bool) []jira.Issue {
+	filtered := make([]jira.Issue, 0, len(issues))
+	for _, issue := range issues {
+		value := strings.TrimSpace(issue.Resolved)
+		isResolved := value != "" && !strings.EqualFold(value, "Unresolved")
+		if isResolved == resolved {
+			filtered = append(filtered, issue)
+		}
+	}
+	return filtered
+}
+
+// filterUpdatedSince keeps the issues updated on or after since, a YYYY-MM-DD date.
+// The comparison uses the date of Jira's updated timestamp as sent, so it follows the
+// time zone of the Jira user's profile.
+func filterUpdatedSince(issues []jira.Issue, since string) []jira.Issue {
+	filtered := make([]jira.Issue, 0, len(issues))
+	for _, issue := range issues {
+		if onOrAfterDate(issue.Updated, since) {
+			filtered = append(filtered, issue)
+		}
+	}
+	return filtered
+}
+
+// filterResolvedSince keeps the issues resolved on or after since, a YYYY-MM-DD date,
+// comparing dates the same way as filterUpdatedSince. Unresolved issues are dropped.
+func filterResolvedSince(issues []jira.Issue, since string) []jira.Issue {
+	filtered := make([]jira.Issue, 0, len(issues))
+	for _, issue := range issues {
+		if onOrAfterDate(issue.Resolved, since) {
+			filtered = append(filtered, issue)
+		}
+	}
+	return filtered
+}
+
+// onOrAfterDate reports whether value, a timestamp in ResolvedLayout or a YYYY-MM-DD
+// date, falls on or after the date since. Values that do not start with a date, such as
+// a resolution name, never do.
+func onOrAfterDate(value, since string) bool {
+	if len(value) < len(dateOnlyLayout) {
+		return false
+	}
+	day := value[:len(dateOnlyLayout)]
+	if _, err := time.Parse(dateOnlyLayout, day); err != nil {
+		return false
+	}
+	return day >= since
+}
+
+// -since-field values.
+const (
+	sinceFieldResolved = "resolved"
+	sinceFieldUpdated  = "updated"
+)
+
+// applySinceDays turns -since-days into the resolved or updated date filter, relative
+// to now. An updated window narrows any -updated-since date rather than replacing it.
+func applySinceDays(rf *reportFlags, flags *flag.FlagSet, now time.Time) error {
+	switch rf.sinceField = strings.ToLower(strings.TrimSpace(rf.sinceField)); {
+	case rf.sinceDays < 0:
+		return errors.New("-since-days cannot be negative")
+	case rf.sinceField != sinceFieldResolved && rf.sinceField != sinceFieldUpdated:
+		return fmt.Errorf("unknown -since-field %q (expected %s or %s)", rf.sinceField, sinceFieldResolved, sinceFieldUpdated)
+	case rf.sinceDays == 0:
+		if flagPassed(flags, "since-field") {
+			return errors.New("-since-field requires -since-days")
+		}
+		return nil
+	}
+
+	since := sinceDaysDate(rf.sinceDays, now)
+	if rf.sinceField == sinceFieldResolved {
+		rf.resolvedSince = since
+	} else if since > rf.updatedSince {
+		rf.updatedSince = since
+	}
+	return nil
+}
+
+// sinceDaysDate returns the first date of a -since-days window of days days ending at
+// now, so issues from exactly that many days ago are included.
+func sinceDaysDate(days int, now time.Time) string {
+	return now.AddDate(0, 0, -days).Format(dateOnlyLayout)
+}
+
+// dropLinks clears the issue and parent URLs for -no-links. Every output renders a key
+// without a URL as plain text, and the JSON outputs omit the empty url fields.
+func dropLinks(issues []jira.Issue) {
+	for i := range issues {
+		issues[i].URL, issues[i].ParentURL = "", ""
+	}
+}
+
+// filterCurrentSprint keeps the issues that belong to an active sprint.
+func filterCurrentSprint(issues []jira.Issue) []jira.Issue {
+	filtered := make([]jira.Issue, 0, len(issues))
+	for _, issue := range issues {
+		if strings.TrimSpace(issue.Sprint) != "" {
+			filtered = append(filtered, issue)
+		}
+	}
+	return filtered
+}
+
+// reportOptions carries the rendering choices parsed from the command line.
+type reportOptions struct {
+	// out receives the report; interactive is set when out is a terminal, which selects
+	// the clipboard paths of the docs, slides, and tabs modes.
+	out         io.Writer
+	interactive bool
+	// colors are the terminal table's status colors, nil when color is off.
+	colors statusColors
+	// hints enables the stderr pipe-into-pbcopy suggestions for non-terminal stdout.
+	hints   bool
+	summary summaryFormat
+	// summaryWidth truncates summaries in the selected format, 0 for no limit. The
+	// terminal table takes it from its summary column's width instead.
+	summaryWidth int
+	// slideBreak separates the slide status groups, empty for none.
+	slideBreak string
+	// groupBy groups slide bullets by status (the default) or assignee.
+	groupBy string
+	// numbered prepends the -numbered row number column to the tabular outputs.
+	numbered bool
+	// tableAttrs are the attributes of the docs and html outputs' <table> tag.
+	tableAttrs string
+	// docsWrapper, when set, is the -docs-template the docs and html tables are
+	// embedded in; filterName fills its {{.FilterName}}.
+	docsWrapper *docsWrapper
+	filterName  string
+	// countBy, when set, prints a tally of the column's values in place of the report,
+	// or after it when countReport is set.
+	countBy     *column
+	countReport bool
+	// digestKeys is the most keys per line of the digest format, 0 for no limit.
+	digestKeys int
+	// points adds a story points footer to the table and slides outputs.
+	points bool
+	// skipHeader omits the header row of delimited output when appending to a file.
+	skipHeader bool
+	// columns are the columns of the tabular outputs, chosen with -fields.
+	columns []column
+	// verifyCopy reads each clipboard copy back and treats an empty clipboard as a
+	// failed copy.
+	verifyCopy bool
+
+	format         reportFormat
+	xlsxPath       string
+	templateSource string
+	tableOrder     []sortKey
+	statusOrder    []sortKey
+}
+
+// renderReport writes issues in the selected output mode.
+func renderReport(issues []jira.Issue, opts reportOptions) error {
+	out := opts.out
+	if opts.countBy != nil {
+		if opts.countReport {
+			report := opts
+			report.countBy = nil
+			if err := renderReport(issues, report); err != nil {
+				return err
 			}
+			fmt.Fprintln(out)
+		}
+		fmt.Fprint(out, buildTally(issues, *opts.countBy, opts.summary))
+		return nil
+	}
+	switch opts.format {
+	case formatSlides, formatSlack, formatDigest:
+		sortIssues(issues, opts.statusOrder)
+	default:
+		sortIssues(issues, opts.tableOrder)
+	}
 
-			if err := copyToClipboard("html", []byte(tableHTML)); err != nil {
-				fmt.Println(tableHTML)
-				fmt.Fprintf(os.Stderr, "Warning: failed to copy table to clipboard (%v).\n", err)
-				fmt.Fprintln(os.Stderr, "Tip: run `wkreport -docs ... | pbcopy -Prefer html` manually.")
-			} else {
-				fmt.Fprintln(os.Stderr, "Table (HTML) copied to clipboard. If Google Docs shows raw markup, use Paste special > Paste HTML.")
+	if opts.xlsxPath != "" {
+		if err := writeXLSX(opts.xlsxPath, issues, opts.columns, opts.summary); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Wrote %d issue(s) to %s.\n", len(issues), opts.xlsxPath)
+		return nil
+	}
+
+	if opts.templateSource != "" {
+		rendered, err := renderTemplate(opts.templateSource, issues, opts.summary.ellipsis)
+		if err != nil {
+			return err
+		}
+		fmt.Fprint(out, rendered)
+		return nil
+	}
+
+	if opts.numbered {
+		opts.columns = append([]column{numberColumn}, opts.columns...)
+	}
+
+	switch opts.format {
+	case formatDocs:
+		return renderDocs(issues, opts)
+	case formatSlides:
+		return renderSlides(issues, opts)
+	case formatTSV, formatSheets:
+		return renderTabs(issues, opts)
+	case formatSlack:
+		fmt.Fprintln(out, buildSlack(issues, opts.summary, opts.summaryWidth))
+	case formatJSON:
+		payload, err := buildJSON(issues)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(out, payload)
+	case formatJSONL:
+		payload, err := buildJSONLines(issues)
+		if err != nil {
+			return err
+		}
+		fmt.Fprint(out, payload)
+	case formatCSV:
+		payload, err := buildCSV(issues, opts.columns, opts.summary, opts.summaryWidth)
+		if err != nil {
+			return err
+		}
+		if opts.skipHeader {
+			payload = dropFirstLine(payload)
+		}
+		fmt.Fprint(out, payload)
+	case formatMarkdown:
+		fmt.Fprint(out, buildMarkdown(issues, opts.columns, opts.summary, opts.summaryWidth))
+	case formatHTML:
+		tableHTML, err := opts.docsHTML(issues)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(out, tableHTML)
+	case formatConfluence:
+		fmt.Fprint(out, buildConfluence(issues, opts.columns, opts.summary, opts.summaryWidth))
+	case formatPorcelain:
+		fmt.Fprint(out, buildPorcelain(issues))
+	case formatBrief:
+		fmt.Fprint(out, buildBrief(issues, opts.summary, opts.summaryWidth))
+	case formatDigest:
+		fmt.Fprint(out, buildDigest(issues, opts.digestKeys))
+	default:
+		renderTable(issues, opts)
+	}
+	return nil
+}
+
+// docsHTML builds the docs and html outputs' table, embedded in the -docs-template
+// when there is one.
+func (o reportOptions) docsHTML(issues []jira.Issue) (string, error) {
+	tableHTML := buildDocsHTML(issues, o.columns, o.summary, o.summaryWidth, o.tableAttrs)
+	if o.docsWrapper == nil {
+		return tableHTML, nil
+	}
+	return o.docsWrapper.wrap(tableHTML, o.filterName)
+}
+
+// renderDocs copies a Google Docs table to the clipboard, or writes it to out.
+func renderDocs(issues []jira.Issue, opts reportOptions) error {
+	out := opts.out
+	tableHTML, err := opts.docsHTML(issues)
+	if err != nil {
+		return err
+	}
+
+	if opts.interactive {
+		htmlErr := opts.copyHTML(tableHTML)
+		if htmlErr == nil {
+			fmt.Fprintln(os.Stderr, "Google Docs table copied to clipboard. Paste directly into your document.")
+			return nil
+		}
+
+		// Fall back to converting through textutil and copying RTF.
+		if rtfPayload, err := convertHTMLToRTF(tableHTML); err == nil {
+			if err := opts.copy("rtf", rtfPayload); err == nil {
+				fmt.Fprintln(os.Stderr, "Google Docs table copied to clipboard as RTF. Paste directly into your document.")
+				return nil
 			}
-		} else {
-			if rtfErr == nil {
-				os.Stdout.Write(rtfPayload)
+		}
+
+		fmt.Fprintln(out, tableHTML)
+		warnClipboardFailure("table", htmlErr, "wkreport -format docs ... | pbcopy -Prefer html")
+	} else {
+		rtfPayload, rtfErr := convertHTMLToRTF(tableHTML)
+		if rtfErr == nil {
+			out.Write(rtfPayload)
+			if opts.hints {
 				fmt.Fprintln(os.Stderr, "Hint: pipe into `pbcopy -Prefer rtf` to preserve table formatting.")
-			} else {
-				fmt.Println(tableHTML)
+			}
+		} else {
+			fmt.Fprintln(out, tableHTML)
+			if opts.hints {
 				fmt.Fprintln(os.Stderr, "Hint: pipe into `pbcopy -Prefer html` to preserve table formatting.")
 			}
 		}
+	}
+	return nil
+}
+
+// renderSlides copies status-grouped slide bullets to the clipboard, or writes them to out.
+func renderSlides(issues []jira.Issue, opts reportOptions) error {
+	out := opts.out
+	groups := groupByStatus(issues)
+	var footers []string
+	if opts.groupBy == groupByAssigneeName {
+		groups = groupByAssignee(issues, opts.statusOrder)
+		footers = append(footers, groupCounts("assignee", groups))
+	}
+	if opts.points {
+		if footer := pointsFooter(issues, opts.statusOrder); footer != "" {
+			footers = append(footers, footer)
+		}
+	}
+	plainOutput, htmlContent := buildSlidesContent(groups, opts.summary, opts.summaryWidth, opts.slideBreak, footers)
+
+	if plainOutput == "" && htmlContent == "" {
+		fmt.Fprintln(out, "No slide content generated.")
 		return nil
 	}
 
-	if slidesOutput {
-		sortIssues(issues, true)
-		plainOutput, htmlContent := buildSlidesContent(issues)
+	if opts.interactive {
+		htmlErr := opts.copyHTML(htmlContent)
+		if htmlErr == nil {
+			fmt.Fprintln(os.Stderr, "Slides summary copied to clipboard with formatting. Paste directly into your slide notes or text box.")
+			return nil
+		}
+
+		// Fall back to converting through textutil and copying RTF.
+		rtfPayload, rtfErr := convertHTMLToRTF(htmlContent)
+		if rtfErr == nil {
+			if err := opts.copy("rtf", rtfPayload); err == nil {
+				fmt.Fprintln(os.Stderr, "Slides summary copied to clipboard as RTF. Paste directly into your slide notes or text box.")
+				return nil
+			}
+		}
+
+		warnClipboardFailure("slides summary", htmlErr, "wkreport -format slides ... | pbcopy -Prefer html")
+		fmt.Fprintln(out, plainOutput)
+	} else {
+		rtfPayload, rtfErr := convertHTMLToRTF(htmlContent)
+		if rtfErr == nil {
+			if _, err := out.Write(rtfPayload); err != nil {
+				fmt.Fprintf(os.Stderr, "Error writing slides RTF payload: %v\n", err)
+				return nil
+			}
+			if opts.hints {
+				fmt.Fprintln(os.Stderr, "Hint: pipe into `pbcopy -Prefer rtf` to preserve hyperlinks in slides.")
+			}
+		} else {
+			fmt.Fprintln(out, htmlContent)
+			if opts.hints {
+				fmt.Fprintln(os.Stderr, "Hint: pipe into `pbcopy -Prefer html` to preserve hyperlinks in slides.")
+			}
+		}
+		return nil
+	}
+
+	return nil
+}
+
+// renderTabs copies tab-separated rows to the clipboard, or writes them to out.
+func renderTabs(issues []jira.Issue, opts reportOptions) error {
+	out := opts.out
+	tabContent := buildTabDelimited(issues, opts.columns, opts.summary, opts.summaryWidth)
+	if opts.format == formatSheets {
+		tabContent = buildSheets(issues, opts.columns, opts.summary, opts.summaryWidth)
+	}
+	if opts.skipHeader {
+		tabContent = dropFirstLine(tabContent)
+	}
+	if opts.interactive {
+		if err := opts.copy("", []byte(tabContent)); err == nil {
+			fmt.Fprintln(os.Stderr, "Tab-delimited report copied to clipboard. Paste into your spreadsheet or text editor.")
+			return nil
+		} else {
+			fmt.Fprint(out, tabContent)
+			warnClipboardFailure("tab-delimited report", err, fmt.Sprintf("wkreport -format %s ... | pbcopy", opts.format))
+		}
+	} else {
+		fmt.Fprint(out, tabContent)
+		if opts.hints {
+			fmt.Fprintln(os.Stderr, "Hint: pipe into `pbcopy` to copy the tab-delimited report.")
+		}
+	}
+	return nil
+}
+
+// renderTable writes the fixed-width terminal table.
+func renderTable(issues []jira.Issue, opts reportOptions) {
+	out := opts.out
+	writeTableHeader(out, opts.columns)
+	for i, issue := range issues {
+		writeTableRow(out, issue, i+1, opts.columns, opts.summary, opts.colors)
+	}
+	writeTableFooter(issues, opts)
+}
+
+func writeTableHeader(out io.Writer, columns []column) {
+	cells := make([]string, len(columns))
+	for i, col := range columns {
+		cells[i] = fmt.Sprintf("%-*s", col.width, col.header())
+	}
+	fmt.Fprintln(out, strings.Join(cells, " "))
+}
+
+func writeTableRow(out io.Writer, issue jira.Issue, row int, columns []column, sf summaryFormat, colors statusColors) {
+	cells := make([]string, len(columns))
+	for i, col := range columns {
+		value := col.value(issue, row, sf, col.width)
+		switch {
+		case col.name == "status":
+			cells[i] = formatStatus(value, col.width, sf.ellipsis, colors)
+			continue
+		case col.fit:
+			value = truncate(value, col.width, sf.ellipsis)
+		}
+		cells[i] = fmt.Sprintf("%-*s", col.width, value)
+	}
+	fmt.Fprintln(out, strings.Join(cells, " "))
+}
+
+func writeTableFooter(issues []jira.Issue, opts reportOptions) {
+	if opts.points {
+		if footer := pointsFooter(issues, opts.statusOrder); footer != "" {
+			fmt.Fprintf(opts.out, "\n%s\n", footer)
+		}
+	}
+}
+
+// openReportFile opens the -o destination. In append mode, delimited output skips its
+// header when the file already has content, and other modes get a timestamped separator.
+func openReportFile(path string, appendMode bool, opts *reportOptions) (*os.File, error) {
+	if !appendMode {
+		file, err := os.Create(path)
+		if err != nil {
+			return nil, fmt.Errorf("create output file: %w", err)
+		}
+		return file, nil
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("open output file: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("stat output file: %w", err)
+	}
+	nonEmpty := info.Size() > 0
+
+	switch {
+	case opts.format == formatTSV || opts.format == formatSheets || opts.format == formatCSV:
+		opts.skipHeader = nonEmpty
+	case opts.format == formatJSON || opts.format == formatJSONL:
+		// Separators would corrupt machine-readable output.
+	default:
+		if nonEmpty {
+			fmt.Fprintln(file)
+		}
+		fmt.Fprintf(file, "===== %s =====\n", time.Now().Format("2006-01-02 15:04"))
+	}
+	return file, nil
+}
+
+func dropFirstLine(content string) string {
+	if i := strings.IndexByte(content, '\n'); i >= 0 {
+		return content[i+1:]
+	}
+	return ""
+}
+
+// flagPassed reports whether the named flag was given explicitly on the command line.
+func flagPassed(flags *flag.FlagSet, name string) bool {
+	passed := false
+	flags.Visit(func(f *flag.Flag) {
+		passed = passed || f.Name == name
+	})
+	return passed
+}
+
+// modeFlagSet reports whether an output mode flag was given explicitly on the command line.
+func modeFlagSet(flags *flag.FlagSet) bool {
+	set := false
+	flags.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "format", "tabs", "docs", "slides", "slack", "json", "jsonl", "brief", "digest", "sheets", "porcelain", "template", "xlsx":
+			set = true
+		}
+	})
+	return set
+}
+
+// newJiraClient creates a client using the configured authentication scheme.
+func newJiraClient(cfg *config.Config, opts ...jira.Option) (*jira.Client, error) {
+	userAgent := cfg.Jira.UserAgent
+	if userAgent == "" {
+		userAgent = "wkreport/" + version
+	}
+	opts = append([]jira.Option{
+		jira.WithUserAgent(userAgent),
+		jira.WithBrowseURL(cfg.Jira.BrowseURL),
+		jira.WithPageSize(cfg.Jira.PageSize),
+		jira.WithEnhancedSearch(cfg.Jira.EnhancedSearch),
+		jira.WithAtlassianTokenNoCheck(cfg.Jira.AtlassianTokenNoCheck),
+		jira.WithConnPool(jira.ConnPool{
+			MaxIdleConnsPerHost: cfg.Jira.MaxIdleConnsPerHost,
+			MaxConnsPerHost:     cfg.Jira.MaxConnsPerHost,
+			IdleConnTimeout:     cfg.Jira.IdleConnTimeout,
+		}),
+	}, opts...)
+	if cfg.Jira.AuthType == config.AuthOAuth {
+		return jira.NewOAuthClient(cfg.Jira.URL, jira.OAuthCredentials{
+			AccessToken:  cfg.Jira.AccessToken,
+			RefreshToken: cfg.Jira.RefreshToken,
+			ClientID:     cfg.Jira.ClientID,
+			ClientSecret: cfg.Jira.ClientSecret,
+			TokenURL:     cfg.Jira.TokenURL,
+		}, opts...)
+	}
+	return jira.NewClient(cfg.Jira.URL, cfg.Jira.Email, cfg.Jira.APIToken, opts...)
+}
+
+// logResolvedFilter notes which filter a name resolved to, for -debug, so a same-named
+// filter owned by someone else is easy to spot. Numeric ids are not logged.
+func logResolvedFilter(ref string, filter *jira.Filter) {
+	if id, err := strconv.Atoi(ref); err == nil && id == filter.ID {
+		return
+	}
+	owner := filter.Owner
+	if owner == "" {
+		owner = "unknown owner"
+	}
+	fmt.Fprintf(os.Stderr, "Resolved filter %q to %d (%s, owned by %s)\n", ref, filter.ID, filter.Name, owner)
+}
+
+// printClientStats reports the Jira traffic of a run, for -debug.
+func printClientStats(client *jira.Client) {
+	stats := client.Stats()
+	noun := "requests"
+	if stats.Requests == 1 {
+		noun = "request"
+	}
+	fmt.Fprintf(os.Stderr, "Made %d %s in %.1fs (%.1f KB received)\n", stats.Requests, noun, stats.Duration.Seconds(), float64(stats.Bytes)/1024)
+}
 
-		if plainOutput == "" && htmlContent == "" {
-			fmt.Println("No slide content generated.")
-			return nil
-		}
+// contextError explains err in terms of why ctx ended: the -deadline budget ran out or
+// the run was interrupted by a signal.
+func contextError(ctx context.Context, deadline time.Duration, err error) error {
+	if err == nil {
+		return nil
+	}
+	if deadline > 0 && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("report exceeded -deadline of %s: %w", deadline, err)
+	}
+	if errors.Is(ctx.Err(), context.Canceled) && !errors.Is(err, jira.ErrInterrupted) {
+		return fmt.Errorf("%w: %w", jira.ErrInterrupted, err)
+	}
+	return err
+}
 
-		rtfPayload, rtfErr := convertHTMLToRTF(htmlContent)
-		if isTerminal(os.Stdout) {
-			copied := false
-
-			if rtfErr == nil {
-				if err := copyToClipboard("rtf", rtfPayload); err == nil {
-					fmt.Fprintln(os.Stderr, "Slides summary copied to clipboard with formatting. Paste directly into your slide notes or text box.")
-					copied = true
-				} else {
-					fmt.Fprintf(os.Stderr, "Warning: failed to copy slides summary as RTF (%v).\n", err)
-				}
-			}
+// maxFailureLines caps the per-error lines of reportFetchFailures.
+const maxFailureLines = 10
 
-			if !copied {
-				if err := copyToClipboard("html", []byte(htmlContent)); err == nil {
-					fmt.Fprintln(os.Stderr, "Slides summary copied as HTML to clipboard. Paste directly into your slide notes or text box.")
-					copied = true
-				} else {
-					fmt.Fprintf(os.Stderr, "Warning: failed to copy slides summary to clipboard (%v).\n", err)
-					fmt.Fprintln(os.Stderr, "Tip: run `wkreport -slides ... | pbcopy -Prefer html` manually.")
-				}
-			}
+// reportFetchFailures prints a summary of the issues skipped in best-effort mode.
+// Failures with the same error are printed once with their issue ids, and at most
+// maxFailureLines errors are listed.
+func reportFetchFailures(partial *jira.PartialResultError) {
+	fmt.Fprintf(os.Stderr, "Warning: %d issue(s) could not be fetched and were skipped:\n", len(partial.Failures))
 
-			if rtfErr != nil {
-				fmt.Fprintf(os.Stderr, "Warning: unable to convert slides summary to RTF (%v). Falling back to HTML clipboard behavior.\n", rtfErr)
-			}
+	type failureGroup struct {
+		message string
+		ids     []string
+	}
+	var groups []*failureGroup
+	byMessage := make(map[string]*failureGroup)
+	for _, failure := range partial.Failures {
+		// Errors name the issue they came from; mask it so that identical causes group.
+		message := strings.ReplaceAll(failure.Err.Error(), "issue "+failure.ID, "issue *")
+		group, ok := byMessage[message]
+		if !ok {
+			group = &failureGroup{message: message}
+			byMessage[message] = group
+			groups = append(groups, group)
+		}
+		group.ids = append(group.ids, failure.ID)
+	}
 
-			if !copied {
-				fmt.Println(plainOutput)
-			}
+	printed := 0
+	for i, group := range groups {
+		if i == maxFailureLines {
+			fmt.Fprintf(os.Stderr, "  (and %d more)\n", len(partial.Failures)-printed)
+			break
+		}
+		if len(group.ids) == 1 {
+			fmt.Fprintf(os.Stderr, "  %s: %s\n", group.ids[0], strings.ReplaceAll(group.message, "issue *", "issue "+group.ids[0]))
 		} else {
-			if rtfErr == nil {
-				if _, err := os.Stdout.Write(rtfPayload); err != nil {
-					fmt.Fprintf(os.Stderr, "Error writing slides RTF payload: %v\n", err)
-					return nil
-				}
-				fmt.Fprintln(os.Stderr, "Hint: pipe into `pbcopy -Prefer rtf` to preserve hyperlinks in slides.")
-			} else {
-				fmt.Println(htmlContent)
-				fmt.Fprintln(os.Stderr, "Hint: pipe into `pbcopy -Prefer html` to preserve hyperlinks in slides.")
-			}
-			return nil
+			fmt.Fprintf(os.Stderr, "  %d issues (%s): %s\n", len(group.ids), abbreviateIDs(group.ids, 5), group.message)
 		}
+		printed += len(group.ids)
+	}
+}
 
-		return nil
+// abbreviateIDs joins up to limit ids, noting how many were left out.
+func abbreviateIDs(ids []string, limit int) string {
+	if len(ids) <= limit {
+		return strings.Join(ids, ", ")
 	}
+	return fmt.Sprintf("%s, and %d more", strings.Join(ids[:limit], ", "), len(ids)-limit)
+}
 
-	sortIssues(issues, false)
+// stdinFilterRef is the -f value that reads the filter identifier from stdin.
+const stdinFilterRef = "-"
 
-	if tabDelimited {
-		tabContent := buildTabDelimited(issues)
-		if isTerminal(os.Stdout) {
-			if err := copyToClipboard("", []byte(tabContent)); err == nil {
-				fmt.Fprintln(os.Stderr, "Tab-delimited report copied to clipboard. Paste into your spreadsheet or text editor.")
-				return nil
-			} else {
-				fmt.Print(tabContent)
-				fmt.Fprintf(os.Stderr, "Warning: failed to copy tab-delimited report to clipboard (%v).\n", err)
-				fmt.Fprintln(os.Stderr, "Tip: run `wkreport -tabs ... | pbcopy` manually.")
-			}
-		} else {
-			fmt.Print(tabContent)
-			fmt.Fprintln(os.Stderr, "Hint: pipe into `pbcopy` to copy the tab-delimited report.")
-		}
-		return nil
-	} else {
-		fmt.Printf("%-12s %-150s %-20s %-12s %-16s\n", "KEY", "SUMMARY", "STATUS", "PARENT", "RESOLVED")
-		for _, issue := range issues {
-			rawSummary := truncate(issue.Summary, 150)
-			parent := strings.TrimSpace(issue.Parent)
-			displaySummary := rawSummary
-			if parent != "" {
-				displaySummary = truncate(fmt.Sprintf("%s / %s", parent, rawSummary), 150)
-			}
-			parentCol := truncate(parent, 12)
-			fmt.Printf("%-12s %-150s %-20s %-12s %-16s\n", issue.Key, displaySummary, issue.Status, parentCol, issue.Resolved)
-		}
+// readFilterRef reads a filter identifier for -f - from the first line of in.
+func readFilterRef(in io.Reader) (string, error) {
+	line, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return "", fmt.Errorf("read filter identifier from stdin: %w", err)
 	}
-
-	return nil
+	ref := strings.TrimSpace(line)
+	if ref == "" {
+		return "", errors.New("-f - found no filter identifier on stdin")
+	}
+	return ref, nil
 }
 
 func normalizeFilterFlag(args []string) []string {
@@ -222,74 +1648,111 @@ func normalizeFilterFlag(args []string) []string {
 	return out
 }
 
-func truncate(input string, width int) string {
-	if len([]rune(input)) <= width {
+// Ellipsis styles accepted by -ellipsis and output.ellipsis.
+const (
+	asciiEllipsis   = "..."
+	unicodeEllipsis = "…"
+)
+
+// truncate shortens input to at most width runes, ending in the ellipsis marker when
+// cut. A width of 0 or less leaves input whole; one too narrow for the marker cuts
+// without it.
+func truncate(input string, width int, ellipsis string) string {
+	if width <= 0 || len([]rune(input)) <= width {
 		return input
 	}
 	runes := []rune(input)
-	if width <= 3 {
+	marker := []rune(ellipsis)
+	if width <= len(marker) {
 		return string(runes[:width])
 	}
-	return string(runes[:width-3]) + "..."
+	return string(runes[:width-len(marker)]) + ellipsis
+}
+
+// parseEllipsis maps an -ellipsis style name to its marker.
+func parseEllipsis(style string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(style)) {
+	case "", "ascii":
+		return asciiEllipsis, nil
+	case "unicode":
+		return unicodeEllipsis, nil
+	default:
+		return "", fmt.Errorf("unknown ellipsis style %q (expected ascii or unicode)", style)
+	}
+}
+
+// defaultTableStyle keeps the bordered table, which pastes cleanly into Google Docs.
+const defaultTableStyle = "bordered"
+
+// tableStyles maps the -table-style names onto the attributes of the <table> tag. plain
+// leaves the table unstyled for styling downstream.
+var tableStyles = map[string]string{
+	"bordered": ` border="1" cellspacing="0" cellpadding="4"`,
+	"minimal":  ` cellspacing="0" cellpadding="4"`,
+	"plain":    "",
 }
 
-func buildDocsHTML(issues []jira.Issue) string {
+// buildDocsHTML renders issues as an HTML table whose tag carries attrs, truncating
+// summaries to width.
+func buildDocsHTML(issues []jira.Issue, columns []column, sf summaryFormat, width int, attrs string) string {
 	var b strings.Builder
-	b.WriteString("<table border=\"1\" cellspacing=\"0\" cellpadding=\"4\">\n")
-	b.WriteString("  <tr><td>KEY</td><td>SUMMARY</td><td>STATUS</td><td>PARENT</td><td>RESOLVED</td></tr>\n")
-	for _, issue := range issues {
-		key := html.EscapeString(issue.Key)
+	b.WriteString("<table" + attrs + ">\n")
+	b.WriteString("  <tr>")
+	for _, header := range columnHeaders(columns) {
+		b.WriteString("<td>" + html.EscapeString(header) + "</td>")
+	}
+	b.WriteString("</tr>\n")
+	for row, issue := range issues {
 		url := html.EscapeString(strings.TrimSpace(issue.URL))
-		rawSummary := truncate(issue.Summary, 150)
-		parentSource := strings.TrimSpace(issue.Parent)
-		if parentSource != "" {
-			rawSummary = truncate(fmt.Sprintf("%s / %s", parentSource, rawSummary), 150)
-		}
-		summary := html.EscapeString(rawSummary)
-		status := html.EscapeString(issue.Status)
-		parent := html.EscapeString(strings.TrimSpace(issue.Parent))
-		resolved := html.EscapeString(issue.Resolved)
 		b.WriteString("  <tr>")
-		b.WriteString("<td>")
-		if url != "" {
-			b.WriteString("<a href=\"")
-			b.WriteString(url)
-			b.WriteString("\">")
-			b.WriteString(key)
-			b.WriteString("</a>")
-		} else {
-			b.WriteString(key)
+		for i, value := range columnValues(issue, row+1, columns, sf, width) {
+			b.WriteString("<td>")
+			if columns[i].name == "key" && url != "" {
+				b.WriteString("<a href=\"")
+				b.WriteString(url)
+				b.WriteString("\">")
+				b.WriteString(html.EscapeString(value))
+				b.WriteString("</a>")
+			} else {
+				b.WriteString(html.EscapeString(value))
+			}
+			b.WriteString("</td>")
 		}
-		b.WriteString("</td><td>")
-		b.WriteString(summary)
-		b.WriteString("</td><td>")
-		b.WriteString(status)
-		b.WriteString("</td><td>")
-		b.WriteString(parent)
-		b.WriteString("</td><td>")
-		b.WriteString(resolved)
-		b.WriteString("</td></tr>\n")
+		b.WriteString("</tr>\n")
 	}
 	b.WriteString("</table>")
 	return b.String()
 }
 
-func buildTabDelimited(issues []jira.Issue) string {
+func buildTabDelimited(issues []jira.Issue, columns []column, sf summaryFormat, width int) string {
 	var b strings.Builder
-	b.WriteString("KEY\tSUMMARY\tSTATUS\tPARENT\tRESOLVED\n")
-	for _, issue := range issues {
-		parent := strings.TrimSpace(issue.Parent)
-		summary := truncate(issue.Summary, 150)
-		if parent != "" {
-			summary = truncate(fmt.Sprintf("%s / %s", parent, summary), 150)
-		}
-		fmt.Fprintf(&b, "%s\t%s\t%s\t%s\t%s\n", issue.Key, summary, issue.Status, parent, issue.Resolved)
+	b.WriteString(strings.Join(columnHeaders(columns), "\t") + "\n")
+	for i, issue := range issues {
+		b.WriteString(strings.Join(columnValues(issue, i+1, columns, sf, width), "\t") + "\n")
 	}
 	return b.String()
 }
 
-func buildSlidesContent(issues []jira.Issue) (string, string) {
-	if len(issues) == 0 {
+// slideBreakFormFeed is the -slide-break value that selects a form feed separator.
+const slideBreakFormFeed = "ff"
+
+// slideSeparator returns the -slide-break separator line for value.
+func slideSeparator(value string) string {
+	if strings.EqualFold(strings.TrimSpace(value), slideBreakFormFeed) {
+		return "\f"
+	}
+	return value
+}
+
+// slidePageBreak is the HTML between slide status groups when a slide break is set.
+const slidePageBreak = "<div style=\"page-break-after: always\"></div>\n"
+
+// buildSlidesContent renders grouped bullets as plain text and HTML, truncating each
+// bullet's summary to width characters. A non-empty slideBreak replaces the blank line
+// between groups, and the HTML gets a page break there. Each footer line is appended as
+// a closing paragraph.
+func buildSlidesContent(groups []issueGroup, sf summaryFormat, width int, slideBreak string, footers []string) (string, string) {
+	if len(groups) == 0 {
 		return "", ""
 	}
 
@@ -298,102 +1761,283 @@ func buildSlidesContent(issues []jira.Issue) (string, string) {
 
 	htmlBuilder.WriteString("<html><body>\n")
 
-	currentStatus := ""
-	firstStatus := true
-
-	for _, issue := range issues {
-		status := strings.TrimSpace(issue.Status)
-		if status == "" {
-			status = "Unknown"
+	for i, group := range groups {
+		if i > 0 {
+			if slideBreak != "" {
+				plain.WriteString(slideBreak)
+				htmlBuilder.WriteString(slidePageBreak)
+			}
+			plain.WriteString("\n")
 		}
+		plain.WriteString(group.name)
+		plain.WriteString("\n")
 
-		if status != currentStatus {
-			if !firstStatus {
-				htmlBuilder.WriteString("</ul>\n")
-				plain.WriteString("\n")
+		htmlBuilder.WriteString("<h2>")
+		htmlBuilder.WriteString(html.EscapeString(group.name))
+		htmlBuilder.WriteString("</h2>\n<ul>\n")
+
+		for _, node := range nestByParent(group.issues) {
+			if node.issue != nil {
+				writeSlideItem(&plain, &htmlBuilder, "", node.issue.Key, node.issue.URL, sf.summary(*node.issue, width))
+			} else {
+				writeSlideItem(&plain, &htmlBuilder, "", node.key, node.url, truncate(node.summary, width, sf.ellipsis))
+			}
+			if len(node.children) == 0 {
+				htmlBuilder.WriteString("</li>\n")
+				continue
 			}
-			firstStatus = false
-			currentStatus = status
 
-			if plain.Len() > 0 {
-				plain.WriteString("\n")
+			htmlBuilder.WriteString("\n    <ul>\n")
+			for _, child := range node.children {
+				// The parent bullet already names the parent, so children skip the prefix.
+				summary := truncate(strings.TrimSpace(child.Summary), width, sf.ellipsis)
+				writeSlideItem(&plain, &htmlBuilder, "  ", child.Key, child.URL, summary)
+				htmlBuilder.WriteString("</li>\n")
 			}
-			plain.WriteString(status)
+			htmlBuilder.WriteString("    </ul>\n  </li>\n")
+		}
+		htmlBuilder.WriteString("</ul>\n")
+	}
+	for i, footer := range footers {
+		if i == 0 {
 			plain.WriteString("\n")
-
-			htmlBuilder.WriteString("<h2>")
-			htmlBuilder.WriteString(html.EscapeString(status))
-			htmlBuilder.WriteString("</h2>\n<ul>\n")
 		}
+		plain.WriteString(footer)
+		plain.WriteString("\n")
+		htmlBuilder.WriteString("<p>")
+		htmlBuilder.WriteString(html.EscapeString(footer))
+		htmlBuilder.WriteString("</p>\n")
+	}
+	htmlBuilder.WriteString("</body></html>")
 
-		key := strings.TrimSpace(issue.Key)
-		rawSummary := truncate(strings.TrimSpace(issue.Summary), 150)
-		parentSource := strings.TrimSpace(issue.Parent)
-		if parentSource != "" {
-			rawSummary = truncate(fmt.Sprintf("%s / %s", parentSource, rawSummary), 150)
-		}
-		summary := rawSummary
-		url := strings.TrimSpace(issue.URL)
+	return strings.TrimRight(plain.String(), "\n"), htmlBuilder.String()
+}
+
+// writeSlideItem writes one bullet to both slide outputs. The HTML <li> is left open so
+// callers can nest a child list inside it.
+func writeSlideItem(plain, htmlBuilder *strings.Builder, indent, key, url, summary string) {
+	key = strings.TrimSpace(key)
+	url = strings.TrimSpace(url)
+
+	plain.WriteString(indent)
+	plain.WriteString("- ")
+	plain.WriteString(key)
+	if summary != "" {
+		plain.WriteString(": ")
+		plain.WriteString(summary)
+	}
+	plain.WriteString("\n")
+
+	htmlBuilder.WriteString("  ")
+	htmlBuilder.WriteString(strings.Repeat("  ", len(indent)))
+	htmlBuilder.WriteString("<li>")
+	if url != "" {
+		htmlBuilder.WriteString("<a href=\"")
+		htmlBuilder.WriteString(html.EscapeString(url))
+		htmlBuilder.WriteString("\">")
+		htmlBuilder.WriteString(html.EscapeString(key))
+		htmlBuilder.WriteString("</a>")
+	} else {
+		htmlBuilder.WriteString(html.EscapeString(key))
+	}
+	if summary != "" {
+		htmlBuilder.WriteString(": ")
+		htmlBuilder.WriteString(html.EscapeString(summary))
+	}
+}
+
+// issueGroup is a run of consecutive issues sharing a status or assignee.
+type issueGroup struct {
+	name   string
+	issues []jira.Issue
+}
 
-		plain.WriteString("- ")
-		plain.WriteString(key)
-		if summary != "" {
-			plain.WriteString(": ")
-			plain.WriteString(summary)
+// groupIssues splits sorted issues into runs sharing value, naming blank values blank.
+func groupIssues(issues []jira.Issue, value func(jira.Issue) string, blank string) []issueGroup {
+	var groups []issueGroup
+	for _, issue := range issues {
+		name := strings.TrimSpace(value(issue))
+		if name == "" {
+			name = blank
 		}
-		plain.WriteString("\n")
+		if len(groups) == 0 || groups[len(groups)-1].name != name {
+			groups = append(groups, issueGroup{name: name})
+		}
+		last := &groups[len(groups)-1]
+		last.issues = append(last.issues, issue)
+	}
+	return groups
+}
 
-		htmlBuilder.WriteString("  <li>")
-		if url != "" {
-			htmlBuilder.WriteString("<a href=\"")
-			htmlBuilder.WriteString(html.EscapeString(url))
-			htmlBuilder.WriteString("\">")
-			htmlBuilder.WriteString(html.EscapeString(key))
-			htmlBuilder.WriteString("</a>")
+// groupByStatus splits status-sorted issues into groups, naming blank statuses "Unknown".
+func groupByStatus(issues []jira.Issue) []issueGroup {
+	return groupIssues(issues, func(issue jira.Issue) string { return issue.Status }, "Unknown")
+}
+
+// Values accepted by -group-by.
+const (
+	groupByStatusName   = "status"
+	groupByAssigneeName = "assignee"
+)
+
+// unassignedGroup names the -group-by assignee group of issues without an assignee.
+const unassignedGroup = "Unassigned"
+
+// groupByAssignee groups issues by assignee in alphabetical order, with the unassigned
+// issues last. Within a group, issues keep statusOrder.
+func groupByAssignee(issues []jira.Issue, statusOrder []sortKey) []issueGroup {
+	sorted := append([]jira.Issue(nil), issues...)
+	sortIssues(sorted, append([]sortKey{{field: "assignee"}}, statusOrder...))
+	assigned := make([]jira.Issue, 0, len(sorted))
+	var unassigned []jira.Issue
+	for _, issue := range sorted {
+		if strings.TrimSpace(issue.Assignee) == "" {
+			unassigned = append(unassigned, issue)
 		} else {
-			htmlBuilder.WriteString(html.EscapeString(key))
+			assigned = append(assigned, issue)
 		}
-		if summary != "" {
-			htmlBuilder.WriteString(": ")
-			htmlBuilder.WriteString(html.EscapeString(summary))
+	}
+	return groupIssues(append(assigned, unassigned...), func(issue jira.Issue) string { return issue.Assignee }, unassignedGroup)
+}
+
+// groupCounts summarizes the number of issues in each group, as a line such as
+// "Issues by assignee: Ann Bo: 2, Unassigned: 1".
+func groupCounts(what string, groups []issueGroup) string {
+	counts := make([]string, len(groups))
+	for i, group := range groups {
+		counts[i] = fmt.Sprintf("%s: %d", group.name, len(group.issues))
+	}
+	return fmt.Sprintf("Issues by %s: %s", what, strings.Join(counts, ", "))
+}
+
+// slideNode is a top-level slide bullet: an issue, or a parent that is not itself in
+// the group, with the group's children of that parent nested beneath it.
+type slideNode struct {
+	issue    *jira.Issue
+	key      string
+	url      string
+	summary  string
+	children []jira.Issue
+}
+
+// nestByParent arranges a status group's issues under their parents, keeping the
+// order in which each parent or unparented issue first appears.
+func nestByParent(issues []jira.Issue) []*slideNode {
+	inGroup := make(map[string]bool, len(issues))
+	for _, issue := range issues {
+		inGroup[strings.TrimSpace(issue.Key)] = true
+	}
+
+	var nodes []*slideNode
+	byKey := make(map[string]*slideNode)
+	nodeFor := func(key string) *slideNode {
+		node, ok := byKey[key]
+		if !ok {
+			node = &slideNode{key: key}
+			byKey[key] = node
+			nodes = append(nodes, node)
 		}
-		htmlBuilder.WriteString("</li>\n")
+		return node
 	}
 
-	if !firstStatus {
-		htmlBuilder.WriteString("</ul>\n")
+	for i := range issues {
+		issue := issues[i]
+		parent := strings.TrimSpace(issue.Parent)
+		if parent == "" || parent == strings.TrimSpace(issue.Key) {
+			nodeFor(strings.TrimSpace(issue.Key)).issue = &issues[i]
+			continue
+		}
+
+		node := nodeFor(parent)
+		node.children = append(node.children, issue)
+		if !inGroup[parent] && node.url == "" && node.summary == "" {
+			node.url = strings.TrimSpace(issue.ParentURL)
+			node.summary = strings.TrimSpace(issue.ParentSummary)
+		}
 	}
-	htmlBuilder.WriteString("</body></html>")
+	return nodes
+}
 
-	return strings.TrimRight(plain.String(), "\n"), htmlBuilder.String()
+func buildJSON(issues []jira.Issue) (string, error) {
+	data, err := json.MarshalIndent(issues, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("encode issues: %w", err)
+	}
+	return string(data), nil
 }
 
-func sortIssues(issues []jira.Issue, byStatus bool) {
-	if byStatus {
-		sort.SliceStable(issues, func(i, j int) bool {
-			statusI := strings.TrimSpace(strings.ToLower(issues[i].Status))
-			statusJ := strings.TrimSpace(strings.ToLower(issues[j].Status))
-			if statusI == statusJ {
-				return strings.TrimSpace(strings.ToLower(issues[i].Key)) < strings.TrimSpace(strings.ToLower(issues[j].Key))
-			}
-			return statusI < statusJ
-		})
-		return
+// buildJSONLines encodes each issue as a compact JSON object on its own line, using the
+// same field names as buildJSON.
+func buildJSONLines(issues []jira.Issue) (string, error) {
+	var b strings.Builder
+	for _, issue := range issues {
+		data, err := json.Marshal(issue)
+		if err != nil {
+			return "", fmt.Errorf("encode issue %s: %w", issue.Key, err)
+		}
+		b.Write(data)
+		b.WriteString("\n")
 	}
+	return b.String(), nil
+}
+
+// buildSlack renders issues as Slack mrkdwn grouped by status. Issues must already be
+// sorted by status so each group is contiguous.
+func buildSlack(issues []jira.Issue, sf summaryFormat, width int) string {
+	var b strings.Builder
+	currentStatus := ""
+
+	for i, issue := range issues {
+		status := strings.TrimSpace(issue.Status)
+		if status == "" {
+			status = "Unknown"
+		}
 
-	sort.SliceStable(issues, func(i, j int) bool {
-		parentI := strings.TrimSpace(strings.ToLower(issues[i].Parent))
-		parentJ := strings.TrimSpace(strings.ToLower(issues[j].Parent))
-		if parentI == parentJ {
-			statusI := strings.TrimSpace(strings.ToLower(issues[i].Status))
-			statusJ := strings.TrimSpace(strings.ToLower(issues[j].Status))
-			if statusI == statusJ {
-				return strings.TrimSpace(strings.ToLower(issues[i].Key)) < strings.TrimSpace(strings.ToLower(issues[j].Key))
+		if i == 0 || status != currentStatus {
+			if i > 0 {
+				b.WriteString("\n")
 			}
-			return statusI < statusJ
+			currentStatus = status
+			b.WriteString("*")
+			b.WriteString(escapeSlack(status))
+			b.WriteString("*\n")
 		}
-		return parentI < parentJ
-	})
+
+		key := escapeSlack(strings.TrimSpace(issue.Key))
+		rawSummary := sf.summary(issue, width)
+		url := strings.TrimSpace(issue.URL)
+
+		b.WriteString("• ")
+		if url != "" {
+			b.WriteString("<")
+			b.WriteString(escapeSlack(url))
+			b.WriteString("|")
+			b.WriteString(key)
+			b.WriteString(">")
+		} else {
+			b.WriteString(key)
+		}
+		if rawSummary != "" {
+			b.WriteString(": ")
+			b.WriteString(escapeSlack(rawSummary))
+		}
+		b.WriteString("\n")
+	}
+
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// escapeSlack escapes the three characters Slack treats as control sequences in mrkdwn.
+func escapeSlack(input string) string {
+	replacer := strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+	return replacer.Replace(input)
+}
+
+// copiesToClipboard reports whether the docs, slides, and tabs outputs go to the
+// clipboard instead of stdout: only when stdout is a terminal, -no-clipboard is not
+// set, and the report is neither redrawn by -watch nor split into -sectioned parts.
+func copiesToClipboard(stdout *os.File, rf *reportFlags) bool {
+	return isTerminal(stdout) && !rf.noClipboard && rf.watch == 0 && !rf.sectioned
 }
 
 func isTerminal(f *os.File) bool {
@@ -404,6 +2048,63 @@ func isTerminal(f *os.File) bool {
 	return (info.Mode() & os.ModeCharDevice) != 0
 }
 
+// missingToolError reports an external helper that is not installed.
+type missingToolError struct {
+	name string
+}
+
+func (e *missingToolError) Error() string {
+	return e.name + " not found"
+}
+
+// lookTool resolves an external helper on PATH so a missing tool surfaces as a
+// readable error instead of a raw exec failure.
+func lookTool(name string) (string, error) {
+	path, err := exec.LookPath(name)
+	if err != nil {
+		return "", &missingToolError{name: name}
+	}
+	return path, nil
+}
+
+// warnClipboardFailure explains why a clipboard copy failed after the report has been
+// printed to stdout instead. The manual tip is skipped when the helper is not installed.
+func warnClipboardFailure(what string, err error, tip string) {
+	var missing *missingToolError
+	if errors.As(err, &missing) {
+		fmt.Fprintf(os.Stderr, "Warning: %v; falling back to stdout.\n", missing)
+		return
+	}
+	fmt.Fprintf(os.Stderr, "Warning: failed to copy %s to clipboard (%v).\n", what, err)
+	fmt.Fprintf(os.Stderr, "Tip: run `%s` manually.\n", tip)
+}
+
+// copy places data on the clipboard with copyToClipboard, reading it back when
+// verifyCopy is set.
+func (o reportOptions) copy(prefer string, data []byte) error {
+	if err := copyToClipboard(prefer, data); err != nil {
+		return err
+	}
+	if o.verifyCopy {
+		return verifyClipboard(prefer)
+	}
+	return nil
+}
+
+// copyHTML places htmlContent on the clipboard with copyHTMLToClipboard, reading it
+// back when verifyCopy is set.
+func (o reportOptions) copyHTML(htmlContent string) error {
+	if err := copyHTMLToClipboard(htmlContent); err != nil {
+		return err
+	}
+	if o.verifyCopy {
+		return verifyClipboard("html")
+	}
+	return nil
+}
+
+// copyToClipboard pipes data into pbcopy. It returns once pbcopy has read all of data
+// and exited, so the clipboard is set before the caller moves on.
 func copyToClipboard(prefer string, data []byte) error {
 	if runtime.GOOS != "darwin" {
 		return errors.New("clipboard copy supported on macOS only")
@@ -413,28 +2114,91 @@ func copyToClipboard(prefer string, data []byte) error {
 	if prefer != "" {
 		args = append(args, "-Prefer", prefer)
 	}
-	cmd := exec.Command("pbcopy", args...)
-	stdin, err := cmd.StdinPipe()
+	pbcopy, err := lookTool("pbcopy")
 	if err != nil {
 		return err
 	}
-	if err := cmd.Start(); err != nil {
-		stdin.Close()
+	cmd := exec.Command(pbcopy, args...)
+	cmd.Stdin = bytes.NewReader(data)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		if trimmed := strings.TrimSpace(string(output)); trimmed != "" {
+			return fmt.Errorf("%w: %s", err, trimmed)
+		}
+		return err
+	}
+	return nil
+}
+
+// verifyClipboard reads the clipboard back for -verify-clipboard and fails when it holds
+// nothing of the given type: "html", "rtf", or "" for plain text. pbpaste cannot read
+// HTML, so that type is read through osascript.
+func verifyClipboard(prefer string) error {
+	var cmd *exec.Cmd
+	if prefer == "html" {
+		osascript, err := lookTool("osascript")
+		if err != nil {
+			return err
+		}
+		cmd = exec.Command(osascript, "-e", "the clipboard as «class HTML»")
+	} else {
+		pbpaste, err := lookTool("pbpaste")
+		if err != nil {
+			return err
+		}
+		args := []string{}
+		if prefer != "" {
+			args = append(args, "-Prefer", prefer)
+		}
+		cmd = exec.Command(pbpaste, args...)
+	}
+
+	output, err := cmd.Output()
+	if err != nil {
+		return fmt.Errorf("read back clipboard: %w", err)
+	}
+	if len(bytes.TrimSpace(output)) == 0 {
+		return errors.New("clipboard is empty after copying")
+	}
+	return nil
+}
+
+// copyHTMLToClipboard places htmlContent on the macOS pasteboard as the public.html type
+// by handing osascript a hex-encoded HTML data literal, avoiding temp files and textutil.
+func copyHTMLToClipboard(htmlContent string) error {
+	if runtime.GOOS != "darwin" {
+		return errors.New("clipboard copy supported on macOS only")
+	}
+
+	script := htmlClipboardScript(htmlContent)
+	osascript, err := lookTool("osascript")
+	if err != nil {
 		return err
 	}
-	if _, err := stdin.Write(data); err != nil {
-		stdin.Close()
-		cmd.Wait()
+	cmd := exec.Command(osascript, "-")
+	cmd.Stdin = strings.NewReader(script)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		if trimmed := strings.TrimSpace(string(output)); trimmed != "" {
+			return fmt.Errorf("%w: %s", err, trimmed)
+		}
 		return err
 	}
-	stdin.Close()
-	return cmd.Wait()
+	return nil
+}
+
+// htmlClipboardScript returns the AppleScript that sets the clipboard to htmlContent.
+// The content is hex encoded, so quotes and non-ASCII text need no escaping.
+func htmlClipboardScript(htmlContent string) string {
+	return fmt.Sprintf("set the clipboard to «data HTML%X»", []byte(htmlContent))
 }
 
 func convertHTMLToRTF(htmlContent string) ([]byte, error) {
 	if runtime.GOOS != "darwin" {
 		return nil, errors.New("rtf conversion supported on macOS only")
 	}
+	textutil, err := lookTool("textutil")
+	if err != nil {
+		return nil, err
+	}
 
 	tempDir, err := os.MkdirTemp("", "wkreport-html")
 	if err != nil {
@@ -449,7 +2213,7 @@ func convertHTMLToRTF(htmlContent string) ([]byte, error) {
 		return nil, err
 	}
 
-	cmd := exec.Command("textutil", "-convert", "rtf", htmlPath, "-output", rtfPath)
+	cmd := exec.Command(textutil, "-convert", "rtf", htmlPath, "-output", rtfPath)
 	if err := cmd.Run(); err != nil {
 		return nil, err
 	}
@@ -462,20 +2226,134 @@ func convertHTMLToRTF(htmlContent string) ([]byte, error) {
 	return rtfData, nil
 }
 
-func displayFilters(ctx context.Context, client *jira.Client) error {
-	filters, err := client.ListFilters(ctx)
+// listOptions controls how displayFilters selects and prints filters.
+type listOptions struct {
+	// match keeps only filters whose name contains this text, ignoring case.
+	match string
+	// sortBy orders the listing by "name" (the default) or "id".
+	sortBy  string
+	showJQL bool
+	// details adds the owner and favourite columns.
+	details bool
+	// favourites lists only the current user's starred filters.
+	favourites bool
+	// limit caps the number of filters printed; zero prints all of them.
+	limit int
+}
+
+func matchFilters(filters []jira.Filter, match string) []jira.Filter {
+	match = strings.ToLower(strings.TrimSpace(match))
+	if match == "" {
+		return filters
+	}
+
+	matched := make([]jira.Filter, 0, len(filters))
+	for _, filter := range filters {
+		if strings.Contains(strings.ToLower(filter.Name), match) {
+			matched = append(matched, filter)
+		}
+	}
+	return matched
+}
+
+func sortFilters(filters []jira.Filter, sortBy string) error {
+	switch strings.ToLower(strings.TrimSpace(sortBy)) {
+	case "", "name":
+		sort.SliceStable(filters, func(i, j int) bool {
+			nameI := strings.ToLower(filters[i].Name)
+			nameJ := strings.ToLower(filters[j].Name)
+			if nameI == nameJ {
+				return filters[i].ID < filters[j].ID
+			}
+			return nameI < nameJ
+		})
+	case "id":
+		sort.SliceStable(filters, func(i, j int) bool { return filters[i].ID < filters[j].ID })
+	default:
+		return fmt.Errorf("unknown filter sort %q (expected name or id)", sortBy)
+	}
+	return nil
+}
+
+// printColumns prints rows as space-separated columns, padding every column but the
+// last to its widest cell (and the first to at least firstWidth).
+func printColumns(rows [][]string, firstWidth int) {
+	if len(rows) == 0 {
+		return
+	}
+	widths := make([]int, len(rows[0]))
+	widths[0] = firstWidth
+	for _, row := range rows {
+		for i, cell := range row[:len(row)-1] {
+			if n := len([]rune(cell)); n > widths[i] {
+				widths[i] = n
+			}
+		}
+	}
+	for _, row := range rows {
+		var b strings.Builder
+		for i, cell := range row {
+			if i == len(row)-1 {
+				b.WriteString(cell)
+				break
+			}
+			fmt.Fprintf(&b, "%-*s ", widths[i], cell)
+		}
+		fmt.Println(strings.TrimRight(b.String(), " "))
+	}
+}
+
+func displayFilters(ctx context.Context, client *jira.Client, opts listOptions) error {
+	list := client.ListFilters
+	if opts.favourites {
+		list = client.FavouriteFilters
+	}
+	filters, err := list(ctx)
 	if err != nil {
 		return fmt.Errorf("list filters: %w", err)
 	}
 
+	filters = matchFilters(filters, opts.match)
 	if len(filters) == 0 {
 		fmt.Println("No filters found.")
 		return nil
 	}
 
-	fmt.Printf("%-8s %s\n", "ID", "NAME")
+	if err := sortFilters(filters, opts.sortBy); err != nil {
+		return err
+	}
+
+	total := len(filters)
+	if opts.limit > 0 && opts.limit < total {
+		filters = filters[:opts.limit]
+	}
+
+	header := []string{"ID", "NAME"}
+	if opts.details {
+		header = append(header, "OWNER", "FAV")
+	}
+	if opts.showJQL {
+		header = append(header, "JQL")
+	}
+	rows := [][]string{header}
 	for _, filter := range filters {
-		fmt.Printf("%-8d %s\n", filter.ID, filter.Name)
+		row := []string{strconv.Itoa(filter.ID), filter.Name}
+		if opts.details {
+			favourite := ""
+			if filter.Favourite {
+				favourite = "*"
+			}
+			row = append(row, filter.Owner, favourite)
+		}
+		if opts.showJQL {
+			row = append(row, strings.TrimSpace(filter.JQL))
+		}
+		rows = append(rows, row)
+	}
+	printColumns(rows, 8)
+
+	if len(filters) < total {
+		fmt.Fprintf(os.Stderr, "Showing %d of %d filters.\n", len(filters), total)
 	}
 
 	return nil
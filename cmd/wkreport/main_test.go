@@ -0,0 +1,2005 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"wkreport/internal/jira"
+)
+
+// fakeFilterID is fakeJira's filter of the ABC project; fakeBugsFilterID is its filter
+// of the BUG project.
+const (
+	fakeFilterID     = 100
+	fakeBugsFilterID = 101
+)
+
+// fakeIssue is one issue of the fake filter's search.
+type fakeIssue struct {
+	id     string
+	key    string
+	status string
+	parent string
+	// resolved and updated are Jira timestamps, such as "2026-10-07T15:04:05.000+0000".
+	resolved string
+	updated  string
+}
+
+// fakeJira serves just enough of the Jira API for a filter report: the filters
+// fakeFilterID and fakeBugsFilterID and a single-page search for their issues, whose
+// details it counts. A search for "project = X" only finds the issues keyed X-N.
+type fakeJira struct {
+	*httptest.Server
+	issues []fakeIssue
+
+	mu sync.Mutex
+	// searches are the jql parameters of the search requests.
+	searches []string
+	details  map[string]int
+	// failing makes the detail requests of these issue ids fail.
+	failing map[string]bool
+	// filters are listed by -ls and searched by name, in filter/search's format.
+	filters []map[string]any
+}
+
+func newFakeJira(t *testing.T, issues ...fakeIssue) *fakeJira {
+	t.Helper()
+	f := &fakeJira{issues: issues, details: make(map[string]int), failing: make(map[string]bool)}
+	f.Server = httptest.NewServer(http.HandlerFunc(f.serve))
+	t.Cleanup(f.Close)
+	return f
+}
+
+func (f *fakeJira) serve(w http.ResponseWriter, r *http.Request) {
+	switch path := r.URL.Path; {
+	case path == "/rest/api/3/filter/search":
+		values := []map[string]any{}
+		name := r.URL.Query().Get("filterName")
+		for _, filter := range f.filters {
+			if name == "" || strings.Contains(strings.ToLower(fmt.Sprint(filter["name"])), strings.ToLower(name)) {
+				values = append(values, filter)
+			}
+		}
+		writeTestJSON(w, map[string]any{"values": values, "total": len(values), "isLast": true})
+	case path == "/rest/api/3/filter/favourite":
+		favourites := []map[string]any{}
+		for _, filter := range f.filters {
+			if filter["favourite"] == true {
+				favourites = append(favourites, filter)
+			}
+		}
+		writeTestJSON(w, favourites)
+	case path == fmt.Sprintf("/rest/api/3/filter/%d", fakeFilterID):
+		writeTestJSON(w, map[string]any{
+			"id":        fmt.Sprint(fakeFilterID),
+			"name":      "Weekly",
+			"jql":       "project = ABC ORDER BY key",
+			"searchUrl": f.URL + "/rest/api/3/search?jql=project%20%3D%20ABC",
+		})
+	case path == fmt.Sprintf("/rest/api/3/filter/%d", fakeBugsFilterID):
+		writeTestJSON(w, map[string]any{
+			"id":   fmt.Sprint(fakeBugsFilterID),
+			"name": "Bugs",
+			"jql":  "project = BUG ORDER BY key",
+		})
+	case path == "/rest/api/3/search":
+		f.mu.Lock()
+		jql := r.URL.Query().Get("jql")
+		f.searches = append(f.searches, jql)
+		f.mu.Unlock()
+		project := ""
+		if m := regexp.MustCompile(`project = (\w+)`).FindStringSubmatch(jql); m != nil {
+			project = m[1] + "-"
+		}
+		refs := []map[string]string{}
+		for _, issue := range f.issues {
+			if strings.HasPrefix(issue.key, project) {
+				refs = append(refs, map[string]string{"id": issue.id})
+			}
+		}
+		writeTestJSON(w, map[string]any{"issues": refs, "total": len(refs), "isLast": true})
+	case strings.HasPrefix(path, "/rest/api/3/issue/"):
+		id := strings.TrimPrefix(path, "/rest/api/3/issue/")
+		f.mu.Lock()
+		f.details[id]++
+		failing := f.failing[id]
+		f.mu.Unlock()
+		for _, issue := range f.issues {
+			if issue.id == id && !failing {
+				writeTestJSON(w, issue.payload())
+				return
+			}
+		}
+		http.Error(w, `{"errorMessages":["Issue does not exist"]}`, http.StatusNotFound)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (i fakeIssue) payload() map[string]any {
+	fields := map[string]any{
+		"summary": "Summary of " + i.key,
+		"status":  map[string]string{"name": i.status},
+	}
+	if i.resolved != "" {
+		fields["resolutiondate"] = i.resolved
+	}
+	if i.updated != "" {
+		fields["updated"] = i.updated
+	}
+	if i.parent != "" {
+		fields["parent"] = map[string]any{"key": i.parent, "fields": map[string]string{"summary": "Parent " + i.parent}}
+	}
+	return map[string]any{"id": i.id, "key": i.key, "fields": fields}
+}
+
+// detailRequests returns how often each issue's details were requested.
+func (f *fakeJira) detailRequests() map[string]int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	counts := make(map[string]int, len(f.details))
+	for id, n := range f.details {
+		counts[id] = n
+	}
+	return counts
+}
+
+func writeTestJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeTestConfig writes a config file for the fake Jira at url and points the cache
+// directory at a fresh temporary one, so runs never touch the real state or journals.
+func writeTestConfig(t *testing.T, url string) string {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("XDG_CACHE_HOME", filepath.Join(dir, "cache"))
+	t.Setenv("HOME", dir)
+	path := filepath.Join(dir, "config.yaml")
+	content := fmt.Sprintf("jira:\n  url: %s\n  email: user@example.com\n  token: secret\n", url)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	return path
+}
+
+// runCapture runs the command line args and returns what it printed to stdout.
+func runCapture(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	return captureStdout(t, func() error { return run(context.Background(), args) })
+}
+
+// captureStdout calls fn and returns what it printed to stdout.
+func captureStdout(t *testing.T, fn func() error) (string, error) {
+	t.Helper()
+	return captureFile(t, &os.Stdout, fn)
+}
+
+// captureStderr calls fn and returns what it printed to stderr.
+func captureStderr(t *testing.T, fn func()) string {
+	t.Helper()
+	out, _ := captureFile(t, &os.Stderr, func() error { fn(); return nil })
+	return out
+}
+
+// captureFile points *file at a pipe while fn runs and returns what fn wrote to it.
+func captureFile(t *testing.T, file **os.File, fn func() error) (string, error) {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("pipe: %v", err)
+	}
+	saved := *file
+	*file = w
+	defer func() { *file = saved }()
+
+	output := make(chan string)
+	go func() {
+		data, _ := io.ReadAll(r)
+		output <- string(data)
+	}()
+	runErr := fn()
+	w.Close()
+	return <-output, runErr
+}
+
+func TestResolveFormat(t *testing.T) {
+	for _, tc := range []struct {
+		name          string
+		args          []string
+		want          reportFormat
+		wantNote      string
+		wantErrSubstr string
+	}{
+		{name: "none", args: nil, want: ""},
+		{name: "format", args: []string{"-format", "csv"}, want: formatCSV},
+		{name: "format case and tabs alias", args: []string{"-format", " Tabs "}, want: formatTSV},
+		{name: "unknown format", args: []string{"-format", "pdf"}, wantErrSubstr: `unknown format "pdf"`},
+		{name: "tabs", args: []string{"-tabs"}, want: formatTSV, wantNote: "-tabs is deprecated; use -format tsv"},
+		{name: "docs", args: []string{"-docs"}, want: formatDocs, wantNote: "-docs is deprecated; use -format docs"},
+		{name: "slides", args: []string{"-slides"}, want: formatSlides, wantNote: "-slides is deprecated"},
+		{name: "slack", args: []string{"-slack"}, want: formatSlack, wantNote: "-slack is deprecated"},
+		{name: "json", args: []string{"-json"}, want: formatJSON, wantNote: "-json is deprecated"},
+		{name: "jsonl", args: []string{"-jsonl"}, want: formatJSONL, wantNote: "-jsonl is deprecated"},
+		{name: "brief shorthand", args: []string{"-brief"}, want: formatBrief},
+		{name: "porcelain shorthand", args: []string{"-porcelain"}, want: formatPorcelain},
+		{name: "false alias", args: []string{"-docs=false"}, want: ""},
+		{name: "alias agreeing with format", args: []string{"-format", "docs", "-docs"}, want: formatDocs, wantNote: "-docs is deprecated"},
+		{name: "alias conflicting with format", args: []string{"-format", "md", "-docs"}, wantErrSubstr: "-format and -docs conflict"},
+		{name: "conflicting aliases", args: []string{"-json", "-slides"}, wantErrSubstr: "choose only one output format"},
+		{name: "conflicting shorthand", args: []string{"-tabs", "-sheets"}, wantErrSubstr: "choose only one output format"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var rf reportFlags
+			flags := newReportFlagSet(&rf)
+			if err := flags.Parse(tc.args); err != nil {
+				t.Fatalf("parse: %v", err)
+			}
+			var format reportFormat
+			var err error
+			note := captureStderr(t, func() { format, err = resolveFormat(flags, rf.formatFlag, true) })
+			if tc.wantErrSubstr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErrSubstr) {
+					t.Fatalf("error = %v, want one containing %q", err, tc.wantErrSubstr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("resolveFormat: %v", err)
+			}
+			if format != tc.want {
+				t.Errorf("format = %q, want %q", format, tc.want)
+			}
+			if tc.wantNote == "" && note != "" || !strings.Contains(note, tc.wantNote) {
+				t.Errorf("debug output = %q, want %q", note, tc.wantNote)
+			}
+		})
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	for _, format := range reportFormats {
+		if got, err := parseFormat(strings.ToUpper(string(format))); err != nil || got != format {
+			t.Errorf("parseFormat(%q) = %q, %v, want %q", strings.ToUpper(string(format)), got, err, format)
+		}
+	}
+	for _, name := range []string{"", "pdf", "table,csv"} {
+		if got, err := parseFormat(name); err == nil {
+			t.Errorf("parseFormat(%q) = %q, want an error", name, got)
+		}
+	}
+}
+
+// TestModeFlagSet covers when output.default_mode applies: only when no flag picks the
+// output.
+func TestModeFlagSet(t *testing.T) {
+	for _, tc := range []struct {
+		args []string
+		want bool
+	}{
+		{nil, false},
+		{[]string{"-f", "123", "-sort", "key", "-no-color"}, false},
+		{[]string{"-format", "md"}, true},
+		{[]string{"-docs"}, true},
+		{[]string{"-porcelain"}, true},
+		{[]string{"-template", "{{len .}}"}, true},
+		{[]string{"-xlsx", "report.xlsx"}, true},
+	} {
+		var rf reportFlags
+		flags := newReportFlagSet(&rf)
+		if err := flags.Parse(tc.args); err != nil {
+			t.Fatalf("parse %q: %v", tc.args, err)
+		}
+		if got := modeFlagSet(flags); got != tc.want {
+			t.Errorf("modeFlagSet(%q) = %v, want %v", tc.args, got, tc.want)
+		}
+	}
+}
+
+func TestDefaultModeFallback(t *testing.T) {
+	fake := newFakeJira(t, fakeIssue{id: "1", key: "ABC-1", status: "Done"})
+	cfgPath := writeTestConfig(t, fake.URL)
+	config, err := os.ReadFile(cfgPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(cfgPath, append(config, "output:\n  default_mode: porcelain\n"...), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"config default", nil, "ABC-1\tDone\t"},
+		{"format flag", []string{"-format", "csv"}, "KEY,SUMMARY,STATUS"},
+		{"deprecated flag", []string{"-json"}, `"key": "ABC-1"`},
+		{"shorthand flag", []string{"-brief"}, "ABC-1\tSummary of ABC-1"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			out, err := runCapture(t, append([]string{"-config", cfgPath, "-f", fmt.Sprint(fakeFilterID)}, tc.args...)...)
+			if err != nil {
+				t.Fatalf("run: %v", err)
+			}
+			if !strings.Contains(out, tc.want) {
+				t.Errorf("output is missing %q:\n%s", tc.want, out)
+			}
+		})
+	}
+}
+
+func TestShowJQL(t *testing.T) {
+	fake := newFakeJira(t, fakeIssue{id: "1", key: "ABC-1", status: "Done"})
+	cfgPath := writeTestConfig(t, fake.URL)
+	for _, tc := range []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"filter", []string{"-f", fmt.Sprint(fakeFilterID)}, "Filter 100 (Weekly) JQL: project = ABC ORDER BY key\n"},
+		{"me", []string{"-me"}, "-me JQL: " + myIssuesJQL + "\n"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var out string
+			stderr := captureStderr(t, func() {
+				var err error
+				if out, err = runCapture(t, append([]string{"-config", cfgPath, "-format", "brief", "-show-jql"}, tc.args...)...); err != nil {
+					t.Errorf("run: %v", err)
+				}
+			})
+			if stderr != tc.want {
+				t.Errorf("stderr = %q, want %q", stderr, tc.want)
+			}
+			if strings.Contains(out, "JQL") || !strings.Contains(out, "ABC-1") {
+				t.Errorf("stdout = %q, want just the report", out)
+			}
+		})
+	}
+}
+
+func TestMe(t *testing.T) {
+	fake := newFakeJira(t,
+		fakeIssue{id: "1", key: "ABC-1", status: "Done"},
+		fakeIssue{id: "2", key: "ABC-2", status: "In Progress"},
+	)
+	cfgPath := writeTestConfig(t, fake.URL)
+	for _, tc := range []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"all", nil, "KEY\tSTATUS\nABC-1\tDone\nABC-2\tIn Progress\n"},
+		{"status", []string{"-status", "in progress"}, "KEY\tSTATUS\nABC-2\tIn Progress\n"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			fake.searches = nil
+			out, err := runCapture(t, append([]string{"-config", cfgPath, "-me", "-format", "tsv", "-fields", "key,status"}, tc.args...)...)
+			if err != nil {
+				t.Fatalf("run: %v", err)
+			}
+			if want := []string{myIssuesJQL}; !slices.Equal(fake.searches, want) {
+				t.Errorf("searched %q, want %q", fake.searches, want)
+			}
+			if out != tc.want {
+				t.Errorf("stdout = %q, want %q", out, tc.want)
+			}
+		})
+	}
+}
+
+func TestFailIfEmpty(t *testing.T) {
+	empty := writeTestConfig(t, newFakeJira(t).URL)
+	full := writeTestConfig(t, newFakeJira(t, fakeIssue{id: "1", key: "ABC-1", status: "Done"}).URL)
+	filter := fmt.Sprint(fakeFilterID)
+	for _, tc := range []struct {
+		name     string
+		args     []string
+		wantCode int
+	}{
+		{"empty", []string{"-config", empty, "-f", filter}, 0},
+		{"empty with the flag", []string{"-config", empty, "-f", filter, "-fail-if-empty"}, exitEmpty},
+		{"empty sections", []string{"-config", empty, "-f", filter + "," + filter, "-sectioned", "-fail-if-empty"}, exitEmpty},
+		{"issues with the flag", []string{"-config", full, "-f", filter, "-fail-if-empty"}, 0},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := runCapture(t, append(tc.args, "-format", "tsv")...)
+			code := 0
+			var exitErr *exitCodeError
+			switch {
+			case errors.As(err, &exitErr) && exitErr.err == nil:
+				code = exitErr.code
+			case err != nil:
+				t.Fatalf("run: %v", err)
+			}
+			if code != tc.wantCode {
+				t.Errorf("exit status %d, want %d", code, tc.wantCode)
+			}
+		})
+	}
+}
+
+func TestDebugLogsFilterResolvedByName(t *testing.T) {
+	fake := newFakeJira(t, fakeIssue{id: "1", key: "ABC-1", status: "Done"})
+	fake.filters = listFilterFixture
+	cfgPath := writeTestConfig(t, fake.URL)
+	for _, tc := range []struct {
+		ref  string
+		want string
+	}{
+		{"Weekly report", `Resolved filter "Weekly report" to 12 (Weekly report, owned by Pat Lee)` + "\n"},
+		{"weekly triage", `Resolved filter "weekly triage" to 7 (weekly triage, owned by unknown owner)` + "\n"},
+		{fmt.Sprint(fakeFilterID), ""},
+	} {
+		stderr := captureStderr(t, func() {
+			if _, err := runCapture(t, "-config", cfgPath, "-f", tc.ref, "-format", "brief", "-debug"); err != nil {
+				t.Errorf("-f %q: %v", tc.ref, err)
+			}
+		})
+		var got string
+		for _, line := range strings.SplitAfter(stderr, "\n") {
+			if strings.HasPrefix(line, "Resolved filter") {
+				got += line
+			}
+		}
+		if got != tc.want {
+			t.Errorf("-f %q logged %q, want %q", tc.ref, got, tc.want)
+		}
+	}
+}
+
+func TestSectioned(t *testing.T) {
+	if got, want := splitFilterRefs(" 100, 101 "), []string{"100", "101"}; !slices.Equal(got, want) {
+		t.Errorf("splitFilterRefs = %q, want %q", got, want)
+	}
+	if got, want := splitFilterRefs("Bugs, Weekly"), []string{"Bugs, Weekly"}; !slices.Equal(got, want) {
+		t.Errorf("splitFilterRefs of a name = %q, want %q", got, want)
+	}
+
+	fake := newFakeJira(t,
+		fakeIssue{id: "1", key: "ABC-1", status: "Done"},
+		fakeIssue{id: "2", key: "BUG-1", status: "To Do"},
+		fakeIssue{id: "3", key: "BUG-2", status: "Done"},
+	)
+	cfgPath := writeTestConfig(t, fake.URL)
+	filters := fmt.Sprintf("%d,%d", fakeFilterID, fakeBugsFilterID)
+	for _, tc := range []struct {
+		format string
+		want   string
+	}{
+		{"brief", "== Weekly (100) ==\nABC-1\tSummary of ABC-1\n\n== Bugs (101) ==\nBUG-2\tSummary of BUG-2\nBUG-1\tSummary of BUG-1\n"},
+		{"md", "## Weekly (100)\n"},
+	} {
+		out, err := runCapture(t, "-config", cfgPath, "-f", filters, "-sectioned", "-format", tc.format, "-fields", "key,summary")
+		if err != nil {
+			t.Fatalf("-sectioned -format %s: %v", tc.format, err)
+		}
+		if !strings.HasPrefix(out, tc.want) {
+			t.Errorf("-sectioned -format %s printed:\n%s\nwant it to start with\n%s", tc.format, out, tc.want)
+		}
+		if strings.Count(out, "(100)") != 1 || strings.Count(out, "(101)") != 1 {
+			t.Errorf("-sectioned -format %s does not label each filter once:\n%s", tc.format, out)
+		}
+	}
+
+	out, err := runCapture(t, "-config", cfgPath, "-f", filters, "-format", "brief")
+	if err != nil {
+		t.Fatalf("merged: %v", err)
+	}
+	// Merged, the filters' issues are sorted together by status.
+	if want := "ABC-1\tSummary of ABC-1\nBUG-2\tSummary of BUG-2\nBUG-1\tSummary of BUG-1\n"; out != want {
+		t.Errorf("merged report printed:\n%s\nwant\n%s", out, want)
+	}
+}
+
+func TestInputRoundTrip(t *testing.T) {
+	fake := newFakeJira(t,
+		fakeIssue{id: "1", key: "ABC-1", status: "Done", parent: "ABC-9", resolved: "2026-10-07T15:04:05.000+0000"},
+		fakeIssue{id: "2", key: "ABC-2", status: "To Do"},
+	)
+	cfgPath := writeTestConfig(t, fake.URL)
+	filter := fmt.Sprint(fakeFilterID)
+
+	dump, err := runCapture(t, "-config", cfgPath, "-f", filter, "-format", "json")
+	if err != nil {
+		t.Fatalf("-format json: %v", err)
+	}
+	saved := filepath.Join(t.TempDir(), "issues.json")
+	if err := os.WriteFile(saved, []byte(dump), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	online, err := runCapture(t, "-config", cfgPath, "-f", filter, "-format", "csv")
+	if err != nil {
+		t.Fatalf("-format csv: %v", err)
+	}
+
+	searches := len(fake.searches)
+	offline, err := runCapture(t, "-config", cfgPath, "-input", saved, "-format", "csv")
+	if err != nil {
+		t.Fatalf("-input: %v", err)
+	}
+	if offline != online {
+		t.Errorf("-input csv:\n%s\nwant the online csv\n%s", offline, online)
+	}
+	if len(fake.searches) != searches {
+		t.Error("-input searched Jira")
+	}
+}
+
+func TestSaveWritesEveryFetchedIssue(t *testing.T) {
+	fake := newFakeJira(t,
+		fakeIssue{id: "1", key: "ABC-1", status: "Done", parent: "ABC-9"},
+		fakeIssue{id: "2", key: "ABC-2", status: "To Do"},
+		fakeIssue{id: "3", key: "ABC-3", status: "In Progress", resolved: "2026-10-07T15:04:05.000+0000"},
+	)
+	cfgPath := writeTestConfig(t, fake.URL)
+	saved := filepath.Join(t.TempDir(), "issues.json")
+	out, err := runCapture(t, "-config", cfgPath, "-f", fmt.Sprint(fakeFilterID), "-format", "tsv", "-fields", "key", "-status", "done", "-save", saved)
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if out != "KEY\nABC-1\n" {
+		t.Errorf("stdout = %q, want the -status filtered report", out)
+	}
+
+	content, err := os.ReadFile(saved)
+	if err != nil {
+		t.Fatalf("read -save file: %v", err)
+	}
+	var issues []jira.Issue
+	if err := json.Unmarshal(content, &issues); err != nil {
+		t.Fatalf("decode -save file: %v\n%s", err, content)
+	}
+	var keys []string
+	for _, issue := range issues {
+		keys = append(keys, issue.Key)
+	}
+	if want := []string{"ABC-1", "ABC-2", "ABC-3"}; !slices.Equal(keys, want) {
+		t.Errorf("saved issues %v, want every fetched issue %v", keys, want)
+	}
+	if issues[0].Parent != "ABC-9" || issues[0].URL != fake.URL+"/browse/ABC-1" || issues[2].Resolved == "" {
+		t.Errorf("saved issues lost fields: %+v", issues)
+	}
+}
+
+func TestFilterRefFromStdin(t *testing.T) {
+	for _, tc := range []struct {
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{"12345\n", "12345", false},
+		{"  Weekly report \r\nsecond line\n", "Weekly report", false},
+		{"12345", "12345", false},
+		{"", "", true},
+		{"\n12345\n", "", true},
+	} {
+		got, err := readFilterRef(bytes.NewReader([]byte(tc.input)))
+		if got != tc.want || (err != nil) != tc.wantErr {
+			t.Errorf("readFilterRef(%q) = %q, %v; want %q, error %v", tc.input, got, err, tc.want, tc.wantErr)
+		}
+	}
+
+	fake := newFakeJira(t, fakeIssue{id: "1", key: "ABC-1", status: "Done"})
+	stdin := filepath.Join(t.TempDir(), "stdin")
+	if err := os.WriteFile(stdin, fmt.Appendf(nil, " %d \nignored\n", fakeFilterID), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	in, err := os.Open(stdin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer in.Close()
+	saved := os.Stdin
+	os.Stdin = in
+	defer func() { os.Stdin = saved }()
+
+	out, err := runCapture(t, "-config", writeTestConfig(t, fake.URL), "-f", "-", "-format", "brief")
+	if err != nil {
+		t.Fatalf("-f -: %v", err)
+	}
+	if want := "ABC-1\tSummary of ABC-1\n"; out != want {
+		t.Errorf("-f - printed %q, want %q", out, want)
+	}
+}
+
+func TestStrict(t *testing.T) {
+	fake := newFakeJira(t,
+		fakeIssue{id: "1", key: "ABC-1", status: "Done"},
+		fakeIssue{id: "2", key: "ABC-2", status: "Blocked"},
+		fakeIssue{id: "3", key: "ABC-3", status: "to do"},
+		fakeIssue{id: "4", key: "ABC-4", status: "Blocked"},
+		fakeIssue{id: "5", key: "ABC-5", status: "Parked"},
+	)
+	cfgPath := writeTestConfig(t, fake.URL)
+	config, err := os.ReadFile(cfgPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(cfgPath, append(config, "output:\n  allowed_statuses: [To Do, In Progress, Done]\n"...), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	args := []string{"-config", cfgPath, "-f", fmt.Sprint(fakeFilterID), "-format", "tsv", "-fields", "key"}
+
+	out, err := runCapture(t, append(args, "-strict")...)
+	want := "-strict: 3 issue(s) have a status outside output.allowed_statuses: Blocked (ABC-2, ABC-4); Parked (ABC-5)"
+	if err == nil || err.Error() != want {
+		t.Errorf("-strict error = %v, want %q", err, want)
+	}
+	if out != "" {
+		t.Errorf("-strict printed %q, want no report", out)
+	}
+
+	if _, err := runCapture(t, append(args, "-strict", "-status", "done,to do")...); err != nil {
+		t.Errorf("-strict with only allowed statuses: %v", err)
+	}
+	if _, err := runCapture(t, args...); err != nil {
+		t.Errorf("without -strict: %v", err)
+	}
+}
+
+// listFilterFixture are the filters the -ls tests list.
+var listFilterFixture = []map[string]any{
+	{"id": "12", "name": "Weekly report", "jql": "project = ABC", "owner": map[string]string{"displayName": "Pat Lee"}, "favourite": true},
+	{"id": "3", "name": "Bugs", "jql": "type = Bug", "owner": map[string]string{"displayName": "Sam Roe"}},
+	{"id": "7", "name": "weekly triage", "jql": "status = New", "favourite": true},
+}
+
+func TestListFiltersMatch(t *testing.T) {
+	fake := newFakeJira(t)
+	fake.filters = listFilterFixture
+	cfgPath := writeTestConfig(t, fake.URL)
+	for _, tc := range []struct {
+		match string
+		want  string
+	}{
+		{"", "ID       NAME\n3        Bugs\n12       Weekly report\n7        weekly triage\n"},
+		{"WEEKLY", "ID       NAME\n12       Weekly report\n7        weekly triage\n"},
+		{" bug ", "ID       NAME\n3        Bugs\n"},
+		{"sprint", "No filters found.\n"},
+	} {
+		out, err := runCapture(t, "-config", cfgPath, "-ls", "-ls-match", tc.match)
+		if err != nil {
+			t.Fatalf("-ls-match %q: %v", tc.match, err)
+		}
+		if out != tc.want {
+			t.Errorf("-ls-match %q:\n%s\nwant\n%s", tc.match, out, tc.want)
+		}
+	}
+}
+
+func TestListFiltersDetails(t *testing.T) {
+	fake := newFakeJira(t)
+	fake.filters = listFilterFixture
+	out, err := runCapture(t, "-config", writeTestConfig(t, fake.URL), "-ls", "-ls-details")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "ID       NAME          OWNER   FAV\n" +
+		"3        Bugs          Sam Roe\n" +
+		"12       Weekly report Pat Lee *\n" +
+		"7        weekly triage         *\n"
+	if out != want {
+		t.Errorf("-ls-details:\n%s\nwant\n%s", out, want)
+	}
+}
+
+func TestListFavouriteFilters(t *testing.T) {
+	fake := newFakeJira(t)
+	fake.filters = listFilterFixture
+	cfgPath := writeTestConfig(t, fake.URL)
+	want := "ID       NAME\n12       Weekly report\n7        weekly triage\n"
+	for _, args := range [][]string{
+		{"-config", cfgPath, "-ls", "-ls-favourites"},
+		{"filters", "-config", cfgPath, "-favourites"},
+	} {
+		out, err := runCapture(t, args...)
+		if err != nil {
+			t.Fatalf("%q: %v", args, err)
+		}
+		if out != want {
+			t.Errorf("%q:\n%s\nwant\n%s", args, out, want)
+		}
+	}
+}
+
+func TestListFiltersSortJQLAndLimit(t *testing.T) {
+	fake := newFakeJira(t)
+	fake.filters = listFilterFixture
+	cfgPath := writeTestConfig(t, fake.URL)
+	for _, tc := range []struct {
+		name       string
+		args       []string
+		want       string
+		wantStderr string
+	}{
+		{name: "by id", args: []string{"-ls-sort", "id"}, want: "ID       NAME\n3        Bugs\n7        weekly triage\n12       Weekly report\n"},
+		{
+			name: "jql column",
+			args: []string{"-ls-jql"},
+			want: "ID       NAME          JQL\n3        Bugs          type = Bug\n12       Weekly report project = ABC\n7        weekly triage status = New\n",
+		},
+		{
+			name:       "limit",
+			args:       []string{"-ls-sort", "ID", "-ls-limit", "2"},
+			want:       "ID       NAME\n3        Bugs\n7        weekly triage\n",
+			wantStderr: "Showing 2 of 3 filters.\n",
+		},
+		{name: "limit above total", args: []string{"-ls-limit", "5"}, want: "ID       NAME\n3        Bugs\n12       Weekly report\n7        weekly triage\n"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var out string
+			stderr := captureStderr(t, func() {
+				var err error
+				if out, err = runCapture(t, append([]string{"-config", cfgPath, "-ls"}, tc.args...)...); err != nil {
+					t.Errorf("run: %v", err)
+				}
+			})
+			if out != tc.want {
+				t.Errorf("listing:\n%s\nwant\n%s", out, tc.want)
+			}
+			if stderr != tc.wantStderr {
+				t.Errorf("stderr = %q, want %q", stderr, tc.wantStderr)
+			}
+		})
+	}
+
+	if _, err := runCapture(t, "-config", cfgPath, "-ls", "-ls-sort", "owner"); err == nil || !strings.Contains(err.Error(), `unknown filter sort "owner"`) {
+		t.Errorf("-ls-sort owner error = %v", err)
+	}
+}
+
+func TestAppendOutput(t *testing.T) {
+	fake := newFakeJira(t, fakeIssue{id: "1", key: "ABC-1", status: "Done"})
+	cfgPath := writeTestConfig(t, fake.URL)
+	separator := regexp.MustCompile(`(?m)^===== \d{4}-\d{2}-\d{2} \d{2}:\d{2} =====$`)
+
+	for _, tc := range []struct {
+		format string
+		check  func(t *testing.T, content string)
+	}{
+		{"brief", func(t *testing.T, content string) {
+			if n := len(separator.FindAllString(content, -1)); n != 2 {
+				t.Errorf("found %d separators, want one per run:\n%s", n, content)
+			}
+			if !strings.HasSuffix(content, "ABC-1\tSummary of ABC-1\n\n"+separator.FindAllString(content, -1)[1]+"\nABC-1\tSummary of ABC-1\n") {
+				t.Errorf("the second report does not follow a blank line and its separator:\n%s", content)
+			}
+		}},
+		{"csv", func(t *testing.T, content string) {
+			if want := "KEY,SUMMARY\nABC-1,Summary of ABC-1\nABC-1,Summary of ABC-1\n"; content != want {
+				t.Errorf("appended csv = %q, want one header and two rows %q", content, want)
+			}
+		}},
+		{"jsonl", func(t *testing.T, content string) {
+			if lines := strings.Split(strings.TrimSpace(content), "\n"); len(lines) != 2 || separator.MatchString(content) {
+				t.Errorf("appended jsonl = %q, want two lines and no separator", content)
+			}
+		}},
+	} {
+		t.Run(tc.format, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "report.out")
+			for range 2 {
+				args := []string{"-config", cfgPath, "-f", fmt.Sprint(fakeFilterID), "-format", tc.format, "-fields", "key,summary", "-o", path, "-append"}
+				captureStderr(t, func() {
+					if _, err := runCapture(t, args...); err != nil {
+						t.Errorf("run: %v", err)
+					}
+				})
+			}
+			content, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			tc.check(t, string(content))
+		})
+	}
+
+	path := filepath.Join(t.TempDir(), "report.out")
+	if err := os.WriteFile(path, []byte("old report\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	captureStderr(t, func() {
+		if _, err := runCapture(t, "-config", cfgPath, "-f", fmt.Sprint(fakeFilterID), "-format", "brief", "-o", path); err != nil {
+			t.Errorf("run: %v", err)
+		}
+	})
+	if content, _ := os.ReadFile(path); string(content) != "ABC-1\tSummary of ABC-1\n" {
+		t.Errorf("-o without -append left %q, want the file replaced", content)
+	}
+}
+
+func TestNoLinks(t *testing.T) {
+	fake := newFakeJira(t,
+		fakeIssue{id: "1", key: "ABC-1", status: "To Do", parent: "ABC-9"},
+		fakeIssue{id: "2", key: "ABC-2", status: "Done"},
+	)
+	cfgPath := writeTestConfig(t, fake.URL)
+
+	for _, tc := range []struct {
+		name string
+		args []string
+	}{
+		{"markdown", []string{"-format", "md"}},
+		{"json", []string{"-format", "json"}},
+		{"stream", []string{"-stream", "-format", "table", "-fields", "key,parent"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			args := append([]string{"-config", cfgPath, "-f", fmt.Sprint(fakeFilterID), "-no-links", "-no-color"}, tc.args...)
+			out, err := runCapture(t, args...)
+			if err != nil {
+				t.Fatalf("run: %v", err)
+			}
+			if strings.Contains(out, fake.URL) || strings.Contains(out, "/browse/") {
+				t.Errorf("output links issues:\n%s", out)
+			}
+			for _, key := range []string{"ABC-1", "ABC-2", "ABC-9"} {
+				if !strings.Contains(out, key) {
+					t.Errorf("output is missing %s:\n%s", key, out)
+				}
+			}
+		})
+	}
+}
+
+func TestBrowseURL(t *testing.T) {
+	fake := newFakeJira(t, fakeIssue{id: "1", key: "ABC-1", status: "To Do"})
+	cfgPath := writeTestConfig(t, fake.URL)
+	config, err := os.ReadFile(cfgPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(cfgPath, append(config, "  browse_url: https://jira.example.com/\n"...), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, format := range []string{"md", "docs"} {
+		t.Run(format, func(t *testing.T) {
+			out, err := runCapture(t, "-config", cfgPath, "-f", fmt.Sprint(fakeFilterID), "-format", format, "-no-clipboard")
+			if err != nil {
+				t.Fatalf("run: %v", err)
+			}
+			if !strings.Contains(out, "https://jira.example.com/browse/ABC-1") {
+				t.Errorf("output does not link the browse URL:\n%s", out)
+			}
+			if strings.Contains(out, fake.URL) {
+				t.Errorf("output links the API base URL:\n%s", out)
+			}
+		})
+	}
+}
+
+func TestDateOnlyFormats(t *testing.T) {
+	fake := newFakeJira(t, fakeIssue{
+		id: "1", key: "ABC-1", status: "Done",
+		resolved: "2026-10-07T15:04:05.000+0000", updated: "2026-10-08T09:30:00.000+0000",
+	})
+	cfgPath := writeTestConfig(t, fake.URL)
+
+	for _, tc := range []struct {
+		format string
+		args   []string
+	}{
+		{"table", []string{"-format", "table", "-fields", "key,resolved,updated"}},
+		{"csv", []string{"-format", "csv", "-fields", "key,resolved,updated"}},
+		{"json", []string{"-format", "json"}},
+		{"porcelain", []string{"-format", "porcelain"}},
+		{"stream", []string{"-stream", "-format", "table", "-fields", "key,resolved,updated"}},
+	} {
+		for _, dateOnly := range []bool{false, true} {
+			t.Run(fmt.Sprintf("%s/date-only=%t", tc.format, dateOnly), func(t *testing.T) {
+				args := append([]string{"-config", cfgPath, "-f", fmt.Sprint(fakeFilterID), "-no-color"}, tc.args...)
+				want := []string{"2026-10-07 15:04", "2026-10-08 09:30"}
+				if dateOnly {
+					args = append(args, "-date-only")
+					want = []string{"2026-10-07", "2026-10-08"}
+				}
+				out, err := runCapture(t, args...)
+				if err != nil {
+					t.Fatalf("run: %v", err)
+				}
+				for _, date := range want {
+					if !strings.Contains(out, date) {
+						t.Errorf("output is missing %q:\n%s", date, out)
+					}
+				}
+				if dateOnly && (strings.Contains(out, "15:04") || strings.Contains(out, "09:30")) {
+					t.Errorf("output keeps the time of day:\n%s", out)
+				}
+			})
+		}
+	}
+}
+
+func TestNumberedAfterSortAndFilter(t *testing.T) {
+	fake := newFakeJira(t,
+		fakeIssue{id: "1", key: "ABC-1", status: "Done"},
+		fakeIssue{id: "2", key: "ABC-2", status: "To Do"},
+		fakeIssue{id: "3", key: "ABC-3", status: "Done"},
+		fakeIssue{id: "4", key: "ABC-4", status: "Done"},
+	)
+	out, err := runCapture(t, "-config", writeTestConfig(t, fake.URL), "-f", fmt.Sprint(fakeFilterID),
+		"-format", "csv", "-fields", "key", "-numbered", "-sort", "-key", "-status", "Done")
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if want := "#,KEY\n1,ABC-4\n2,ABC-3\n3,ABC-1\n"; out != want {
+		t.Errorf("output = %q, want %q", out, want)
+	}
+}
+
+func TestTableStreamNoLinks(t *testing.T) {
+	var out strings.Builder
+	stream := &tableStream{
+		out:      &out,
+		columns:  []column{columnsByName["key"]},
+		statuses: newStatusNormalizer(nil),
+		noLinks:  true,
+	}
+	stream.add(jira.Issue{Key: "ABC-1", URL: "https://jira.example.com/browse/ABC-1", Parent: "ABC-9", ParentURL: "https://jira.example.com/browse/ABC-9"})
+	if len(stream.issues) != 1 {
+		t.Fatalf("stream kept %d issues, want 1", len(stream.issues))
+	}
+	if got := stream.issues[0]; got.URL != "" || got.ParentURL != "" {
+		t.Errorf("streamed issue kept its links: %q, %q", got.URL, got.ParentURL)
+	}
+}
+
+func TestTableStreamWritesIncrementally(t *testing.T) {
+	columns, err := parseColumns("key,status", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out strings.Builder
+	stream := &tableStream{out: &out, columns: columns, statuses: newStatusNormalizer(nil), statusFilter: "done"}
+
+	stream.add(jira.Issue{Key: "ABC-1", Status: "To Do"})
+	if out.Len() != 0 {
+		t.Errorf("a filtered-out first issue wrote %q, want nothing yet", out.String())
+	}
+	stream.add(jira.Issue{Key: "ABC-2", Status: "Done"})
+	first := out.String()
+	if !strings.HasPrefix(first, "KEY") || !strings.Contains(first, "ABC-2") {
+		t.Fatalf("after the first kept issue, output = %q, want the header and its row", first)
+	}
+	stream.add(jira.Issue{Key: "ABC-3", Status: "done"})
+	rest, ok := strings.CutPrefix(out.String(), first)
+	if !ok || strings.Contains(rest, "KEY") || !strings.Contains(rest, "ABC-3") {
+		t.Errorf("the second kept issue appended %q, want just its row", rest)
+	}
+	if len(stream.issues) != 2 {
+		t.Errorf("stream kept %d issues, want ABC-2 and ABC-3", len(stream.issues))
+	}
+}
+
+func TestApplySinceDays(t *testing.T) {
+	now := time.Date(2026, 10, 14, 9, 30, 0, 0, time.UTC)
+	for _, tc := range []struct {
+		name          string
+		args          []string
+		wantResolved  string
+		wantUpdated   string
+		wantErrSubstr string
+	}{
+		{name: "unset", args: nil},
+		{name: "resolved window", args: []string{"-since-days", "7"}, wantResolved: "2026-10-07"},
+		{name: "updated window", args: []string{"-since-days", "7", "-since-field", "updated"}, wantUpdated: "2026-10-07"},
+		{name: "zero days with since-field", args: []string{"-since-days", "0", "-since-field", "updated"}, wantErrSubstr: "requires -since-days"},
+		{name: "narrows an earlier updated-since", args: []string{"-since-days", "7", "-since-field", "updated", "-updated-since", "2026-09-01"}, wantUpdated: "2026-10-07"},
+		{name: "keeps a later updated-since", args: []string{"-since-days", "7", "-since-field", "Updated", "-updated-since", "2026-10-10"}, wantUpdated: "2026-10-10"},
+		{name: "resolved leaves updated-since alone", args: []string{"-since-days", "7", "-updated-since", "2026-09-01"}, wantResolved: "2026-10-07", wantUpdated: "2026-09-01"},
+		{name: "since-field alone", args: []string{"-since-field", "updated"}, wantErrSubstr: "requires -since-days"},
+		{name: "unknown field", args: []string{"-since-days", "3", "-since-field", "created"}, wantErrSubstr: `unknown -since-field "created"`},
+		{name: "negative", args: []string{"-since-days", "-1"}, wantErrSubstr: "cannot be negative"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var rf reportFlags
+			flags := newReportFlagSet(&rf)
+			if err := flags.Parse(tc.args); err != nil {
+				t.Fatalf("parse: %v", err)
+			}
+			err := applySinceDays(&rf, flags, now)
+			if tc.wantErrSubstr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErrSubstr) {
+					t.Fatalf("error = %v, want one containing %q", err, tc.wantErrSubstr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("applySinceDays: %v", err)
+			}
+			if rf.resolvedSince != tc.wantResolved || rf.updatedSince != tc.wantUpdated {
+				t.Errorf("resolved since %q, updated since %q; want %q, %q", rf.resolvedSince, rf.updatedSince, tc.wantResolved, tc.wantUpdated)
+			}
+		})
+	}
+}
+
+func TestReportFlagsValidate(t *testing.T) {
+	for _, tc := range []struct {
+		name          string
+		args          []string
+		wantErrSubstr string
+	}{
+		{name: "defaults", args: nil},
+		{name: "filter", args: []string{"-f", "123", "-format", "md", "-sort", "-resolved"}},
+		{name: "me and filter", args: []string{"-me", "-f", "123"}, wantErrSubstr: "either -me or -f"},
+		{name: "me since last run", args: []string{"-me", "-since-last-run"}, wantErrSubstr: "need a saved filter"},
+		{name: "resume with input", args: []string{"-resume", "-input", "saved.json"}, wantErrSubstr: "-resume needs a saved filter"},
+		{name: "negative issue timeout", args: []string{"-issue-timeout", "-1s"}, wantErrSubstr: "-issue-timeout cannot be negative"},
+		{name: "negative deadline", args: []string{"-deadline", "-1m"}, wantErrSubstr: "-deadline must not be negative"},
+		{name: "open and resolved only", args: []string{"-open-only", "-resolved-only"}, wantErrSubstr: "either -open-only or -resolved-only"},
+		{name: "parent prefixes", args: []string{"-parent-summary", "-no-parent-prefix"}, wantErrSubstr: "either -parent-summary or -no-parent-prefix"},
+		{name: "negative digest keys", args: []string{"-digest-keys", "-1"}, wantErrSubstr: "-digest-keys cannot be negative"},
+		{name: "narrow bullets", args: []string{"-bullet-width", "3"}, wantErrSubstr: "-bullet-width must be at least 4"},
+		{name: "narrow width", args: []string{"-width", "3"}, wantErrSubstr: "-width must be 0"},
+		{name: "no width limit", args: []string{"-width", "0"}},
+		{name: "append without o", args: []string{"-append"}, wantErrSubstr: "-append requires -o"},
+		{name: "append with o", args: []string{"-append", "-o", "report.txt"}},
+		{name: "jira order and sort", args: []string{"-jira-order", "rank", "-sort", "key"}, wantErrSubstr: "either -jira-order or -sort"},
+		{name: "count report without count by", args: []string{"-count-with-report"}, wantErrSubstr: "requires -count-by"},
+		{name: "count by with format", args: []string{"-count-by", "status", "-format", "md"}, wantErrSubstr: "add -count-with-report"},
+		{name: "porcelain fields", args: []string{"-porcelain", "-fields", "key"}, wantErrSubstr: "-fields does not apply"},
+		{name: "bad updated since", args: []string{"-updated-since", "10/01/2026"}, wantErrSubstr: "is not a YYYY-MM-DD date"},
+		{name: "padded updated since", args: []string{"-updated-since", " 2026-10-01 "}},
+		{name: "unknown table style", args: []string{"-table-style", "fancy"}, wantErrSubstr: `unknown -table-style "fancy"`},
+		{name: "table style case", args: []string{"-table-style", "Minimal"}},
+		{name: "assignee groups outside slides", args: []string{"-group-by", "assignee"}, wantErrSubstr: "only applies to -format slides"},
+		{name: "assignee groups in slides", args: []string{"-group-by", "assignee", "-format", "slides"}},
+		{name: "unknown group by", args: []string{"-group-by", "type"}, wantErrSubstr: `unknown -group-by "type"`},
+		{name: "numbered csv", args: []string{"-numbered", "-format", "csv"}},
+		{name: "numbered json", args: []string{"-numbered", "-format", "json"}, wantErrSubstr: "-numbered only applies"},
+		{name: "watch too often", args: []string{"-watch", "1ms"}, wantErrSubstr: "-watch must be at least"},
+		{name: "docs template outside docs", args: []string{"-docs-template", "t.html"}, wantErrSubstr: "-docs-template only applies"},
+		{name: "input with filter", args: []string{"-input", "saved.json", "-f", "123"}, wantErrSubstr: "-input replaces -f and -me"},
+		{name: "stream with sort", args: []string{"-stream", "-sort", "key"}, wantErrSubstr: "cannot be combined with -sort"},
+		{name: "raw with format", args: []string{"-raw", "-format", "json"}, wantErrSubstr: "-raw prints Jira's own JSON"},
+		{name: "browse several filters", args: []string{"-browse", "-f", "1,2"}, wantErrSubstr: "-browse takes a single filter"},
+		{name: "browse one filter", args: []string{"-browse", "-f", "1"}},
+		{name: "sectioned json", args: []string{"-sectioned", "-f", "1,2", "-format", "json"}, wantErrSubstr: "-sectioned cannot be combined with -format json"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var rf reportFlags
+			flags := newReportFlagSet(&rf)
+			if err := flags.Parse(tc.args); err != nil {
+				t.Fatalf("parse: %v", err)
+			}
+			format, err := resolveFormat(flags, rf.formatFlag, false)
+			if err != nil {
+				t.Fatalf("resolveFormat: %v", err)
+			}
+			err = rf.validate(flags, format)
+			if tc.wantErrSubstr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErrSubstr) {
+					t.Fatalf("error = %v, want one containing %q", err, tc.wantErrSubstr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("validate: %v", err)
+			}
+		})
+	}
+}
+
+func TestFilterResolution(t *testing.T) {
+	issues := []jira.Issue{
+		{Key: "ABC-1", Resolved: "2026-10-07 15:04"},
+		{Key: "ABC-2"},
+		{Key: "ABC-3", Resolved: "Unresolved"},
+		{Key: "ABC-4", Resolved: "Won't Do"},
+		{Key: "ABC-5", Resolved: "  "},
+	}
+	for _, tc := range []struct {
+		resolved bool
+		want     []string
+	}{
+		{false, []string{"ABC-2", "ABC-3", "ABC-5"}},
+		{true, []string{"ABC-1", "ABC-4"}},
+	} {
+		var got []string
+		for _, issue := range filterResolution(issues, tc.resolved) {
+			got = append(got, issue.Key)
+		}
+		if !slices.Equal(got, tc.want) {
+			t.Errorf("filterResolution(resolved=%v) = %v, want %v", tc.resolved, got, tc.want)
+		}
+	}
+
+	fake := newFakeJira(t,
+		fakeIssue{id: "1", key: "ABC-1", status: "Done", resolved: "2026-10-07T15:04:05.000+0000"},
+		fakeIssue{id: "2", key: "ABC-2", status: "To Do"},
+		fakeIssue{id: "3", key: "ABC-3", status: "Done", resolved: "2026-10-08T09:30:00.000+0000"},
+	)
+	cfgPath := writeTestConfig(t, fake.URL)
+	for _, tc := range []struct {
+		args []string
+		want string
+	}{
+		{[]string{"-open-only", "-format", "tsv"}, "KEY\nABC-2\n"},
+		{[]string{"-resolved-only", "-format", "tsv"}, "KEY\nABC-1\nABC-3\n"},
+		{[]string{"-open-only", "-stream", "-no-color"}, "KEY\nABC-2\n"},
+		{[]string{"-resolved-only", "-stream", "-no-color"}, "KEY\nABC-1\nABC-3\n"},
+	} {
+		out, err := runCapture(t, append([]string{"-config", cfgPath, "-f", fmt.Sprint(fakeFilterID), "-fields", "key"}, tc.args...)...)
+		if err != nil {
+			t.Fatalf("%q: %v", tc.args, err)
+		}
+		if got := strings.Join(strings.Fields(out), "\n") + "\n"; got != tc.want {
+			t.Errorf("%q printed %q, want %q", tc.args, out, tc.want)
+		}
+	}
+}
+
+func TestSinceDaysBoundary(t *testing.T) {
+	now := time.Date(2026, 10, 14, 0, 5, 0, 0, time.UTC)
+	since := sinceDaysDate(3, now)
+	issues := []jira.Issue{
+		{Key: "ABC-1", Resolved: "2026-10-10 23:59"},
+		{Key: "ABC-2", Resolved: "2026-10-11 00:00"},
+		{Key: "ABC-3", Resolved: "2026-10-14 00:01"},
+		{Key: "ABC-4", Resolved: ""},
+	}
+	var kept []string
+	for _, issue := range filterResolvedSince(issues, since) {
+		kept = append(kept, issue.Key)
+	}
+	if want := []string{"ABC-2", "ABC-3"}; fmt.Sprint(kept) != fmt.Sprint(want) {
+		t.Errorf("kept %v, want %v (window starting %s)", kept, want, since)
+	}
+}
+
+func TestFilterUpdatedSinceBoundary(t *testing.T) {
+	issues := []jira.Issue{
+		{Key: "ABC-1", Updated: "2026-10-06 23:59"},
+		{Key: "ABC-2", Updated: "2026-10-07 00:00"},
+		{Key: "ABC-3", Updated: "2026-10-07"},
+		{Key: "ABC-4", Updated: "2026-10-14 09:30"},
+		{Key: "ABC-5", Updated: ""},
+		{Key: "ABC-6", Updated: "yesterday"},
+	}
+	var kept []string
+	for _, issue := range filterUpdatedSince(issues, "2026-10-07") {
+		kept = append(kept, issue.Key)
+	}
+	if want := []string{"ABC-2", "ABC-3", "ABC-4"}; fmt.Sprint(kept) != fmt.Sprint(want) {
+		t.Errorf("kept %v, want %v", kept, want)
+	}
+}
+
+func TestTruncateDates(t *testing.T) {
+	issues := []jira.Issue{
+		{Key: "ABC-1", Resolved: "2026-10-07 15:04", Updated: "2026-10-08 09:00"},
+		{Key: "ABC-2", Resolved: "Won't Do", Updated: ""},
+		{Key: "ABC-3", Resolved: "2026-10-07", Updated: "last week"},
+	}
+	truncateDates(issues)
+	var got []string
+	for _, issue := range issues {
+		got = append(got, issue.Resolved+"|"+issue.Updated)
+	}
+	if want := []string{"2026-10-07|2026-10-08", "Won't Do|", "2026-10-07|last week"}; !slices.Equal(got, want) {
+		t.Errorf("truncated dates = %q, want %q", got, want)
+	}
+}
+
+func TestSortIssuesByResolved(t *testing.T) {
+	issues := []jira.Issue{
+		{Key: "ABC-1", Resolved: ""},
+		{Key: "ABC-2", Resolved: "2026-10-07 10:00"},
+		{Key: "ABC-3", Resolved: "Won't Do"},
+		{Key: "ABC-4", Resolved: "2026-10-07"},
+		{Key: "ABC-5", Resolved: "2026-09-30 23:59"},
+		{Key: "ABC-6", Resolved: "2026-10-07 9:30"},
+	}
+	for _, tc := range []struct {
+		spec string
+		want []string
+	}{
+		{"resolved,key", []string{"ABC-5", "ABC-4", "ABC-6", "ABC-2", "ABC-1", "ABC-3"}},
+		{"-resolved,key", []string{"ABC-2", "ABC-6", "ABC-4", "ABC-5", "ABC-1", "ABC-3"}},
+	} {
+		t.Run(tc.spec, func(t *testing.T) {
+			keys, err := parseSortKeys(tc.spec)
+			if err != nil {
+				t.Fatalf("parseSortKeys(%q): %v", tc.spec, err)
+			}
+			sorted := slices.Clone(issues)
+			sortIssues(sorted, keys)
+			var got []string
+			for _, issue := range sorted {
+				got = append(got, issue.Key)
+			}
+			if !slices.Equal(got, tc.want) {
+				t.Errorf("sorted by %q = %v, want %v", tc.spec, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestWithStatusOrder(t *testing.T) {
+	issues := []jira.Issue{
+		{Key: "ABC-1", Status: "Done"},
+		{Key: "ABC-2", Status: "In Progress"},
+		{Key: "ABC-3", Status: "Blocked"},
+		{Key: "ABC-4", Status: "to do"},
+		{Key: "ABC-5", Status: "Archived"},
+		{Key: "ABC-6", Status: "In Progress"},
+	}
+	for _, tc := range []struct {
+		name  string
+		order []string
+		want  []string
+	}{
+		{"alphabetical", nil, []string{"ABC-5", "ABC-3", "ABC-1", "ABC-2", "ABC-6", "ABC-4"}},
+		{"configured", []string{"To Do", " in progress ", "Done"}, []string{"ABC-4", "ABC-2", "ABC-6", "ABC-1", "ABC-5", "ABC-3"}},
+		{"duplicates keep the first position", []string{"Done", "To Do", "Done"}, []string{"ABC-1", "ABC-4", "ABC-5", "ABC-3", "ABC-2", "ABC-6"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			sorted := slices.Clone(issues)
+			sortIssues(sorted, withStatusOrder([]sortKey{{field: "status"}, {field: "key"}}, tc.order))
+			var got []string
+			for _, issue := range sorted {
+				got = append(got, issue.Key)
+			}
+			if !slices.Equal(got, tc.want) {
+				t.Errorf("sorted with status_order %q = %v, want %v", tc.order, got, tc.want)
+			}
+		})
+	}
+
+	sorted := slices.Clone(issues[:4])
+	sortIssues(sorted, withStatusOrder([]sortKey{{field: "status"}}, []string{"To Do", "In Progress"}))
+	var names []string
+	for _, group := range groupByStatus(sorted) {
+		names = append(names, group.name)
+	}
+	if want := []string{"to do", "In Progress", "Blocked", "Done"}; !slices.Equal(names, want) {
+		t.Errorf("slide groups = %q, want %q", names, want)
+	}
+}
+
+func TestTruncateWidthBoundaries(t *testing.T) {
+	for _, tc := range []struct {
+		input    string
+		width    int
+		ellipsis string
+		want     string
+	}{
+		{"abcdef", 0, asciiEllipsis, "abcdef"},
+		{"abcdef", 6, asciiEllipsis, "abcdef"},
+		{"abcdef", 5, asciiEllipsis, "ab..."},
+		{"abcdef", 4, asciiEllipsis, "a..."},
+		{"abcdef", 3, asciiEllipsis, "abc"},
+		{"abcdef", 6, unicodeEllipsis, "abcdef"},
+		{"abcdef", 5, unicodeEllipsis, "abcd…"},
+		{"abcdef", 2, unicodeEllipsis, "a…"},
+		{"abcdef", 1, unicodeEllipsis, "a"},
+		// Widths count runes, not bytes.
+		{"ünïcødé", 7, asciiEllipsis, "ünïcødé"},
+		{"ünïcødé", 6, asciiEllipsis, "ünï..."},
+		{"ünïcødé", 6, unicodeEllipsis, "ünïcø…"},
+	} {
+		if got := truncate(tc.input, tc.width, tc.ellipsis); got != tc.want {
+			t.Errorf("truncate(%q, %d, %q) = %q, want %q", tc.input, tc.width, tc.ellipsis, got, tc.want)
+		}
+	}
+}
+
+func TestSummaryUsesEllipsis(t *testing.T) {
+	issue := jira.Issue{Key: "ABC-1", Summary: "A fairly long summary", Parent: "ABC-9"}
+	for ellipsis, want := range map[string]string{
+		asciiEllipsis:   "ABC-9 / A fair...",
+		unicodeEllipsis: "ABC-9 / A fairly…",
+	} {
+		if got := (summaryFormat{ellipsis: ellipsis}).summary(issue, 17); got != want {
+			t.Errorf("summary with %q = %q, want %q", ellipsis, got, want)
+		}
+	}
+}
+
+func TestEscapeSlack(t *testing.T) {
+	for input, want := range map[string]string{
+		"plain":             "plain",
+		"R&D <beta> -> GA":  "R&amp;D &lt;beta&gt; -&gt; GA",
+		"&amp; stays typed": "&amp;amp; stays typed",
+	} {
+		if got := escapeSlack(input); got != want {
+			t.Errorf("escapeSlack(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestBuildSlackGroupsByStatus(t *testing.T) {
+	issues := []jira.Issue{
+		{Key: "ABC-1", Summary: "Fix <script> & tags", Status: "To Do", URL: "https://jira.example.com/browse/ABC-1?a=1&b=2"},
+		{Key: "ABC-2", Summary: "Second", Status: "To Do"},
+		{Key: "ABC-3", Summary: "Third", Status: "R&D"},
+		{Key: "ABC-4", Summary: "", Status: " "},
+	}
+	want := strings.Join([]string{
+		"*To Do*",
+		"• <https://jira.example.com/browse/ABC-1?a=1&amp;b=2|ABC-1>: Fix &lt;script&gt; &amp; tags",
+		"• ABC-2: Second",
+		"",
+		"*R&amp;D*",
+		"• ABC-3: Third",
+		"",
+		"*Unknown*",
+		"• ABC-4",
+	}, "\n")
+	if got := buildSlack(issues, summaryFormat{ellipsis: asciiEllipsis}, 0); got != want {
+		t.Errorf("buildSlack =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestBuildJSONLinesRoundTrip(t *testing.T) {
+	points := 3.0
+	issues := []jira.Issue{
+		{Key: "ABC-1", Summary: "First\nwith a newline", Status: "To Do", URL: "https://jira.example.com/browse/ABC-1", StoryPoints: &points},
+		{Key: "ABC-2", Summary: "Second", Status: "Done", Parent: "ABC-9", Assignee: "Pat Lee"},
+	}
+	out, err := buildJSONLines(issues)
+	if err != nil {
+		t.Fatalf("buildJSONLines: %v", err)
+	}
+	lines := strings.Split(strings.TrimSuffix(out, "\n"), "\n")
+	if len(lines) != len(issues) {
+		t.Fatalf("buildJSONLines wrote %d lines, want %d:\n%s", len(lines), len(issues), out)
+	}
+	for i, line := range lines {
+		var got jira.Issue
+		if err := json.Unmarshal([]byte(line), &got); err != nil {
+			t.Fatalf("line %d: %v", i+1, err)
+		}
+		if !reflect.DeepEqual(got, issues[i]) {
+			t.Errorf("line %d decodes to %+v, want %+v", i+1, got, issues[i])
+		}
+	}
+	if out, err := buildJSONLines(nil); err != nil || out != "" {
+		t.Errorf("buildJSONLines(nil) = %q, %v; want no output", out, err)
+	}
+}
+
+func TestContextError(t *testing.T) {
+	live := context.Background()
+	expired, cancelExpired := context.WithDeadline(live, time.Now().Add(-time.Second))
+	defer cancelExpired()
+	canceled, cancel := context.WithCancel(live)
+	cancel()
+	failure := errors.New("search jira issues: boom")
+
+	for _, tc := range []struct {
+		name     string
+		ctx      context.Context
+		deadline time.Duration
+		err      error
+		want     string
+		wantIs   error
+	}{
+		{"live context", live, time.Minute, failure, "search jira issues: boom", failure},
+		{"deadline exceeded", expired, time.Minute, failure, "report exceeded -deadline of 1m0s: search jira issues: boom", failure},
+		{"timeout without -deadline", expired, 0, failure, "search jira issues: boom", failure},
+		{"interrupted", canceled, time.Minute, failure, "interrupted: search jira issues: boom", jira.ErrInterrupted},
+		{"already interrupted", canceled, 0, fmt.Errorf("%w while searching", jira.ErrInterrupted), "interrupted while searching", jira.ErrInterrupted},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got := contextError(tc.ctx, tc.deadline, tc.err)
+			if got == nil || got.Error() != tc.want || !errors.Is(got, tc.wantIs) {
+				t.Errorf("contextError = %v, want %q wrapping %v", got, tc.want, tc.wantIs)
+			}
+		})
+	}
+	if err := contextError(canceled, time.Minute, nil); err != nil {
+		t.Errorf("contextError(nil) = %v, want nil", err)
+	}
+}
+
+func TestSummaryParentPrefix(t *testing.T) {
+	epicChild := jira.Issue{Summary: "Write the docs", Parent: "ABC-9", ParentType: "Epic", ParentSummary: "Launch"}
+	subtask := jira.Issue{Summary: "Review", Parent: "ABC-3", ParentType: "Task"}
+	for _, tc := range []struct {
+		name  string
+		sf    summaryFormat
+		issue jira.Issue
+		want  string
+	}{
+		{"parent key", summaryFormat{}, epicChild, "ABC-9 / Write the docs"},
+		{"parent summary", summaryFormat{parentSummary: true}, epicChild, "Launch / Write the docs"},
+		{"parent without a summary", summaryFormat{parentSummary: true}, subtask, "ABC-3 / Review"},
+		{"no prefix", summaryFormat{parentSummary: true, noParentPrefix: true}, epicChild, "Write the docs"},
+		{"no parent", summaryFormat{parentSummary: true}, jira.Issue{Summary: "Alone"}, "Alone"},
+	} {
+		if got := tc.sf.summary(tc.issue, 0); got != tc.want {
+			t.Errorf("%s: summary = %q, want %q", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestNoParentPrefixFormats(t *testing.T) {
+	fake := newFakeJira(t, fakeIssue{id: "1", key: "ABC-1", status: "Done", parent: "ABC-9"})
+	cfgPath := writeTestConfig(t, fake.URL)
+	for _, format := range []string{"table", "tsv", "csv", "md", "docs", "html", "confluence", "slack", "brief", "sheets"} {
+		t.Run(format, func(t *testing.T) {
+			for _, noPrefix := range []bool{false, true} {
+				args := []string{"-config", cfgPath, "-f", fmt.Sprint(fakeFilterID), "-format", format, "-no-color", "-no-clipboard"}
+				if noPrefix {
+					args = append(args, "-no-parent-prefix")
+				}
+				out, err := runCapture(t, args...)
+				if err != nil {
+					t.Fatalf("run: %v", err)
+				}
+				if prefixed := strings.Contains(out, "ABC-9 / Summary of ABC-1"); prefixed == noPrefix || !strings.Contains(out, "Summary of ABC-1") {
+					t.Errorf("-no-parent-prefix=%v printed:\n%s", noPrefix, out)
+				}
+			}
+		})
+	}
+}
+
+func TestHTMLClipboardScript(t *testing.T) {
+	for content, want := range map[string]string{
+		"<b>ok</b>": "set the clipboard to «data HTML3C623E6F6B3C2F623E»",
+		`"é"`:       "set the clipboard to «data HTML22C3A922»",
+		"":          "set the clipboard to «data HTML»",
+	} {
+		if got := htmlClipboardScript(content); got != want {
+			t.Errorf("htmlClipboardScript(%q) = %q, want %q", content, got, want)
+		}
+	}
+}
+
+func TestLookToolReportsMissingTool(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+	_, err := lookTool("pbcopy")
+	var missing *missingToolError
+	if !errors.As(err, &missing) || err.Error() != "pbcopy not found" {
+		t.Errorf("lookTool error = %v, want a *missingToolError for pbcopy", err)
+	}
+}
+
+func TestWarnClipboardFailure(t *testing.T) {
+	missing := captureStderr(t, func() {
+		warnClipboardFailure("table", fmt.Errorf("copy: %w", &missingToolError{name: "textutil"}), "wkreport -docs ... | pbcopy -Prefer html")
+	})
+	if want := "Warning: textutil not found; falling back to stdout.\n"; missing != want {
+		t.Errorf("missing tool warning = %q, want %q", missing, want)
+	}
+
+	failed := captureStderr(t, func() {
+		warnClipboardFailure("table", errors.New("exit status 1"), "wkreport -docs ... | pbcopy -Prefer html")
+	})
+	want := "Warning: failed to copy table to clipboard (exit status 1).\nTip: run `wkreport -docs ... | pbcopy -Prefer html` manually.\n"
+	if failed != want {
+		t.Errorf("failure warning = %q, want %q", failed, want)
+	}
+}
+
+func TestBuildSlidesNestsByParent(t *testing.T) {
+	issues := []jira.Issue{
+		{Key: "ABC-1", Summary: "Epic work", Status: "To Do"},
+		{Key: "ABC-2", Summary: "Child of ABC-1", Status: "To Do", Parent: "ABC-1"},
+		{Key: "ABC-3", Summary: "Child of an epic elsewhere", Status: "To Do", Parent: "ABC-9", ParentSummary: "Launch", ParentURL: "https://jira.example.com/browse/ABC-9"},
+		{Key: "ABC-4", Summary: "Loose", Status: "To Do"},
+		{Key: "ABC-5", Summary: "Second child of ABC-1", Status: "To Do", Parent: "ABC-1"},
+	}
+	plain, htmlContent := buildSlidesContent(groupByStatus(issues), summaryFormat{ellipsis: asciiEllipsis}, 150, "", nil)
+
+	wantPlain := strings.Join([]string{
+		"To Do",
+		"- ABC-1: Epic work",
+		"  - ABC-2: Child of ABC-1",
+		"  - ABC-5: Second child of ABC-1",
+		"- ABC-9: Launch",
+		"  - ABC-3: Child of an epic elsewhere",
+		"- ABC-4: Loose",
+	}, "\n")
+	if plain != wantPlain {
+		t.Errorf("plain slides =\n%s\nwant\n%s", plain, wantPlain)
+	}
+	for _, want := range []string{
+		"<li>ABC-1: Epic work\n    <ul>\n      <li>ABC-2: Child of ABC-1</li>\n      <li>ABC-5: Second child of ABC-1</li>\n    </ul>\n  </li>\n",
+		`<li><a href="https://jira.example.com/browse/ABC-9">ABC-9</a>: Launch`,
+		"  <li>ABC-4: Loose</li>\n</ul>\n",
+	} {
+		if !strings.Contains(htmlContent, want) {
+			t.Errorf("html slides are missing %q:\n%s", want, htmlContent)
+		}
+	}
+}
+
+func TestReportFetchFailuresGroupsErrors(t *testing.T) {
+	partial := &jira.PartialResultError{}
+	for i := 1; i <= 7; i++ {
+		id := fmt.Sprint(i)
+		partial.Failures = append(partial.Failures, jira.IssueFailure{ID: id, Err: fmt.Errorf("issue %s: 404 Not Found", id)})
+	}
+	partial.Failures = append(partial.Failures, jira.IssueFailure{ID: "8", Err: errors.New("issue took longer than 30s")})
+
+	got := captureStderr(t, func() { reportFetchFailures(partial) })
+	want := "Warning: 8 issue(s) could not be fetched and were skipped:\n" +
+		"  7 issues (1, 2, 3, 4, 5, and 2 more): issue *: 404 Not Found\n" +
+		"  8: issue took longer than 30s\n"
+	if got != want {
+		t.Errorf("reportFetchFailures printed\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestReportFetchFailuresCapsLines(t *testing.T) {
+	partial := &jira.PartialResultError{}
+	for i := 1; i <= maxFailureLines+3; i++ {
+		partial.Failures = append(partial.Failures, jira.IssueFailure{ID: fmt.Sprint(i), Err: fmt.Errorf("error %d", i)})
+	}
+	got := captureStderr(t, func() { reportFetchFailures(partial) })
+	lines := strings.Split(strings.TrimSuffix(got, "\n"), "\n")
+	if len(lines) != maxFailureLines+2 {
+		t.Fatalf("reportFetchFailures printed %d lines, want %d:\n%s", len(lines), maxFailureLines+2, got)
+	}
+	if lines[1] != "  1: error 1" || lines[len(lines)-1] != "  (and 3 more)" {
+		t.Errorf("reportFetchFailures printed\n%s", got)
+	}
+}
+
+func TestResolveSummaryWidth(t *testing.T) {
+	widths := map[string]int{"table": 120, "docs": 200, "slides": 60, "tsv": 0}
+	for _, tc := range []struct {
+		format reportFormat
+		args   []string
+		widths map[string]int
+		want   int
+	}{
+		{formatTable, nil, widths, 120},
+		{"", nil, widths, 120},
+		{formatDocs, nil, widths, 200},
+		{formatSlides, nil, widths, 60},
+		{formatTSV, nil, widths, 0},
+		{formatMarkdown, nil, widths, defaultSummaryWidth},
+		{formatCSV, nil, widths, 0},
+		{formatSlides, nil, nil, 80},
+		{formatCSV, []string{"-width", "30"}, widths, 30},
+		{formatDocs, []string{"-width", "30"}, widths, 30},
+		{formatSlides, []string{"-width", "30", "-bullet-width", "40"}, widths, 30},
+		{formatSlides, []string{"-bullet-width", "40"}, widths, 40},
+		{formatTable, []string{"-bullet-width", "40"}, widths, 120},
+		{formatSheets, nil, map[string]int{"SHEETS": 25}, 25},
+	} {
+		var rf reportFlags
+		flags := newReportFlagSet(&rf)
+		if err := flags.Parse(tc.args); err != nil {
+			t.Fatalf("parse %q: %v", tc.args, err)
+		}
+		if got := resolveSummaryWidth(tc.format, tc.widths, &rf, flags); got != tc.want {
+			t.Errorf("resolveSummaryWidth(%q, %v, %q) = %d, want %d", tc.format, tc.widths, tc.args, got, tc.want)
+		}
+	}
+
+	for _, tc := range []struct {
+		widths  map[string]int
+		wantErr bool
+	}{
+		{widths, false},
+		{map[string]int{"table": 3}, true},
+		{map[string]int{"json": 80}, true},
+		{map[string]int{"pdf": 80}, true},
+	} {
+		if err := checkWidths(tc.widths); (err != nil) != tc.wantErr {
+			t.Errorf("checkWidths(%v) = %v, want error %v", tc.widths, err, tc.wantErr)
+		}
+	}
+
+	fake := newFakeJira(t, fakeIssue{id: "1", key: "ABC-1", status: "Done"})
+	cfgPath := writeTestConfig(t, fake.URL)
+	config, err := os.ReadFile(cfgPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(cfgPath, append(config, "output:\n  widths: {tsv: 10, md: 12}\n"...), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		args []string
+		want string
+	}{
+		{[]string{"-format", "tsv"}, "SUMMARY\nSummary...\n"},
+		{[]string{"-format", "md"}, "| Summary |\n| --- |\n| Summary o... |\n"},
+		{[]string{"-format", "tsv", "-width", "0"}, "SUMMARY\nSummary of ABC-1\n"},
+	} {
+		out, err := runCapture(t, append([]string{"-config", cfgPath, "-f", fmt.Sprint(fakeFilterID), "-fields", "summary", "-ellipsis", "ascii", "-no-clipboard"}, tc.args...)...)
+		if err != nil {
+			t.Fatalf("%q: %v", tc.args, err)
+		}
+		if out != tc.want {
+			t.Errorf("%q printed %q, want %q", tc.args, out, tc.want)
+		}
+	}
+}
+
+func TestBulletWidth(t *testing.T) {
+	long := strings.TrimSpace(strings.Repeat("word ", 40))
+	issues := []jira.Issue{{Key: "ABC-1", Summary: long, Status: "To Do"}}
+	columns, err := parseColumns("key,summary", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sf := summaryFormat{ellipsis: asciiEllipsis}
+	for _, tc := range []struct {
+		args                  []string
+		wantSlides, wantTable int
+	}{
+		{nil, 80, defaultSummaryWidth},
+		{[]string{"-bullet-width", "20"}, 20, defaultSummaryWidth},
+	} {
+		var rf reportFlags
+		flags := newReportFlagSet(&rf)
+		if err := flags.Parse(tc.args); err != nil {
+			t.Fatalf("parse %q: %v", tc.args, err)
+		}
+		slidesWidth := resolveSummaryWidth(formatSlides, nil, &rf, flags)
+		tableWidth := resolveSummaryWidth(formatTSV, nil, &rf, flags)
+		if slidesWidth != tc.wantSlides || tableWidth != tc.wantTable {
+			t.Errorf("%q: widths slides %d, table %d, want %d, %d", tc.args, slidesWidth, tableWidth, tc.wantSlides, tc.wantTable)
+			continue
+		}
+
+		plain, _ := buildSlidesContent(groupByStatus(issues), sf, slidesWidth, "", nil)
+		if want := "To Do\n- ABC-1: " + truncate(long, tc.wantSlides, asciiEllipsis); plain != want {
+			t.Errorf("%q: slides = %q, want %q", tc.args, plain, want)
+		}
+		table := buildTabDelimited(issues, columns, sf, tableWidth)
+		if want := "KEY\tSUMMARY\nABC-1\t" + truncate(long, tc.wantTable, asciiEllipsis) + "\n"; table != want {
+			t.Errorf("%q: table = %q, want %q", tc.args, table, want)
+		}
+	}
+}
+
+func TestGroupByAssignee(t *testing.T) {
+	issues := []jira.Issue{
+		{Key: "ABC-1", Summary: "One", Status: "Done", Assignee: "Sam Roe"},
+		{Key: "ABC-2", Summary: "Two", Status: "To Do"},
+		{Key: "ABC-3", Summary: "Three", Status: "To Do", Assignee: "Ann Bo"},
+		{Key: "ABC-4", Summary: "Four", Status: "Done", Assignee: "Ann Bo"},
+		{Key: "ABC-5", Summary: "Five", Status: "Done", Assignee: " "},
+	}
+	groups := groupByAssignee(issues, []sortKey{{field: "status"}})
+	type group struct {
+		name string
+		keys []string
+	}
+	var got []group
+	for _, g := range groups {
+		var keys []string
+		for _, issue := range g.issues {
+			keys = append(keys, issue.Key)
+		}
+		got = append(got, group{g.name, keys})
+	}
+	want := []group{
+		{"Ann Bo", []string{"ABC-4", "ABC-3"}},
+		{"Sam Roe", []string{"ABC-1"}},
+		{unassignedGroup, []string{"ABC-5", "ABC-2"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("groupByAssignee = %+v, want %+v", got, want)
+	}
+	if got, want := groupCounts("assignee", groups), "Issues by assignee: Ann Bo: 2, Sam Roe: 1, Unassigned: 2"; got != want {
+		t.Errorf("groupCounts = %q, want %q", got, want)
+	}
+
+	plain, _ := buildSlidesContent(groups, summaryFormat{}, 0, "", []string{groupCounts("assignee", groups)})
+	if !strings.HasPrefix(plain, "Ann Bo\n- ABC-4: Four\n- ABC-3: Three\n") || !strings.HasSuffix(plain, "Issues by assignee: Ann Bo: 2, Sam Roe: 1, Unassigned: 2") {
+		t.Errorf("slides grouped by assignee:\n%s", plain)
+	}
+}
+
+func TestBuildSlidesBreaksBetweenGroups(t *testing.T) {
+	issues := []jira.Issue{
+		{Key: "ABC-1", Summary: "First", Status: "To Do"},
+		{Key: "ABC-2", Summary: "Second", Status: "Done"},
+		{Key: "ABC-3", Summary: "Third", Status: "Blocked"},
+	}
+	groups := groupByStatus(issues)
+	sf := summaryFormat{ellipsis: asciiEllipsis}
+
+	for _, tc := range []struct {
+		flag string
+		want string
+	}{
+		{"", "To Do\n- ABC-1: First\n\nDone\n- ABC-2: Second\n\nBlocked\n- ABC-3: Third"},
+		{"---", "To Do\n- ABC-1: First\n---\nDone\n- ABC-2: Second\n---\nBlocked\n- ABC-3: Third"},
+		{"FF", "To Do\n- ABC-1: First\n\f\nDone\n- ABC-2: Second\n\f\nBlocked\n- ABC-3: Third"},
+	} {
+		plain, htmlContent := buildSlidesContent(groups, sf, 0, slideSeparator(tc.flag), nil)
+		if plain != tc.want {
+			t.Errorf("-slide-break %q: plain slides = %q, want %q", tc.flag, plain, tc.want)
+		}
+		wantBreaks := 2
+		if tc.flag == "" {
+			wantBreaks = 0
+		}
+		if n := strings.Count(htmlContent, slidePageBreak); n != wantBreaks {
+			t.Errorf("-slide-break %q: html has %d page breaks, want %d", tc.flag, n, wantBreaks)
+		}
+		if n := strings.Index(htmlContent, slidePageBreak); n >= 0 && n < strings.Index(htmlContent, "<h2>") {
+			t.Errorf("-slide-break %q: html starts with a page break:\n%s", tc.flag, htmlContent)
+		}
+	}
+}
+
+// fakeTool installs an executable shell script called name as the only tool on PATH.
+func fakeTool(t *testing.T, name, script string) {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte("#!/bin/sh\n"+script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir)
+}
+
+func TestOpenFilter(t *testing.T) {
+	opener := map[string]string{"darwin": "open", "linux": "xdg-open"}[runtime.GOOS]
+	if opener == "" {
+		t.Skipf("opening a browser is not supported on %s", runtime.GOOS)
+	}
+	fake := newFakeJira(t)
+	cfgPath := writeTestConfig(t, fake.URL)
+	link := fake.URL + "/issues/?jql=project+%3D+ABC+ORDER+BY+key"
+
+	argsFile := filepath.Join(t.TempDir(), "args")
+	fakeTool(t, opener, `echo "$@" > `+argsFile)
+	var out string
+	stderr := captureStderr(t, func() {
+		var err error
+		if out, err = runCapture(t, "open", "-config", cfgPath, fmt.Sprint(fakeFilterID)); err != nil {
+			t.Errorf("open: %v", err)
+		}
+	})
+	if args, _ := os.ReadFile(argsFile); string(args) != link+"\n" {
+		t.Errorf("%s args = %q, want %q", opener, args, link)
+	}
+	if out != "" || !strings.Contains(stderr, "Opened "+link) {
+		t.Errorf("open printed %q and %q, want only the opened link on stderr", out, stderr)
+	}
+
+	fakeTool(t, opener, "exit 1")
+	stderr = captureStderr(t, func() {
+		var err error
+		if out, err = runCapture(t, "open", "-config", cfgPath, "-f", fmt.Sprint(fakeFilterID)); err != nil {
+			t.Errorf("open: %v", err)
+		}
+	})
+	if out != link+"\n" || !strings.Contains(stderr, "unable to open a browser") {
+		t.Errorf("failed open printed %q and %q, want the link and a warning", out, stderr)
+	}
+
+	out, err := runCapture(t, "-config", cfgPath, "-f", fmt.Sprint(fakeFilterID), "-browse")
+	if err != nil {
+		t.Fatalf("-browse: %v", err)
+	}
+	if out != link+"\n" {
+		t.Errorf("-browse printed %q, want %q", out, link)
+	}
+	if got := fake.detailRequests(); len(got) != 0 {
+		t.Errorf("-browse fetched issue details %v", got)
+	}
+}
+
+func TestNoClipboard(t *testing.T) {
+	// /dev/null is a character device, so it passes for a terminal.
+	devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		t.Skipf("open %s: %v", os.DevNull, err)
+	}
+	defer devNull.Close()
+	if !isTerminal(devNull) {
+		t.Skipf("%s is not a character device here", os.DevNull)
+	}
+	for _, tc := range []struct {
+		rf   reportFlags
+		want bool
+	}{
+		{reportFlags{}, true},
+		{reportFlags{noClipboard: true}, false},
+		{reportFlags{watch: time.Minute}, false},
+		{reportFlags{sectioned: true}, false},
+	} {
+		if got := copiesToClipboard(devNull, &tc.rf); got != tc.want {
+			t.Errorf("copiesToClipboard(terminal, %+v) = %v, want %v", tc.rf, got, tc.want)
+		}
+	}
+
+	fake := newFakeJira(t, fakeIssue{id: "1", key: "ABC-1", status: "Done"})
+	calls := filepath.Join(t.TempDir(), "calls")
+	fakeTool(t, "pbcopy", "echo pbcopy >> "+calls)
+	var out string
+	stderr := captureStderr(t, func() {
+		if out, err = runCapture(t, "-config", writeTestConfig(t, fake.URL), "-f", fmt.Sprint(fakeFilterID), "-format", "tsv", "-fields", "key", "-no-clipboard"); err != nil {
+			t.Errorf("run: %v", err)
+		}
+	})
+	if out != "KEY\nABC-1\n" {
+		t.Errorf("stdout = %q, want the tsv report", out)
+	}
+	if stderr != "" {
+		t.Errorf("-no-clipboard printed %q to stderr, want no pbcopy hint", stderr)
+	}
+	if _, err := os.Stat(calls); err == nil {
+		t.Error("-no-clipboard ran pbcopy")
+	}
+}
+
+func TestVerifyClipboard(t *testing.T) {
+	argsFile := filepath.Join(t.TempDir(), "args")
+	fakeTool(t, "pbpaste", `echo "$@" > `+argsFile+`; echo pasted`)
+	if err := verifyClipboard("rtf"); err != nil {
+		t.Fatalf("verifyClipboard: %v", err)
+	}
+	if args, _ := os.ReadFile(argsFile); string(args) != "-Prefer rtf\n" {
+		t.Errorf("pbpaste args = %q, want -Prefer rtf", args)
+	}
+
+	fakeTool(t, "pbpaste", "echo '  '")
+	if err := verifyClipboard(""); err == nil || err.Error() != "clipboard is empty after copying" {
+		t.Errorf("verifyClipboard with an empty clipboard = %v", err)
+	}
+
+	fakeTool(t, "pbpaste", "exit 1")
+	if err := verifyClipboard(""); err == nil || !strings.Contains(err.Error(), "read back clipboard") {
+		t.Errorf("verifyClipboard with a failing pbpaste = %v", err)
+	}
+
+	// HTML is read back through osascript, which is missing here.
+	var missing *missingToolError
+	if err := verifyClipboard("html"); !errors.As(err, &missing) || missing.name != "osascript" {
+		t.Errorf("verifyClipboard(html) = %v, want osascript reported missing", err)
+	}
+}
+
+func TestBuildDocsHTMLTableStyles(t *testing.T) {
+	columns, err := parseColumns("key,summary", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	issues := []jira.Issue{{Key: "ABC-1", Summary: "R&D", URL: "https://jira.example.com/browse/ABC-1"}}
+	body := "\n  <tr><td>KEY</td><td>SUMMARY</td></tr>\n" +
+		`  <tr><td><a href="https://jira.example.com/browse/ABC-1">ABC-1</a></td><td>R&amp;D</td></tr>` +
+		"\n</table>"
+	for style, tag := range map[string]string{
+		"bordered": `<table border="1" cellspacing="0" cellpadding="4">`,
+		"minimal":  `<table cellspacing="0" cellpadding="4">`,
+		"plain":    `<table>`,
+	} {
+		if got := buildDocsHTML(issues, columns, summaryFormat{}, 0, tableStyles[style]); got != tag+body {
+			t.Errorf("-table-style %s:\n%s\nwant\n%s", style, got, tag+body)
+		}
+	}
+
+	fake := newFakeJira(t)
+	_, err = runCapture(t, "-config", writeTestConfig(t, fake.URL), "-f", fmt.Sprint(fakeFilterID), "-table-style", "fancy")
+	if err == nil || !strings.Contains(err.Error(), `unknown -table-style "fancy"`) {
+		t.Errorf("-table-style fancy error = %v", err)
+	}
+}
+
+func TestBrief(t *testing.T) {
+	issues := []jira.Issue{
+		{Key: "ABC-1", Summary: "Ship the weekly report", Status: "In Progress", Resolved: "2026-10-07"},
+		{Key: "ABC-2", Summary: "Fix the build", Status: "Done"},
+	}
+	if got, want := buildBrief(issues, summaryFormat{ellipsis: asciiEllipsis}, 13), "ABC-1\tShip the w...\nABC-2\tFix the build\n"; got != want {
+		t.Errorf("buildBrief = %q, want %q", got, want)
+	}
+
+	fake := newFakeJira(t, fakeIssue{id: "1", key: "ABC-1", status: "Done", parent: "ABC-9", resolved: "2026-10-07T15:04:05.000+0000"})
+	cfgPath := writeTestConfig(t, fake.URL)
+	for _, args := range [][]string{{"-brief"}, {"-format", "brief"}} {
+		out, err := runCapture(t, append([]string{"-config", cfgPath, "-f", fmt.Sprint(fakeFilterID)}, args...)...)
+		if err != nil {
+			t.Fatalf("%q: %v", args, err)
+		}
+		if want := "ABC-1\tABC-9 / Summary of ABC-1\n"; out != want {
+			t.Errorf("%q printed %q, want only the key and summary %q", args, out, want)
+		}
+	}
+}
+
+func TestBuildSheets(t *testing.T) {
+	columns, err := parseColumns("key,summary,resolved,updated", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	issues := []jira.Issue{
+		{Key: "ABC-1", Summary: `Say "hi"`, URL: `https://jira.example.com/browse/ABC-1`, Resolved: "2026-10-07 15:04", Updated: "2026-10-08 09:30"},
+		{Key: "ABC-2", Summary: "No link", Resolved: "", Updated: "2026-10-08"},
+	}
+	want := "KEY\tSUMMARY\tRESOLVED\tUPDATED\n" +
+		`=HYPERLINK("https://jira.example.com/browse/ABC-1","ABC-1")` + "\tSay \"hi\"\t2026-10-07\t2026-10-08\n" +
+		"ABC-2\tNo link\t\t2026-10-08\n"
+	if got := buildSheets(issues, columns, summaryFormat{}, 0); got != want {
+		t.Errorf("buildSheets:\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestBuildPorcelain(t *testing.T) {
+	points := 3.0
+	issues := []jira.Issue{
+		{
+			Key: "ABC-1", Status: "Done", Resolved: "2026-10-07 15:04", Updated: "2026-10-08 09:30", Parent: "ABC-9",
+			Assignee: "Pat Lee", Type: "Story", Priority: "High", URL: "https://jira.example.com/browse/ABC-1",
+			Summary: "Ship\tthe\nthing ", StoryPoints: &points,
+		},
+		{Key: "ABC-2", Status: "To Do", Summary: "Plan next"},
+	}
+	want := "ABC-1\tDone\t2026-10-07 15:04\t2026-10-08 09:30\tABC-9\tPat Lee\tStory\tHigh\thttps://jira.example.com/browse/ABC-1\tShip the thing\n" +
+		"ABC-2\tTo Do\t\t\t\t\t\t\t\tPlan next\n"
+	if got := buildPorcelain(issues); got != want {
+		t.Errorf("buildPorcelain:\n%q\nwant\n%q", got, want)
+	}
+
+	long := strings.TrimSpace(strings.Repeat("long summary ", 30))
+	if got := buildPorcelain([]jira.Issue{{Key: "ABC-3", Summary: long}}); !strings.HasSuffix(got, "\t"+long+"\n") {
+		t.Errorf("buildPorcelain truncated a %d-character summary: %q", len(long), got)
+	}
+
+	fake := newFakeJira(t, fakeIssue{id: "1", key: "ABC-1", status: "Done"})
+	out, err := runCapture(t, "-config", writeTestConfig(t, fake.URL), "-f", fmt.Sprint(fakeFilterID), "-porcelain")
+	if err != nil {
+		t.Fatalf("-porcelain: %v", err)
+	}
+	if want := "ABC-1\tDone\t\t\t\t\t\t\t" + fake.URL + "/browse/ABC-1\tSummary of ABC-1\n"; out != want {
+		t.Errorf("-porcelain printed %q, want %q with no header", out, want)
+	}
+}
+
+func TestBuildDigest(t *testing.T) {
+	issues := []jira.Issue{
+		{Key: "ABC-1", Status: "Done"},
+		{Key: "ABC-2", Status: "Done"},
+		{Key: "ABC-5", Status: "Done"},
+		{Key: "ABC-3", Status: "In Progress"},
+		{Key: "ABC-4", Status: ""},
+	}
+	for _, tc := range []struct {
+		keysPerLine int
+		want        string
+	}{
+		{0, "Done (3): ABC-1, ABC-2, ABC-5\nIn Progress (1): ABC-3\nUnknown (1): ABC-4\n"},
+		{3, "Done (3): ABC-1, ABC-2, ABC-5\nIn Progress (1): ABC-3\nUnknown (1): ABC-4\n"},
+		{2, "Done (3): ABC-1, ABC-2,\n  ABC-5\nIn Progress (1): ABC-3\nUnknown (1): ABC-4\n"},
+		{1, "Done (3): ABC-1,\n  ABC-2,\n  ABC-5\nIn Progress (1): ABC-3\nUnknown (1): ABC-4\n"},
+	} {
+		if got := buildDigest(issues, tc.keysPerLine); got != tc.want {
+			t.Errorf("buildDigest(%d keys per line):\n%s\nwant\n%s", tc.keysPerLine, got, tc.want)
+		}
+	}
+
+	fake := newFakeJira(t,
+		fakeIssue{id: "1", key: "ABC-1", status: "To Do"},
+		fakeIssue{id: "2", key: "ABC-2", status: "Done"},
+		fakeIssue{id: "3", key: "ABC-3", status: "To Do"},
+	)
+	out, err := runCapture(t, "-config", writeTestConfig(t, fake.URL), "-f", fmt.Sprint(fakeFilterID), "-digest")
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if want := "Done (1): ABC-2\nTo Do (2): ABC-1, ABC-3\n"; out != want {
+		t.Errorf("-digest output = %q, want %q", out, want)
+	}
+}
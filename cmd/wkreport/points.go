@@ -0,0 +1,40 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"wkreport/internal/jira"
+)
+
+// pointsFooter totals story points overall and per status group, in statusOrder, as a
+// line such as "Story points: 18 pts total (In Progress: 13 pts, Done: 5 pts)". It
+// returns "" when no issue carries points; unset and zero points count as zero.
+func pointsFooter(issues []jira.Issue, statusOrder []sortKey) string {
+	sorted := append([]jira.Issue(nil), issues...)
+	sortIssues(sorted, statusOrder)
+
+	var total float64
+	hasPoints := false
+	groups := make([]string, 0)
+	for _, group := range groupByStatus(sorted) {
+		var sum float64
+		for _, issue := range group.issues {
+			if issue.StoryPoints != nil && *issue.StoryPoints != 0 {
+				sum += *issue.StoryPoints
+				hasPoints = true
+			}
+		}
+		total += sum
+		groups = append(groups, fmt.Sprintf("%s: %s pts", group.name, formatPoints(sum)))
+	}
+	if !hasPoints {
+		return ""
+	}
+	return fmt.Sprintf("Story points: %s pts total (%s)", formatPoints(total), strings.Join(groups, ", "))
+}
+
+func formatPoints(points float64) string {
+	return strconv.FormatFloat(points, 'f', -1, 64)
+}
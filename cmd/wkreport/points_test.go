@@ -0,0 +1,46 @@
+package main
+
+import (
+	"testing"
+
+	"wkreport/internal/jira"
+)
+
+func TestPointsFooter(t *testing.T) {
+	points := func(p float64) *float64 { return &p }
+	order := []sortKey{{field: "status"}}
+	for _, tc := range []struct {
+		name   string
+		issues []jira.Issue
+		want   string
+	}{
+		{
+			"per group and total",
+			[]jira.Issue{
+				{Key: "ABC-1", Status: "In Progress", StoryPoints: points(8)},
+				{Key: "ABC-2", Status: "Done", StoryPoints: points(5)},
+				{Key: "ABC-3", Status: "In Progress", StoryPoints: points(5)},
+				{Key: "ABC-4", Status: "Done"},
+				{Key: "ABC-5", Status: "To Do", StoryPoints: points(0.5)},
+			},
+			"Story points: 18.5 pts total (Done: 5 pts, In Progress: 13 pts, To Do: 0.5 pts)",
+		},
+		{
+			"unpointed group",
+			[]jira.Issue{
+				{Key: "ABC-1", Status: "Done", StoryPoints: points(3)},
+				{Key: "ABC-2", Status: "To Do", StoryPoints: points(0)},
+				{Key: "ABC-3", Status: "To Do"},
+			},
+			"Story points: 3 pts total (Done: 3 pts, To Do: 0 pts)",
+		},
+		{"no points", []jira.Issue{{Key: "ABC-1", Status: "Done"}, {Key: "ABC-2", Status: "Done", StoryPoints: points(0)}}, ""},
+		{"no issues", nil, ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := pointsFooter(tc.issues, order); got != tc.want {
+				t.Errorf("pointsFooter = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
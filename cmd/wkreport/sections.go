@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+
+	"wkreport/internal/jira"
+)
+
+// splitFilterRefs splits a -f value such as "18205,18206" into its filter ids. Values
+// that are not a list of numeric ids are returned whole, since filter names and URLs
+// may themselves contain commas.
+func splitFilterRefs(ref string) []string {
+	ref = strings.TrimSpace(ref)
+	if !strings.Contains(ref, ",") {
+		return []string{ref}
+	}
+	parts := strings.Split(ref, ",")
+	ids := make([]string, 0, len(parts))
+	for _, part := range parts {
+		part = strings.TrimSpace(part)
+		if _, err := strconv.Atoi(part); err != nil {
+			return []string{ref}
+		}
+		ids = append(ids, part)
+	}
+	return ids
+}
+
+// mergedFilter names the combined report of several filters.
+func mergedFilter(filters []*jira.Filter) *jira.Filter {
+	names := make([]string, len(filters))
+	for i, filter := range filters {
+		names[i] = filter.Name
+	}
+	return &jira.Filter{Name: strings.Join(names, " + ")}
+}
+
+// checkMultiFilterFlags rejects flags that need a single filter.
+func checkMultiFilterFlags(rf *reportFlags) error {
+	switch {
+	case rf.browseOnly:
+		return errors.New("-browse takes a single filter")
+	case rf.stream:
+		return errors.New("-stream takes a single filter")
+	}
+	return nil
+}
+
+// checkSectionedFlags rejects -sectioned combinations whose output cannot hold several
+// labeled sections.
+func checkSectionedFlags(rf *reportFlags, format reportFormat) error {
+	switch {
+	case format == formatJSON || format == formatJSONL:
+		return fmt.Errorf("-sectioned cannot be combined with -format %s", format)
+	case format == formatDocs || format == formatSlides:
+		return fmt.Errorf("-sectioned cannot be combined with -format %s, which is copied to the clipboard as one document", format)
+	case rf.outputPath != "" || rf.xlsxPath != "" || rf.savePath != "":
+		return errors.New("-sectioned cannot be combined with -o, -xlsx, or -save")
+	case rf.docsTemplate != "":
+		return errors.New("-sectioned cannot be combined with -docs-template, which wraps a single table")
+	case rf.watch != 0 || rf.stream || rf.raw:
+		return errors.New("-sectioned cannot be combined with -watch, -stream, or -raw")
+	}
+	return nil
+}
+
+// sectionHeader labels one filter's section in the given format.
+func sectionHeader(format reportFormat, filter *jira.Filter) string {
+	label := filter.Name
+	if filter.ID > 0 {
+		label = fmt.Sprintf("%s (%d)", filter.Name, filter.ID)
+	}
+	switch format {
+	case formatMarkdown:
+		return "## " + label
+	case formatConfluence:
+		return "h2. " + label
+	case formatSlack:
+		return "*" + label + "*"
+	case formatHTML:
+		return "<h2>" + html.EscapeString(label) + "</h2>"
+	default:
+		return "== " + label + " =="
+	}
+}
+
+// renderSections renders each filter as its own labeled section for -sectioned. Every
+// section is attempted; the first error is returned once all have been printed, and
+// -fail-if-empty applies only when every section is empty.
+func (r *reportRun) renderSections(ctx context.Context) error {
+	filters := r.filters
+	var firstErr error
+	empty := 0
+	for i, filter := range filters {
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Println(sectionHeader(r.format, filter))
+		r.filter, r.filters = filter, []*jira.Filter{filter}
+
+		err := r.fetchAndRender(ctx)
+		var exitErr *exitCodeError
+		switch {
+		case errors.As(err, &exitErr) && exitErr.code == exitEmpty && exitErr.err == nil:
+			empty++
+		case err != nil && firstErr == nil:
+			firstErr = err
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	if firstErr != nil {
+		return firstErr
+	}
+	if empty == len(filters) {
+		return &exitCodeError{code: exitEmpty}
+	}
+	return nil
+}
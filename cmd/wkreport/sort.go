@@ -0,0 +1,182 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"wkreport/internal/jira"
+)
+
+const (
+	// defaultTableSort groups related work under its parent for the table-style outputs.
+	defaultTableSort = "parent,status,key"
+	// defaultStatusSort keeps status groups contiguous for the grouped outputs (slides, slack).
+	defaultStatusSort = "status,key"
+)
+
+type sortKey struct {
+	field string
+	desc  bool
+	// rank, when set, orders values by their position in a configured list instead of
+	// alphabetically. Values missing from it sort after the listed ones.
+	rank map[string]int
+}
+
+// sortFields maps the names accepted by -sort to the issue value they compare.
+var sortFields = map[string]func(jira.Issue) string{
+	"key":      func(issue jira.Issue) string { return issue.Key },
+	"summary":  func(issue jira.Issue) string { return issue.Summary },
+	"status":   func(issue jira.Issue) string { return issue.Status },
+	"parent":   func(issue jira.Issue) string { return issue.Parent },
+	"resolved": func(issue jira.Issue) string { return issue.Resolved },
+	"assignee": func(issue jira.Issue) string { return issue.Assignee },
+}
+
+// timeSortFields are the sort fields holding dates, which compare as times with empty
+// or unparsable values last in either direction.
+var timeSortFields = map[string]bool{"resolved": true}
+
+// parseSortKeys parses a -sort value such as "parent,status,key" or "-resolved,key".
+func parseSortKeys(spec string) ([]sortKey, error) {
+	keys := make([]sortKey, 0)
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.ToLower(strings.TrimSpace(part))
+		if part == "" {
+			continue
+		}
+
+		key := sortKey{field: part}
+		if strings.HasPrefix(part, "-") {
+			key = sortKey{field: strings.TrimPrefix(part, "-"), desc: true}
+		}
+		if _, ok := sortFields[key.field]; !ok {
+			return nil, fmt.Errorf("unknown sort field %q (expected key, summary, status, parent, resolved, or assignee)", key.field)
+		}
+		keys = append(keys, key)
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("sort spec %q has no fields", spec)
+	}
+	return keys, nil
+}
+
+// resolveSortOrders returns the orderings for the table-style and status-grouped outputs.
+// A custom spec applies to both, with status moved to the front for the grouped outputs.
+func resolveSortOrders(spec string) (table, grouped []sortKey, err error) {
+	if strings.TrimSpace(spec) == "" {
+		table, _ = parseSortKeys(defaultTableSort)
+		grouped, _ = parseSortKeys(defaultStatusSort)
+		return table, grouped, nil
+	}
+
+	table, err = parseSortKeys(spec)
+	if err != nil {
+		return nil, nil, err
+	}
+	return table, statusFirst(table), nil
+}
+
+func statusFirst(keys []sortKey) []sortKey {
+	status := sortKey{field: "status"}
+	rest := make([]sortKey, 0, len(keys))
+	for _, key := range keys {
+		if key.field == "status" {
+			status = key
+			continue
+		}
+		rest = append(rest, key)
+	}
+	return append([]sortKey{status}, rest...)
+}
+
+// withStatusOrder applies the configured output.status_order to the status keys.
+func withStatusOrder(keys []sortKey, order []string) []sortKey {
+	if len(order) == 0 {
+		return keys
+	}
+	rank := make(map[string]int, len(order))
+	for _, status := range order {
+		status = strings.ToLower(strings.TrimSpace(status))
+		if _, seen := rank[status]; !seen {
+			rank[status] = len(rank)
+		}
+	}
+
+	ranked := make([]sortKey, len(keys))
+	for i, key := range keys {
+		if key.field == "status" {
+			key.rank = rank
+		}
+		ranked[i] = key
+	}
+	return ranked
+}
+
+// sortIssues stably orders issues by each key in turn, comparing case-insensitively.
+func sortIssues(issues []jira.Issue, keys []sortKey) {
+	sort.SliceStable(issues, func(i, j int) bool {
+		for _, key := range keys {
+			value := sortFields[key.field]
+			if timeSortFields[key.field] {
+				if less, decided := compareTimes(value(issues[i]), value(issues[j]), key.desc); decided {
+					return less
+				}
+				continue
+			}
+			a := strings.TrimSpace(strings.ToLower(value(issues[i])))
+			b := strings.TrimSpace(strings.ToLower(value(issues[j])))
+			if a == b {
+				continue
+			}
+			if key.rank != nil {
+				if ra, rb := key.rankOf(a), key.rankOf(b); ra != rb {
+					if key.desc {
+						return ra > rb
+					}
+					return ra < rb
+				}
+			}
+			if key.desc {
+				return a > b
+			}
+			return a < b
+		}
+		return false
+	})
+}
+
+func (k sortKey) rankOf(value string) int {
+	if r, ok := k.rank[value]; ok {
+		return r
+	}
+	return len(k.rank)
+}
+
+// compareTimes orders two dates for sortIssues, reporting decided false when they are
+// equal or both unparsable.
+func compareTimes(a, b string, desc bool) (less, decided bool) {
+	ta, okA := parseSortTime(a)
+	tb, okB := parseSortTime(b)
+	switch {
+	case !okA || !okB:
+		return okA, okA != okB
+	case ta.Equal(tb):
+		return false, false
+	case desc:
+		return ta.After(tb), true
+	}
+	return ta.Before(tb), true
+}
+
+// parseSortTime parses a date in ResolvedLayout or, after -date-only, dateOnlyLayout.
+func parseSortTime(value string) (time.Time, bool) {
+	value = strings.TrimSpace(value)
+	for _, layout := range []string{jira.ResolvedLayout, dateOnlyLayout} {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			return parsed, true
+		}
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// jqlTimeLayout is the minute-precision format Jira accepts in JQL date comparisons.
+const jqlTimeLayout = "2006-01-02 15:04"
+
+func cacheDir() (string, error) {
+	base, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("locate cache dir: %w", err)
+	}
+	return filepath.Join(base, "wkreport"), nil
+}
+
+func sinceStatePath(filterID int) (string, error) {
+	dir, err := cacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, fmt.Sprintf("since-%d", filterID)), nil
+}
+
+// loadLastRun returns the recorded time of the last successful -since-last-run report for
+// the filter. A zero time means no run has been recorded.
+func loadLastRun(filterID int) (time.Time, error) {
+	path, err := sinceStatePath(filterID)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, fmt.Errorf("read last run state: %w", err)
+	}
+
+	ts, err := time.Parse(time.RFC3339, strings.TrimSpace(string(data)))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("parse last run state %s: %w", path, err)
+	}
+	return ts, nil
+}
+
+func saveLastRun(filterID int, ts time.Time) error {
+	path, err := sinceStatePath(filterID)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("create cache dir: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(ts.Format(time.RFC3339)+"\n"), 0o600); err != nil {
+		return fmt.Errorf("write last run state: %w", err)
+	}
+	return nil
+}
+
+func resetLastRun(filterID int) error {
+	path, err := sinceStatePath(filterID)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("remove last run state: %w", err)
+	}
+	return nil
+}
+
+// updatedSinceClause builds the JQL clause that limits a search to issues updated at or
+// after ts. Jira reads absolute JQL dates in the zone of the Jira user's profile, which
+// need not be this machine's, so the clause uses a relative offset such as "-90m",
+// rounded up to whole minutes so nothing updated at ts is missed.
+func updatedSinceClause(ts, now time.Time) string {
+	minutes := max(int(math.Ceil(now.Sub(ts).Minutes())), 1)
+	return fmt.Sprintf(`updated >= "-%dm"`, minutes)
+}
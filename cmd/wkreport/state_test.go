@@ -0,0 +1,108 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestUpdatedSinceClause(t *testing.T) {
+	now := time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC)
+	for _, tc := range []struct {
+		since time.Time
+		want  string
+	}{
+		{now.Add(-90 * time.Minute), `updated >= "-90m"`},
+		{now.Add(-90*time.Minute - time.Second), `updated >= "-91m"`},
+		{now.Add(-26 * time.Hour), `updated >= "-1560m"`},
+		{now.Add(-10 * time.Second), `updated >= "-1m"`},
+		// A last run recorded ahead of this clock still narrows to the last minute.
+		{now.Add(time.Minute), `updated >= "-1m"`},
+		// The offset does not depend on the zone the last run was recorded in.
+		{now.Add(-2 * time.Hour).In(time.FixedZone("UTC+9", 9*3600)), `updated >= "-120m"`},
+	} {
+		if got := updatedSinceClause(tc.since, now); got != tc.want {
+			t.Errorf("updatedSinceClause(%s) = %s, want %s", tc.since, got, tc.want)
+		}
+	}
+}
+
+func TestLastRunState(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	if last, err := loadLastRun(42); err != nil || !last.IsZero() {
+		t.Fatalf("loadLastRun before any run = %v, %v; want the zero time", last, err)
+	}
+	started := time.Date(2026, 10, 14, 9, 30, 0, 0, time.FixedZone("PDT", -7*3600))
+	if err := saveLastRun(42, started); err != nil {
+		t.Fatal(err)
+	}
+	last, err := loadLastRun(42)
+	if err != nil || !last.Equal(started) {
+		t.Fatalf("loadLastRun = %v, %v; want %v", last, err, started)
+	}
+	if other, err := loadLastRun(43); err != nil || !other.IsZero() {
+		t.Errorf("loadLastRun of another filter = %v, %v; want the zero time", other, err)
+	}
+
+	if err := resetLastRun(42); err != nil {
+		t.Fatal(err)
+	}
+	if last, err := loadLastRun(42); err != nil || !last.IsZero() {
+		t.Errorf("loadLastRun after reset = %v, %v; want the zero time", last, err)
+	}
+	if err := resetLastRun(42); err != nil {
+		t.Errorf("resetLastRun without state: %v", err)
+	}
+
+	path, err := sinceStatePath(42)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("yesterday\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadLastRun(42); err == nil || !strings.Contains(err.Error(), "parse last run state") {
+		t.Errorf("loadLastRun of a corrupt state = %v, want a parse error", err)
+	}
+}
+
+func TestSinceLastRunNarrowsJQL(t *testing.T) {
+	fake := newFakeJira(t, fakeIssue{id: "1", key: "ABC-1", status: "To Do"})
+	cfgPath := writeTestConfig(t, fake.URL)
+	args := []string{"-config", cfgPath, "-f", fmt.Sprint(fakeFilterID), "-format", "porcelain", "-since-last-run"}
+
+	before := time.Now().Truncate(time.Second)
+	for run := range 2 {
+		if _, err := runCapture(t, args...); err != nil {
+			t.Fatalf("run %d: %v", run+1, err)
+		}
+	}
+	last, err := loadLastRun(fakeFilterID)
+	if err != nil || last.Before(before) {
+		t.Fatalf("recorded last run = %v, %v; want a time after %v", last, err, before)
+	}
+
+	if _, err := runCapture(t, append(args, "-reset-since")...); err != nil {
+		t.Fatalf("reset run: %v", err)
+	}
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	if len(fake.searches) != 3 {
+		t.Fatalf("searches = %q, want 3", fake.searches)
+	}
+	// Runs without state use the filter's own search URL.
+	searchURLJQL := "project = ABC"
+	if fake.searches[0] != searchURLJQL {
+		t.Errorf("first search jql = %q, want the filter's search URL", fake.searches[0])
+	}
+	if want := `(project = ABC) AND updated >= "-1m" ORDER BY key`; fake.searches[1] != want {
+		t.Errorf("second search jql = %q, want %q", fake.searches[1], want)
+	}
+	if fake.searches[2] != searchURLJQL {
+		t.Errorf("search after -reset-since jql = %q, want the filter's search URL", fake.searches[2])
+	}
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"strings"
+
+	"wkreport/internal/jira"
+)
+
+// normalizeStatuses rewrites issue statuses so that grouping and sorting see one label
+// per status: aliases are applied first (matched case-insensitively), then variants
+// that differ only by case or surrounding space take the first spelling seen.
+func normalizeStatuses(issues []jira.Issue, aliases map[string]string) {
+	normalizer := newStatusNormalizer(aliases)
+	for i := range issues {
+		issues[i].Status = normalizer.normalize(issues[i].Status)
+	}
+}
+
+// statusNormalizer applies normalizeStatuses one status at a time, remembering the
+// spellings seen so far, for callers that receive issues incrementally.
+type statusNormalizer struct {
+	aliases   map[string]string
+	canonical map[string]string
+}
+
+func newStatusNormalizer(aliases map[string]string) *statusNormalizer {
+	lowered := make(map[string]string, len(aliases))
+	for from, to := range aliases {
+		lowered[strings.ToLower(strings.TrimSpace(from))] = strings.TrimSpace(to)
+	}
+	return &statusNormalizer{aliases: lowered, canonical: make(map[string]string)}
+}
+
+func (n *statusNormalizer) normalize(status string) string {
+	status = strings.Join(strings.Fields(status), " ")
+	if alias, ok := n.aliases[strings.ToLower(status)]; ok {
+		status = alias
+	}
+	fold := strings.ToLower(status)
+	if label, ok := n.canonical[fold]; ok {
+		return label
+	}
+	n.canonical[fold] = status
+	return status
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"slices"
+	"testing"
+
+	"wkreport/internal/jira"
+)
+
+func TestNormalizeStatuses(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		statuses []string
+		aliases  map[string]string
+		want     []string
+	}{
+		{"case and space", []string{"In Review", "in review", " In  Review ", "IN REVIEW"}, nil, []string{"In Review", "In Review", "In Review", "In Review"}},
+		{"first spelling wins", []string{"in review", "In Review"}, nil, []string{"in review", "in review"}},
+		{"aliases", []string{"QA", "In QA", "in qa", "Done"}, map[string]string{"QA": "In Review", " in QA ": "In Review"}, []string{"In Review", "In Review", "In Review", "Done"}},
+		{"alias merges with the status", []string{"in review", "QA"}, map[string]string{"qa": "In Review"}, []string{"in review", "in review"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			issues := make([]jira.Issue, len(tc.statuses))
+			for i, status := range tc.statuses {
+				issues[i].Status = status
+			}
+			normalizeStatuses(issues, tc.aliases)
+			var got []string
+			for _, issue := range issues {
+				got = append(got, issue.Status)
+			}
+			if !slices.Equal(got, tc.want) {
+				t.Errorf("normalizeStatuses(%q) = %q, want %q", tc.statuses, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestNormalizedStatusesShareAGroup(t *testing.T) {
+	issues := []jira.Issue{
+		{Key: "ABC-1", Status: "In Review"},
+		{Key: "ABC-2", Status: "QA"},
+		{Key: "ABC-3", Status: "in review"},
+	}
+	normalizeStatuses(issues, map[string]string{"QA": "In Review"})
+	groups := groupByStatus(issues)
+	if len(groups) != 1 || groups[0].name != "In Review" || len(groups[0].issues) != 3 {
+		t.Errorf("groups = %+v, want one In Review group of 3", groups)
+	}
+}
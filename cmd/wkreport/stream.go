@@ -0,0 +1,86 @@
+package main
+
+import (
+	"errors"
+	"io"
+
+	"wkreport/internal/jira"
+)
+
+// tableStream writes table rows as the client delivers each issue, for -stream. Status
+// normalization, -no-links, and the -status, -current-sprint, -updated-since,
+// -since-days, -open-only, and -resolved-only filters are applied per issue, since the
+// full result set is never held before printing.
+type tableStream struct {
+	out           io.Writer
+	summary       summaryFormat
+	columns       []column
+	colors        statusColors
+	statuses      *statusNormalizer
+	statusFilter  string
+	currentSprint bool
+	updatedSince  string
+	resolvedSince string
+	openOnly      bool
+	resolvedOnly  bool
+	dateOnly      bool
+	noLinks       bool
+
+	// issues are the rows written so far, for the footer and the empty-report check.
+	issues []jira.Issue
+}
+
+// checkStreamFlags rejects -stream combinations that need the whole result set first.
+func checkStreamFlags(rf *reportFlags, format reportFormat) error {
+	switch {
+	case format != "" && format != formatTable:
+		return errors.New("-stream only supports the table format")
+	case rf.sortSpec != "":
+		return errors.New("-stream prints issues in Jira's order and cannot be combined with -sort")
+	case rf.outputPath != "" || rf.xlsxPath != "" || rf.templateFlag != "":
+		return errors.New("-stream cannot be combined with -o, -xlsx, or -template")
+	case rf.watch != 0:
+		return errors.New("-stream cannot be combined with -watch")
+	case rf.numbered || rf.savePath != "":
+		return errors.New("-stream cannot be combined with -numbered or -save")
+	case rf.resolveParent:
+		return errors.New("-stream prints each issue as it arrives and cannot be combined with -resolve-parents")
+	}
+	return nil
+}
+
+func (s *tableStream) add(issue jira.Issue) {
+	issue.Status = s.statuses.normalize(issue.Status)
+	if s.noLinks {
+		issue.URL, issue.ParentURL = "", ""
+	}
+	kept := []jira.Issue{issue}
+	if s.dateOnly {
+		truncateDates(kept)
+	}
+	if s.currentSprint {
+		kept = filterCurrentSprint(kept)
+	}
+	if s.statusFilter != "" {
+		kept = filterStatuses(kept, s.statusFilter)
+	}
+	if s.updatedSince != "" {
+		kept = filterUpdatedSince(kept, s.updatedSince)
+	}
+	if s.resolvedSince != "" {
+		kept = filterResolvedSince(kept, s.resolvedSince)
+	}
+	if s.openOnly || s.resolvedOnly {
+		kept = filterResolution(kept, s.resolvedOnly)
+	}
+	if len(kept) == 0 {
+		return
+	}
+	issue = kept[0]
+
+	if len(s.issues) == 0 {
+		writeTableHeader(s.out, s.columns)
+	}
+	writeTableRow(s.out, issue, len(s.issues)+1, s.columns, s.summary, s.colors)
+	s.issues = append(s.issues, issue)
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"wkreport/internal/jira"
+)
+
+// summaryFormat controls how issue summaries are labelled across the output modes.
+type summaryFormat struct {
+	// parentSummary prefixes summaries with the parent's summary instead of its key.
+	parentSummary bool
+	// noParentPrefix leaves summaries unprefixed, for reports that show the parent column.
+	noParentPrefix bool
+	// ellipsis marks truncated summaries, chosen from -ellipsis or output.ellipsis.
+	ellipsis string
+}
+
+// parentLabel returns the text used to identify an issue's parent in prefixes.
+func (f summaryFormat) parentLabel(issue jira.Issue) string {
+	if f.parentSummary {
+		if summary := strings.TrimSpace(issue.ParentSummary); summary != "" {
+			return summary
+		}
+	}
+	return strings.TrimSpace(issue.Parent)
+}
+
+// summary returns the issue summary prefixed with its parent as "Parent / Summary"
+// (unless noParentPrefix is set), truncated to width. A width of zero leaves the summary
+// untruncated.
+func (f summaryFormat) summary(issue jira.Issue, width int) string {
+	clip := func(s string) string {
+		if width <= 0 {
+			return s
+		}
+		return truncate(s, width, f.ellipsis)
+	}
+	summary := clip(strings.TrimSpace(issue.Summary))
+	if f.noParentPrefix {
+		return summary
+	}
+	if label := f.parentLabel(issue); label != "" {
+		summary = clip(fmt.Sprintf("%s / %s", label, summary))
+	}
+	return summary
+}
+
+// resolveParentSummaries fills in ParentSummary, for -resolve-parents, on issues whose
+// parent Jira sent without one. Every distinct parent is looked up once, in batches; a
+// failed lookup is reported as a warning and leaves those summaries empty.
+func resolveParentSummaries(ctx context.Context, client *jira.Client, issues []jira.Issue) {
+	var keys []string
+	for _, issue := range issues {
+		if issue.Parent != "" && issue.ParentSummary == "" {
+			keys = append(keys, issue.Parent)
+		}
+	}
+	if len(keys) == 0 {
+		return
+	}
+
+	summaries, err := client.IssueSummaries(ctx, keys)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not look up parent summaries: %v\n", err)
+	}
+	for i := range issues {
+		if issues[i].ParentSummary == "" {
+			issues[i].ParentSummary = summaries[issues[i].Parent]
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"cmp"
+	"errors"
+	"flag"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"wkreport/internal/jira"
+)
+
+// countByFields are the -count-by fields, each tallied by its column's value.
+var countByFields = []string{"status", "assignee", "type", "priority", "parent"}
+
+// noValueLabel names the tally row of issues with an empty value.
+const noValueLabel = "(none)"
+
+// parseCountBy returns the column whose values -count-by tallies.
+func parseCountBy(name string) (column, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if !slices.Contains(countByFields, name) {
+		return column{}, fmt.Errorf("unknown -count-by field %q (expected one of %s)", name, strings.Join(countByFields, ", "))
+	}
+	return columnsByName[name], nil
+}
+
+// checkCountByFlags rejects -count-by combinations. Without -count-with-report only the
+// tally is printed, so an explicit output format would be ignored.
+func checkCountByFlags(rf *reportFlags, flags *flag.FlagSet) error {
+	switch {
+	case rf.stream || rf.raw:
+		return errors.New("-count-by cannot be combined with -stream or -raw")
+	case !rf.countReport && modeFlagSet(flags):
+		return errors.New("-count-by prints only the tally; add -count-with-report to render the report as well")
+	}
+	return nil
+}
+
+// tallyRow is one distinct value of the tallied field and how many issues have it.
+type tallyRow struct {
+	value string
+	count int
+}
+
+// buildTally renders a table of how many issues have each distinct value of col, most
+// common first and ties in value order.
+func buildTally(issues []jira.Issue, col column, sf summaryFormat) string {
+	counts := make(map[string]int)
+	for i, issue := range issues {
+		value := strings.TrimSpace(col.value(issue, i+1, sf, 0))
+		if value == "" {
+			value = noValueLabel
+		}
+		counts[value]++
+	}
+	rows := make([]tallyRow, 0, len(counts))
+	for value, count := range counts {
+		rows = append(rows, tallyRow{value: value, count: count})
+	}
+	slices.SortFunc(rows, func(a, b tallyRow) int {
+		return cmp.Or(cmp.Compare(b.count, a.count), strings.Compare(strings.ToLower(a.value), strings.ToLower(b.value)), strings.Compare(a.value, b.value))
+	})
+
+	width := len([]rune(col.header()))
+	for _, row := range rows {
+		width = max(width, len([]rune(row.value)))
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "%-*s %s\n", width, col.header(), "COUNT")
+	for _, row := range rows {
+		fmt.Fprintf(&b, "%-*s %s\n", width, row.value, strconv.Itoa(row.count))
+	}
+	return b.String()
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+
+	"wkreport/internal/jira"
+)
+
+func TestBuildTally(t *testing.T) {
+	issues := []jira.Issue{
+		{Key: "ABC-1", Assignee: "Sam Roe", Type: "Bug", Status: "Done"},
+		{Key: "ABC-2", Assignee: "Ann Bo", Type: "Story", Status: "Done"},
+		{Key: "ABC-3", Type: "Bug", Status: "To Do"},
+		{Key: "ABC-4", Assignee: "Sam Roe", Type: "Task", Status: "To Do"},
+		{Key: "ABC-5", Assignee: "ann bo", Type: "Bug", Status: "Done"},
+		{Key: "ABC-6", Assignee: " ", Type: "Story", Status: "Done"},
+	}
+	for _, tc := range []struct {
+		field string
+		want  string
+	}{
+		{"assignee", "" +
+			"ASSIGNEE COUNT\n" +
+			"(none)   2\n" +
+			"Sam Roe  2\n" +
+			"Ann Bo   1\n" +
+			"ann bo   1\n"},
+		{"type", "" +
+			"TYPE  COUNT\n" +
+			"Bug   3\n" +
+			"Story 2\n" +
+			"Task  1\n"},
+		{" Status ", "" +
+			"STATUS COUNT\n" +
+			"Done   4\n" +
+			"To Do  2\n"},
+	} {
+		t.Run(tc.field, func(t *testing.T) {
+			col, err := parseCountBy(tc.field)
+			if err != nil {
+				t.Fatalf("parseCountBy(%q): %v", tc.field, err)
+			}
+			if got := buildTally(issues, col, summaryFormat{}); got != tc.want {
+				t.Errorf("buildTally by %s:\n%s\nwant\n%s", tc.field, got, tc.want)
+			}
+		})
+	}
+
+	if _, err := parseCountBy("summary"); err == nil {
+		t.Error("parseCountBy(summary) succeeded, want an error")
+	}
+}
+
+func TestCountByPrintsTally(t *testing.T) {
+	fake := newFakeJira(t,
+		fakeIssue{id: "1", key: "ABC-1", status: "Done"},
+		fakeIssue{id: "2", key: "ABC-2", status: "To Do"},
+		fakeIssue{id: "3", key: "ABC-3", status: "Done"},
+	)
+	cfgPath := writeTestConfig(t, fake.URL)
+	args := []string{"-config", cfgPath, "-f", fmt.Sprint(fakeFilterID), "-count-by", "status"}
+	out, err := runCapture(t, args...)
+	if err != nil {
+		t.Fatalf("-count-by: %v", err)
+	}
+	if want := "STATUS COUNT\nDone   2\nTo Do  1\n"; out != want {
+		t.Errorf("-count-by printed:\n%s\nwant\n%s", out, want)
+	}
+	if _, err := runCapture(t, append(args, "-format", "csv")...); err == nil {
+		t.Error("-count-by with -format succeeded, want an error without -count-with-report")
+	}
+}
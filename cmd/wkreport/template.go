@@ -0,0 +1,65 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+
+	"wkreport/internal/jira"
+)
+
+// loadTemplateSource returns the template text for the -template flag. Values prefixed
+// with @ are treated as a path to a template file.
+func loadTemplateSource(value string) (string, error) {
+	if !strings.HasPrefix(value, "@") {
+		return value, nil
+	}
+
+	path := strings.TrimSpace(strings.TrimPrefix(value, "@"))
+	if path == "" {
+		return "", fmt.Errorf("template file path is required after @")
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("read template file: %w", err)
+	}
+	return string(data), nil
+}
+
+// templateFuncs returns the functions available to -template, truncating with the
+// ellipsis marker.
+func templateFuncs(ellipsis string) template.FuncMap {
+	return template.FuncMap{
+		// truncate is argument-swapped so it works in pipelines: {{.Summary | truncate 40}}.
+		"truncate": func(width int, input string) string {
+			return truncate(input, width, ellipsis)
+		},
+		"join": func(items []string, sep string) string {
+			return strings.Join(items, sep)
+		},
+		// link renders a Markdown link, falling back to the plain text when url is empty.
+		"link": func(text, url string) string {
+			url = strings.TrimSpace(url)
+			if url == "" {
+				return text
+			}
+			return fmt.Sprintf("[%s](%s)", text, url)
+		},
+	}
+}
+
+// renderTemplate executes a text/template over the issue slice, whose truncate function
+// ends cut text in ellipsis.
+func renderTemplate(source string, issues []jira.Issue, ellipsis string) (string, error) {
+	tmpl, err := template.New("wkreport").Funcs(templateFuncs(ellipsis)).Parse(source)
+	if err != nil {
+		return "", fmt.Errorf("parse template: %w", err)
+	}
+
+	var b strings.Builder
+	if err := tmpl.Execute(&b, issues); err != nil {
+		return "", fmt.Errorf("execute template: %w", err)
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"wkreport/internal/jira"
+)
+
+func TestRenderTemplate(t *testing.T) {
+	issues := []jira.Issue{
+		{Key: "ABC-1", Summary: "Ship the release notes", Status: "Done", URL: "https://jira.example.com/browse/ABC-1"},
+		{Key: "ABC-2", Summary: "Triage", Status: "To Do"},
+	}
+	source := `{{range .}}- {{link .Key .URL}} {{.Summary | truncate 12}} [{{.Status}}]
+{{end}}`
+	got, err := renderTemplate(source, issues, unicodeEllipsis)
+	if err != nil {
+		t.Fatalf("renderTemplate: %v", err)
+	}
+	want := "- [ABC-1](https://jira.example.com/browse/ABC-1) Ship the re… [Done]\n- ABC-2 Triage [To Do]\n"
+	if got != want {
+		t.Errorf("renderTemplate = %q, want %q", got, want)
+	}
+}
+
+func TestRenderTemplateErrors(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		source string
+		want   string
+	}{
+		{"unclosed action", "{{range .}}{{.Key}}", "parse template"},
+		{"unknown function", "{{shout .}}", `parse template: template: wkreport:1: function "shout" not defined`},
+		{"unknown field", "{{range .}}{{.Missing}}{{end}}", "execute template"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := renderTemplate(tc.source, []jira.Issue{{Key: "ABC-1"}}, asciiEllipsis)
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("renderTemplate error = %v, want one containing %q", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestLoadTemplateSource(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.tmpl")
+	if err := os.WriteFile(path, []byte("{{len .}} issues\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := loadTemplateSource("@" + path); err != nil || got != "{{len .}} issues\n" {
+		t.Errorf("loadTemplateSource(@file) = %q, %v", got, err)
+	}
+	if got, err := loadTemplateSource("{{len .}}"); err != nil || got != "{{len .}}" {
+		t.Errorf("loadTemplateSource(inline) = %q, %v", got, err)
+	}
+	if _, err := loadTemplateSource("@"); err == nil {
+		t.Error("loadTemplateSource(@) succeeded, want a missing path error")
+	}
+	if _, err := loadTemplateSource("@" + path + ".missing"); err == nil || !strings.Contains(err.Error(), "read template file") {
+		t.Errorf("loadTemplateSource(missing file) error = %v", err)
+	}
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// minWatchInterval keeps -watch from hammering Jira with back-to-back searches.
+const minWatchInterval = time.Second
+
+// clearScreen moves the cursor home and clears the terminal.
+const clearScreen = "\x1b[H\x1b[2J"
+
+// checkWatchFlags rejects -watch values and flag combinations that make no sense for a
+// report that is redrawn in place.
+func checkWatchFlags(rf *reportFlags) error {
+	if rf.watch < minWatchInterval {
+		return fmt.Errorf("-watch must be at least %s", minWatchInterval)
+	}
+	if !isTerminal(os.Stdout) {
+		return errors.New("-watch needs stdout to be a terminal")
+	}
+	switch {
+	case rf.outputPath != "":
+		return errors.New("-watch cannot be combined with -o")
+	case rf.xlsxPath != "":
+		return errors.New("-watch cannot be combined with -xlsx")
+	case rf.sinceLastRun || rf.resetSince:
+		return errors.New("-watch cannot be combined with -since-last-run or -reset-since")
+	case rf.deadline > 0:
+		return errors.New("-watch cannot be combined with -deadline")
+	case rf.failIfEmpty:
+		return errors.New("-watch cannot be combined with -fail-if-empty")
+	}
+	return nil
+}
+
+// watchReport draws the report, then redraws it each time after(interval) fires until
+// ctx is cancelled; runReport passes time.After. Errors from a refresh are shown below
+// the header and the next refresh is attempted as usual.
+func watchReport(ctx context.Context, interval time.Duration, after func(time.Duration) <-chan time.Time, run *reportRun) error {
+	// tick stays nil for the first draw, which happens at once.
+	var tick <-chan time.Time
+	for {
+		if tick != nil {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-tick:
+			}
+		} else if ctx.Err() != nil {
+			return nil
+		}
+
+		fmt.Print(clearScreen)
+		fmt.Printf("Every %s: %s (updated %s, Ctrl-C to stop)\n\n", interval, run.filter.Name, time.Now().Format("15:04:05"))
+		err := run.fetchAndRender(ctx)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+		}
+		tick = after(interval)
+	}
+}
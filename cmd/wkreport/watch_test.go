@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"wkreport/internal/config"
+	"wkreport/internal/jira"
+)
+
+// fakeTicker stands in for time.After: each wait is reported on waits, and the test
+// fires it by sending on the returned channel.
+type fakeTicker struct {
+	waits chan time.Duration
+	ticks chan time.Time
+}
+
+func (f *fakeTicker) after(d time.Duration) <-chan time.Time {
+	f.waits <- d
+	return f.ticks
+}
+
+func TestWatchReportRedrawsOnEachTick(t *testing.T) {
+	input := filepath.Join(t.TempDir(), "issues.jsonl")
+	writeIssues := func(keys ...string) {
+		var b strings.Builder
+		for _, key := range keys {
+			b.WriteString(`{"key":"` + key + `","summary":"s","status":"To Do"}` + "\n")
+		}
+		if err := os.WriteFile(input, []byte(b.String()), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeIssues("ABC-1")
+
+	run := &reportRun{
+		flags:  &reportFlags{inputPath: input},
+		cfg:    &config.Config{},
+		filter: &jira.Filter{Name: "Weekly"},
+		format: formatPorcelain,
+	}
+	ticker := &fakeTicker{waits: make(chan time.Duration), ticks: make(chan time.Time)}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	const interval = 30 * time.Second
+	out, err := captureStdout(t, func() error {
+		done := make(chan error, 1)
+		go func() { done <- watchReport(ctx, interval, ticker.after, run) }()
+
+		// The first draw happens without waiting; every later one waits for a tick.
+		for draw, keys := range [][]string{{"ABC-1", "ABC-2"}, {"ABC-3"}, nil} {
+			if d := <-ticker.waits; d != interval {
+				t.Errorf("wait %d was for %s, want %s", draw+1, d, interval)
+			}
+			if keys == nil {
+				break
+			}
+			writeIssues(keys...)
+			ticker.ticks <- time.Now()
+		}
+		cancel()
+		return <-done
+	})
+	if err != nil {
+		t.Fatalf("watchReport: %v", err)
+	}
+
+	frames := strings.Split(out, clearScreen)[1:]
+	if len(frames) != 3 {
+		t.Fatalf("drew %d frames, want 3:\n%q", len(frames), out)
+	}
+	for i, want := range []string{"ABC-1", "ABC-2", "ABC-3"} {
+		if !strings.HasPrefix(frames[i], "Every 30s: Weekly (updated ") || !strings.Contains(frames[i], want) {
+			t.Errorf("frame %d is missing %s:\n%s", i+1, want, frames[i])
+		}
+	}
+	if strings.Contains(frames[2], "ABC-1") {
+		t.Errorf("frame 3 still shows the first issues:\n%s", frames[2])
+	}
+}
+
+func TestWatchReportKeepsWatchingAfterAFailedRefresh(t *testing.T) {
+	run := &reportRun{
+		flags:  &reportFlags{inputPath: filepath.Join(t.TempDir(), "missing.jsonl")},
+		cfg:    &config.Config{},
+		filter: &jira.Filter{Name: "Weekly"},
+		format: formatPorcelain,
+	}
+	ticker := &fakeTicker{waits: make(chan time.Duration), ticks: make(chan time.Time)}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	out, err := captureStdout(t, func() error {
+		done := make(chan error, 1)
+		go func() { done <- watchReport(ctx, time.Minute, ticker.after, run) }()
+		<-ticker.waits
+		ticker.ticks <- time.Now()
+		<-ticker.waits
+		cancel()
+		return <-done
+	})
+	if err != nil {
+		t.Fatalf("watchReport: %v", err)
+	}
+	if n := strings.Count(out, clearScreen); n != 2 {
+		t.Errorf("drew %d frames, want 2", n)
+	}
+}
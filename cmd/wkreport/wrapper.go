@@ -0,0 +1,69 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"html/template"
+	"os"
+	"strings"
+)
+
+// docsWrapper is a -docs-template HTML shell that the docs and html tables are embedded
+// in, such as a branded page with its own head and styles.
+type docsWrapper struct {
+	tmpl *template.Template
+}
+
+// docsWrapperData is what a -docs-template is executed with: {{.Table}} is the
+// generated table and {{.FilterName}} the report's filter name.
+type docsWrapperData struct {
+	Table      template.HTML
+	FilterName string
+}
+
+// wrapperTableMarker stands in for the table when a template is checked for {{.Table}}.
+const wrapperTableMarker = "<!-- wkreport table -->"
+
+// loadDocsWrapper parses the -docs-template file at path and checks, by executing it
+// once, that it places the table somewhere in its output.
+func loadDocsWrapper(path string) (*docsWrapper, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read docs template: %w", err)
+	}
+	tmpl, err := template.New("docs").Parse(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("parse docs template: %w", err)
+	}
+
+	wrapper := &docsWrapper{tmpl: tmpl}
+	rendered, err := wrapper.wrap(wrapperTableMarker, "")
+	if err != nil {
+		return nil, err
+	}
+	if !strings.Contains(rendered, wrapperTableMarker) {
+		return nil, fmt.Errorf("docs template %s does not include the table; add {{.Table}} where it belongs", path)
+	}
+	return wrapper, nil
+}
+
+// wrap embeds tableHTML in the template.
+func (w *docsWrapper) wrap(tableHTML, filterName string) (string, error) {
+	var b strings.Builder
+	data := docsWrapperData{Table: template.HTML(tableHTML), FilterName: filterName}
+	if err := w.tmpl.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("execute docs template: %w", err)
+	}
+	return b.String(), nil
+}
+
+// checkDocsTemplateFlags rejects -docs-template outside the HTML table outputs.
+func checkDocsTemplateFlags(rf *reportFlags, format reportFormat) error {
+	switch {
+	case format != formatDocs && format != formatHTML:
+		return errors.New("-docs-template only applies to -format docs and -format html")
+	case rf.templateFlag != "" || rf.xlsxPath != "":
+		return errors.New("-docs-template cannot be combined with -template or -xlsx")
+	}
+	return nil
+}
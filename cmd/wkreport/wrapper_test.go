@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeDocsTemplate writes a -docs-template file with content and returns its path.
+func writeDocsTemplate(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "wrapper.html")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestDocsTemplateWrapsTable(t *testing.T) {
+	fake := newFakeJira(t, fakeIssue{id: "1", key: "ABC-1", status: "Done"})
+	wrapper := writeDocsTemplate(t, "<html><body class=\"brand\"><h1>{{.FilterName}}</h1>\n{{.Table}}\n</body></html>\n")
+	for _, format := range []string{"html", "docs"} {
+		t.Run(format, func(t *testing.T) {
+			out, err := runCapture(t, "-config", writeTestConfig(t, fake.URL), "-f", fmt.Sprint(fakeFilterID),
+				"-format", format, "-docs-template", wrapper, "-no-clipboard")
+			if err != nil {
+				t.Fatalf("run: %v", err)
+			}
+			if !strings.HasPrefix(out, "<html><body class=\"brand\"><h1>Weekly</h1>\n<table") {
+				t.Errorf("output does not start with the wrapper:\n%s", out)
+			}
+			if !strings.Contains(out, ">ABC-1</a>") || !strings.Contains(out, "</table>\n</body></html>\n") {
+				t.Errorf("output does not embed the table in the wrapper:\n%s", out)
+			}
+		})
+	}
+}
+
+func TestLoadDocsWrapper(t *testing.T) {
+	w, err := loadDocsWrapper(writeDocsTemplate(t, "<title>{{.FilterName}}</title>{{.Table}}"))
+	if err != nil {
+		t.Fatalf("loadDocsWrapper: %v", err)
+	}
+	got, err := w.wrap("<table></table>", "R&D <weekly>")
+	if err != nil {
+		t.Fatalf("wrap: %v", err)
+	}
+	// The table is embedded as is; the filter name is escaped.
+	if want := "<title>R&amp;D &lt;weekly&gt;</title><table></table>"; got != want {
+		t.Errorf("wrap = %q, want %q", got, want)
+	}
+
+	for _, tc := range []struct {
+		content string
+		wantErr string
+	}{
+		{"<html>{{.FilterName}}</html>", "does not include the table"},
+		{"<html>{{if .Table}}</html>", "parse docs template"},
+		{"<html>{{.Missing}}{{.Table}}</html>", "execute docs template"},
+	} {
+		if _, err := loadDocsWrapper(writeDocsTemplate(t, tc.content)); err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+			t.Errorf("loadDocsWrapper(%q) = %v, want an error containing %q", tc.content, err, tc.wantErr)
+		}
+	}
+}
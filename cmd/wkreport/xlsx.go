@@ -0,0 +1,214 @@
+package main
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"wkreport/internal/jira"
+)
+
+// xlsxMaxColumnWidth caps auto-sized columns so long summaries stay readable.
+const xlsxMaxColumnWidth = 80
+
+// Cell style indexes into the cellXfs list in xlsxStyles.
+const (
+	xlsxStyleDefault = 0
+	xlsxStyleHeader  = 1
+	xlsxStyleLink    = 2
+)
+
+type xlsxCell struct {
+	value string
+	style int
+	link  string
+}
+
+// writeXLSX writes issues to path as a single-sheet workbook with a bold, frozen header
+// row, auto-sized columns, and issue keys hyperlinked to their browse URLs. The workbook
+// is assembled directly from SpreadsheetML parts to avoid a spreadsheet dependency.
+func writeXLSX(path string, issues []jira.Issue, columns []column, sf summaryFormat) error {
+	header := make([]xlsxCell, len(columns))
+	for i, title := range columnHeaders(columns) {
+		header[i] = xlsxCell{value: title, style: xlsxStyleHeader}
+	}
+	rows := [][]xlsxCell{header}
+	for n, issue := range issues {
+		row := make([]xlsxCell, len(columns))
+		for i, value := range columnValues(issue, n+1, columns, sf, 0) {
+			row[i] = xlsxCell{value: value}
+			if url := strings.TrimSpace(issue.URL); columns[i].name == "key" && url != "" {
+				row[i] = xlsxCell{value: value, style: xlsxStyleLink, link: url}
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("create xlsx file: %w", err)
+	}
+
+	zw := zip.NewWriter(file)
+	sheet, sheetRels := xlsxSheet(rows)
+	parts := []struct {
+		name    string
+		content string
+	}{
+		{"[Content_Types].xml", xlsxContentTypes},
+		{"_rels/.rels", xlsxRootRels},
+		{"xl/workbook.xml", xlsxWorkbook},
+		{"xl/_rels/workbook.xml.rels", xlsxWorkbookRels},
+		{"xl/styles.xml", xlsxStyles},
+		{"xl/worksheets/sheet1.xml", sheet},
+		{"xl/worksheets/_rels/sheet1.xml.rels", sheetRels},
+	}
+	for _, part := range parts {
+		w, err := zw.Create(part.name)
+		if err != nil {
+			file.Close()
+			return fmt.Errorf("write xlsx part %s: %w", part.name, err)
+		}
+		if _, err := w.Write([]byte(part.content)); err != nil {
+			file.Close()
+			return fmt.Errorf("write xlsx part %s: %w", part.name, err)
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		file.Close()
+		return fmt.Errorf("finish xlsx file: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("close xlsx file: %w", err)
+	}
+	return nil
+}
+
+// xlsxSheet renders the worksheet XML and its relationships part (for hyperlinks).
+func xlsxSheet(rows [][]xlsxCell) (string, string) {
+	widths := make([]int, 0)
+	for _, row := range rows {
+		for i, cell := range row {
+			if i >= len(widths) {
+				widths = append(widths, 0)
+			}
+			if n := utf8.RuneCountInString(cell.value); n > widths[i] {
+				widths[i] = n
+			}
+		}
+	}
+
+	var sheet strings.Builder
+	var links strings.Builder
+	var rels strings.Builder
+	linkCount := 0
+
+	sheet.WriteString(xml.Header)
+	sheet.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">`)
+	sheet.WriteString(`<sheetViews><sheetView workbookViewId="0"><pane ySplit="1" topLeftCell="A2" activePane="bottomLeft" state="frozen"/></sheetView></sheetViews>`)
+
+	sheet.WriteString("<cols>")
+	for i, width := range widths {
+		width += 2
+		if width > xlsxMaxColumnWidth {
+			width = xlsxMaxColumnWidth
+		}
+		fmt.Fprintf(&sheet, `<col min="%d" max="%d" width="%d" customWidth="1"/>`, i+1, i+1, width)
+	}
+	sheet.WriteString("</cols>")
+
+	sheet.WriteString("<sheetData>")
+	for r, row := range rows {
+		fmt.Fprintf(&sheet, `<row r="%d">`, r+1)
+		for c, cell := range row {
+			ref := fmt.Sprintf("%s%d", xlsxColumnName(c), r+1)
+			fmt.Fprintf(&sheet, `<c r="%s" t="inlineStr"`, ref)
+			if cell.style != xlsxStyleDefault {
+				fmt.Fprintf(&sheet, ` s="%d"`, cell.style)
+			}
+			sheet.WriteString(`><is><t xml:space="preserve">`)
+			sheet.WriteString(xmlEscape(cell.value))
+			sheet.WriteString("</t></is></c>")
+
+			if cell.link != "" {
+				linkCount++
+				fmt.Fprintf(&links, `<hyperlink ref="%s" r:id="rId%d"/>`, ref, linkCount)
+				fmt.Fprintf(&rels, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/hyperlink" Target="%s" TargetMode="External"/>`, linkCount, xmlEscape(cell.link))
+			}
+		}
+		sheet.WriteString("</row>")
+	}
+	sheet.WriteString("</sheetData>")
+
+	if linkCount > 0 {
+		sheet.WriteString("<hyperlinks>")
+		sheet.WriteString(links.String())
+		sheet.WriteString("</hyperlinks>")
+	}
+	sheet.WriteString("</worksheet>")
+
+	sheetRels := xml.Header +
+		`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		rels.String() +
+		`</Relationships>`
+
+	return sheet.String(), sheetRels
+}
+
+// xlsxColumnName converts a zero-based column index to its spreadsheet letters (A, B, ... AA).
+func xlsxColumnName(index int) string {
+	name := ""
+	for index >= 0 {
+		name = string(rune('A'+index%26)) + name
+		index = index/26 - 1
+	}
+	return name
+}
+
+func xmlEscape(value string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(value))
+	return b.String()
+}
+
+const xlsxContentTypes = xml.Header + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+	`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+	`<Default Extension="xml" ContentType="application/xml"/>` +
+	`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+	`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+	`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>` +
+	`</Types>`
+
+const xlsxRootRels = xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+	`</Relationships>`
+
+const xlsxWorkbook = xml.Header + `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+	`<sheets><sheet name="Report" sheetId="1" r:id="rId1"/></sheets>` +
+	`</workbook>`
+
+const xlsxWorkbookRels = xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+	`<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>` +
+	`</Relationships>`
+
+const xlsxStyles = xml.Header + `<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+	`<fonts count="3">` +
+	`<font><sz val="11"/><name val="Calibri"/></font>` +
+	`<font><b/><sz val="11"/><name val="Calibri"/></font>` +
+	`<font><u/><sz val="11"/><color rgb="FF0563C1"/><name val="Calibri"/></font>` +
+	`</fonts>` +
+	`<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>` +
+	`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>` +
+	`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+	`<cellXfs count="3">` +
+	`<xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>` +
+	`<xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/>` +
+	`<xf numFmtId="0" fontId="2" fillId="0" borderId="0" xfId="0" applyFont="1"/>` +
+	`</cellXfs>` +
+	`<cellStyles count="1"><cellStyle name="Normal" xfId="0" builtinId="0"/></cellStyles>` +
+	`</styleSheet>`
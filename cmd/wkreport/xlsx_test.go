@@ -0,0 +1,107 @@
+package main
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"io"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"wkreport/internal/jira"
+)
+
+// readXLSX returns the parts of the workbook at path, in archive order, by name.
+func readXLSX(t *testing.T, path string) ([]string, map[string]string) {
+	t.Helper()
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		t.Fatalf("open xlsx: %v", err)
+	}
+	defer zr.Close()
+	var names []string
+	parts := make(map[string]string)
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatalf("open part %s: %v", f.Name, err)
+		}
+		data, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			t.Fatalf("read part %s: %v", f.Name, err)
+		}
+		names = append(names, f.Name)
+		parts[f.Name] = string(data)
+	}
+	return names, parts
+}
+
+func TestWriteXLSX(t *testing.T) {
+	columns, err := parseColumns("key,summary,status", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	issues := []jira.Issue{
+		{Key: "ABC-1", Summary: "Fix <b> & friends", Status: "Done", URL: "https://jira.example.com/browse/ABC-1?x=1&y=2"},
+		{Key: "ABC-2", Summary: "No link", Status: "To Do"},
+	}
+	path := filepath.Join(t.TempDir(), "report.xlsx")
+	if err := writeXLSX(path, issues, columns, summaryFormat{ellipsis: asciiEllipsis}); err != nil {
+		t.Fatalf("writeXLSX: %v", err)
+	}
+
+	names, parts := readXLSX(t, path)
+	want := []string{
+		"[Content_Types].xml",
+		"_rels/.rels",
+		"xl/workbook.xml",
+		"xl/_rels/workbook.xml.rels",
+		"xl/styles.xml",
+		"xl/worksheets/sheet1.xml",
+		"xl/worksheets/_rels/sheet1.xml.rels",
+	}
+	if !slices.Equal(names, want) {
+		t.Errorf("parts = %q, want %q", names, want)
+	}
+	for name, content := range parts {
+		decoder := xml.NewDecoder(strings.NewReader(content))
+		for {
+			if _, err := decoder.Token(); err == io.EOF {
+				break
+			} else if err != nil {
+				t.Errorf("part %s is not well-formed XML: %v", name, err)
+				break
+			}
+		}
+	}
+
+	sheet := parts["xl/worksheets/sheet1.xml"]
+	for _, want := range []string{
+		`<c r="A1" t="inlineStr" s="1"><is><t xml:space="preserve">KEY</t></is></c>`,
+		`<c r="A2" t="inlineStr" s="2"><is><t xml:space="preserve">ABC-1</t></is></c>`,
+		`<t xml:space="preserve">Fix &lt;b&gt; &amp; friends</t>`,
+		`<c r="A3" t="inlineStr"><is><t xml:space="preserve">ABC-2</t></is></c>`,
+		`<hyperlinks><hyperlink ref="A2" r:id="rId1"/></hyperlinks>`,
+	} {
+		if !strings.Contains(sheet, want) {
+			t.Errorf("sheet is missing %s:\n%s", want, sheet)
+		}
+	}
+	rels := parts["xl/worksheets/_rels/sheet1.xml.rels"]
+	if n := strings.Count(rels, "<Relationship "); n != 1 {
+		t.Errorf("sheet has %d relationships, want 1:\n%s", n, rels)
+	}
+	if want := `Target="https://jira.example.com/browse/ABC-1?x=1&amp;y=2" TargetMode="External"`; !strings.Contains(rels, want) {
+		t.Errorf("sheet relationships are missing %s:\n%s", want, rels)
+	}
+}
+
+func TestXLSXColumnName(t *testing.T) {
+	for index, want := range map[int]string{0: "A", 25: "Z", 26: "AA", 51: "AZ", 52: "BA", 701: "ZZ", 702: "AAA"} {
+		if got := xlsxColumnName(index); got != want {
+			t.Errorf("xlsxColumnName(%d) = %q, want %q", index, got, want)
+		}
+	}
+}
@@ -1,18 +1,53 @@
 package config
 
 import (
-	"bufio"
 	"errors"
 	"fmt"
+	"iter"
+	"math"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strconv"
 	"strings"
-	"unicode"
+	"time"
+
+	"gopkg.in/yaml.v3"
 )
 
 // Config models application level configuration.
 type Config struct {
-	Jira JiraConfig
+	Jira   JiraConfig
+	Output OutputConfig
+	// Profile is the name of the profile whose jira block was applied, if any.
+	Profile string
+	// Sources records where each value was set, keyed like "jira.url"; see Source.
+	Sources map[string]string
+}
+
+// SourceDefault is the Source of a key that nothing set.
+const SourceDefault = "default"
+
+// Source describes where the value of key (such as "jira.url") came from: "file",
+// "profile <name>", "<VAR> env", "api_token_file", or SourceDefault.
+func (c *Config) Source(key string) string {
+	if source, ok := c.Sources[key]; ok {
+		return source
+	}
+	return SourceDefault
+}
+
+// setSource records the source of a section key, folding aliases such as jira.token
+// into the key they set.
+func (c *Config) setSource(section, key, source string) {
+	key = strings.ToLower(key)
+	if section == "jira" && key == "token" {
+		key = "api_token"
+	}
+	if c.Sources == nil {
+		c.Sources = make(map[string]string)
+	}
+	c.Sources[section+"."+key] = source
 }
 
 // JiraConfig contains connection details for the Jira instance.
@@ -20,27 +55,133 @@ type JiraConfig struct {
 	URL      string
 	Email    string
 	APIToken string
+	// APITokenFile names a file (such as a mounted secret) holding the API token. It is
+	// read only when no token is set directly; relative paths resolve against the config file.
+	APITokenFile string
+	// SprintField is the sprint custom field id (for example customfield_10020).
+	SprintField string
+	// StoryPointsField is the story points custom field id (for example customfield_10016).
+	StoryPointsField string
+	// BrowseURL is the base of issue links when the web UI is not served from URL,
+	// for example behind a reverse proxy. Empty means URL.
+	BrowseURL string
+	// PageSize is the number of results requested per search or filter listing page.
+	// Zero uses the Jira maximum; other values are clamped to 1 through maxPageSize.
+	PageSize int
+	// EnhancedSearch uses the /rest/api/3/search/jql endpoint with token pagination.
+	EnhancedSearch bool
+	// MaxIssues caps how many issues a report may match before it is refused.
+	// It defaults to DefaultMaxIssues; zero disables the cap.
+	MaxIssues int
+	// ExtraFields are additional Jira fields to request, exposed to -template and JSON.
+	ExtraFields []string
+	// AuditLog names a file that each report run appends a JSON metadata line to.
+	// Relative paths resolve against the config file.
+	AuditLog string
+	// UserAgent replaces the User-Agent header, which defaults to wkreport/<version>.
+	UserAgent string
+	// AtlassianTokenNoCheck sends X-Atlassian-Token: no-check on every request, for
+	// gateways and reverse proxies that reject requests without it.
+	AtlassianTokenNoCheck bool
+	// MaxIdleConnsPerHost, MaxConnsPerHost, and IdleConnTimeout tune the HTTP
+	// connection pool; zero keeps the client's defaults.
+	MaxIdleConnsPerHost int
+	MaxConnsPerHost     int
+	IdleConnTimeout     time.Duration
+
+	// AuthType selects the authentication scheme: "basic" (email + API token, the
+	// default) or "oauth" (OAuth 2.0 bearer token with optional refresh).
+	AuthType     string
+	AccessToken  string
+	RefreshToken string
+	ClientID     string
+	ClientSecret string
+	TokenURL     string
+}
+
+// Auth type values accepted by JiraConfig.AuthType.
+const (
+	AuthBasic = "basic"
+	AuthOAuth = "oauth"
+)
+
+// OutputConfig contains report rendering preferences.
+type OutputConfig struct {
+	// DefaultMode selects the output mode used when no mode flag is given (e.g. table, docs).
+	DefaultMode string
+	// StatusOrder lists statuses in report order; unlisted statuses follow alphabetically.
+	StatusOrder []string
+	// AllowedStatuses lists the statuses -strict accepts, compared after aliasing.
+	AllowedStatuses []string
+	// StatusAliases maps statuses to the label they are reported under, merging
+	// workflow variants such as "QA" and "In QA".
+	StatusAliases map[string]string
+	// StatusColors maps statuses to the color names of the terminal table's STATUS
+	// column, over the built-in colors.
+	StatusColors map[string]string
+	// Ellipsis selects the truncation marker: "ascii" (the default) or "unicode".
+	Ellipsis string
+	// Widths maps output formats (table, docs, slides, ...) to their summary
+	// truncation width, 0 for no limit.
+	Widths map[string]int
 }
 
 // Load reads configuration from the provided path and applies environment overrides.
+// When the file defines profiles, the default_profile is selected.
 func Load(path string) (*Config, error) {
+	return LoadProfile(path, "")
+}
+
+// LoadProfile is like Load but applies the named profile's jira block on top of the
+// top-level jira section. An empty name selects default_profile, if one is set.
+func LoadProfile(path, profile string) (*Config, error) {
+	return load(path, profile, false)
+}
+
+// LoadOffline is like LoadProfile for reports rendered from saved issues, which never
+// contact Jira: the jira credentials are neither read nor required, and a missing
+// config file leaves every key at its default (environment overrides still apply).
+func LoadOffline(path, profile string) (*Config, error) {
+	return load(path, profile, true)
+}
+
+func load(path, profile string, offline bool) (*Config, error) {
 	absPath, err := filepath.Abs(path)
 	if err != nil {
 		return nil, fmt.Errorf("resolve config path: %w", err)
 	}
 
-	file, err := os.Open(absPath)
-	if err != nil {
-		return nil, fmt.Errorf("open config file: %w", err)
+	cfg := Config{Jira: JiraConfig{MaxIssues: DefaultMaxIssues}}
+	data, err := os.ReadFile(absPath)
+	switch {
+	case err == nil:
+		if err := parseConfig(data, &cfg, profile); err != nil {
+			return nil, err
+		}
+	case offline && errors.Is(err, os.ErrNotExist):
+	default:
+		return nil, fmt.Errorf("read config file: %w", err)
+	}
+
+	if err := applyEnvOverrides(&cfg); err != nil {
+		return nil, err
+	}
+	if offline {
+		return &cfg, nil
 	}
-	defer file.Close()
 
-	cfg := Config{}
-	if err := parseYAMLSubset(bufio.NewScanner(file), &cfg); err != nil {
+	hadToken := cfg.Jira.APIToken != ""
+	if err := loadAPITokenFile(&cfg.Jira, filepath.Dir(absPath)); err != nil {
 		return nil, err
 	}
+	if !hadToken && cfg.Jira.APIToken != "" {
+		cfg.setSource("jira", "api_token", "api_token_file")
+	}
+	if cfg.Jira.AuditLog != "" && !filepath.IsAbs(cfg.Jira.AuditLog) {
+		cfg.Jira.AuditLog = filepath.Join(filepath.Dir(absPath), cfg.Jira.AuditLog)
+	}
 
-	applyJiraEnvOverrides(&cfg.Jira)
+	clampPageSize(&cfg.Jira)
 
 	if err := validate(&cfg); err != nil {
 		return nil, err
@@ -49,109 +190,528 @@ func Load(path string) (*Config, error) {
 	return &cfg, nil
 }
 
-func parseYAMLSubset(scanner *bufio.Scanner, cfg *Config) error {
-	const jiraSection = "jira"
-	currentSection := ""
+// parseConfig decodes the YAML config file data into cfg, then applies the named
+// profile's jira block (default_profile when name is empty) over the top-level jira
+// section.
+func parseConfig(data []byte, cfg *Config, profile string) error {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("parse config: %w", err)
+	}
+	if len(doc.Content) == 0 {
+		return nil
+	}
+	root := resolveAlias(doc.Content[0])
+	if isNull(root) {
+		return nil
+	}
+	if root.Kind != yaml.MappingNode {
+		return nodeError(root, errors.New("expected jira, output, and profiles sections"))
+	}
 
-	for scanner.Scan() {
-		line := scanner.Text()
-		trimmed := strings.TrimSpace(line)
-		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
-			continue
+	profiles := make(map[string]*yaml.Node)
+	var profileOrder []string
+	defaultProfile := ""
+	for key, value := range entries(root) {
+		var err error
+		switch strings.ToLower(key) {
+		case "jira":
+			err = applyJira(cfg, value, "file")
+		case "output":
+			err = applyOutput(cfg, value)
+		case "profiles":
+			err = collectProfiles(value, profiles, &profileOrder)
+		case "default_profile":
+			defaultProfile, err = scalar("default_profile", value)
+		default:
+			// Sections wkreport does not read are left alone; stray values are mistakes.
+			if value.Kind == yaml.ScalarNode && !isNull(value) {
+				err = nodeError(value, fmt.Errorf("unrecognized config key %q", key))
+			}
+		}
+		if err != nil {
+			return err
 		}
+	}
 
-		if !isIndented(line) {
-			// Root level key
-			if strings.HasSuffix(trimmed, ":") {
-				currentSection = strings.TrimSuffix(trimmed, ":")
-				continue
+	if profile == "" {
+		profile = defaultProfile
+	}
+	if profile == "" {
+		return nil
+	}
+	block, ok := profiles[profile]
+	if !ok {
+		if len(profileOrder) == 0 {
+			return fmt.Errorf("profile %q requested but the config defines no profiles", profile)
+		}
+		return fmt.Errorf("unknown profile %q (available: %s)", profile, strings.Join(profileOrder, ", "))
+	}
+	if err := applyJira(cfg, block, "profile "+profile); err != nil {
+		return fmt.Errorf("profile %s: %w", profile, err)
+	}
+	cfg.Profile = profile
+	return nil
+}
+
+// applyJira sets the keys of a jira section, recording source as their origin.
+func applyJira(cfg *Config, section *yaml.Node, source string) error {
+	if isNull(section) {
+		return nil
+	}
+	if section.Kind != yaml.MappingNode {
+		return nodeError(section, errors.New("jira must be a map of keys"))
+	}
+	for key, value := range entries(section) {
+		var text string
+		var err error
+		if strings.EqualFold(key, "extra_fields") && value.Kind == yaml.SequenceNode {
+			var fields []string
+			fields, err = stringList(key, value)
+			text = strings.Join(fields, ",")
+		} else {
+			text, err = scalar(key, value)
+		}
+		if err == nil {
+			err = setJiraKey(&cfg.Jira, key, text)
+		}
+		if err != nil {
+			return nodeError(value, err)
+		}
+		cfg.setSource("jira", key, source)
+	}
+	return nil
+}
+
+// applyOutput sets the keys of the output section.
+func applyOutput(cfg *Config, section *yaml.Node) error {
+	if isNull(section) {
+		return nil
+	}
+	if section.Kind != yaml.MappingNode {
+		return nodeError(section, errors.New("output must be a map of keys"))
+	}
+	for key, value := range entries(section) {
+		var err error
+		switch strings.ToLower(key) {
+		case "status_order":
+			cfg.Output.StatusOrder, err = stringList(key, value)
+		case "allowed_statuses":
+			cfg.Output.AllowedStatuses, err = stringList(key, value)
+		case "status_aliases":
+			cfg.Output.StatusAliases, err = stringMap(key, value)
+		case "status_colors":
+			cfg.Output.StatusColors, err = stringMap(key, value)
+		case "widths":
+			var widths map[string]string
+			if widths, err = stringMap(key, value); err == nil {
+				err = setWidths(&cfg.Output, widths)
 			}
-			return fmt.Errorf("unrecognized config line: %q", line)
+		default:
+			var text string
+			if text, err = scalar(key, value); err == nil {
+				err = setOutputKey(&cfg.Output, key, text)
+			}
+		}
+		if err != nil {
+			return nodeError(value, err)
 		}
+		cfg.setSource("output", key, "file")
+	}
+	return nil
+}
 
-		if currentSection != jiraSection {
+// collectProfiles records the jira block of each profile under profiles, in file order.
+// Profiles nest as profiles > name > jira > key: value.
+func collectProfiles(section *yaml.Node, profiles map[string]*yaml.Node, order *[]string) error {
+	if isNull(section) {
+		return nil
+	}
+	if section.Kind != yaml.MappingNode {
+		return nodeError(section, errors.New("profiles must map profile names to their settings"))
+	}
+	for name, body := range entries(section) {
+		if _, seen := profiles[name]; !seen {
+			*order = append(*order, name)
+		}
+		profiles[name] = nil
+		if isNull(body) {
 			continue
 		}
+		if body.Kind != yaml.MappingNode {
+			return nodeError(body, fmt.Errorf("profile %s must contain a jira block", name))
+		}
+		for key, block := range entries(body) {
+			if !strings.EqualFold(key, "jira") {
+				return nodeError(block, fmt.Errorf("profile %s: profiles only contain a jira block, not %q", name, key))
+			}
+			profiles[name] = block
+		}
+	}
+	return nil
+}
+
+// entries iterates over the keys and values of a mapping node, following aliases.
+func entries(node *yaml.Node) iter.Seq2[string, *yaml.Node] {
+	return func(yield func(string, *yaml.Node) bool) {
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if !yield(resolveAlias(node.Content[i]).Value, resolveAlias(node.Content[i+1])) {
+				return
+			}
+		}
+	}
+}
+
+// scalar returns a single config value with its ${VAR} references expanded. A key
+// left empty (or set to null) has the empty value.
+func scalar(key string, node *yaml.Node) (string, error) {
+	if isNull(node) {
+		return "", nil
+	}
+	if node.Kind != yaml.ScalarNode {
+		return "", fmt.Errorf("%s must be a single value", key)
+	}
+	return expandEnv(node.Value), nil
+}
 
-		key, value, err := splitKeyValue(trimmed)
+// stringList returns the non-empty items of a block or flow list such as
+// "[To Do, In Progress, Done]".
+func stringList(key string, node *yaml.Node) ([]string, error) {
+	if isNull(node) {
+		return nil, nil
+	}
+	if node.Kind != yaml.SequenceNode {
+		return nil, fmt.Errorf("%s must be a list such as [a, b, c]", key)
+	}
+	var items []string
+	for _, item := range node.Content {
+		text, err := scalar(key, resolveAlias(item))
 		if err != nil {
-			return fmt.Errorf("invalid jira config line: %q: %w", line, err)
+			return nil, err
 		}
-		value = stripQuotes(value)
+		if text = strings.TrimSpace(text); text != "" {
+			items = append(items, text)
+		}
+	}
+	return items, nil
+}
 
-		switch strings.ToLower(key) {
-		case "url":
-			cfg.Jira.URL = value
-		case "email":
-			cfg.Jira.Email = value
-		case "api_token":
-			cfg.Jira.APIToken = value
-		case "token":
-			cfg.Jira.APIToken = value
-		default:
-			return fmt.Errorf("unknown jira config key %q", key)
+// stringMap returns the entries of a block or flow map such as "{table: 120, docs: 200}".
+func stringMap(key string, node *yaml.Node) (map[string]string, error) {
+	values := make(map[string]string)
+	if isNull(node) {
+		return values, nil
+	}
+	if node.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("%s must be a map of key: value entries", key)
+	}
+	for name, value := range entries(node) {
+		text, err := scalar(key+"."+name, value)
+		if err != nil {
+			return nil, err
 		}
+		values[name] = text
 	}
+	return values, nil
+}
 
-	if err := scanner.Err(); err != nil {
-		return fmt.Errorf("read config: %w", err)
+func resolveAlias(node *yaml.Node) *yaml.Node {
+	for node.Kind == yaml.AliasNode && node.Alias != nil {
+		node = node.Alias
 	}
+	return node
+}
 
+func isNull(node *yaml.Node) bool {
+	return node.Kind == yaml.ScalarNode && node.Tag == "!!null"
+}
+
+// nodeError places err at node's line of the config file.
+func nodeError(node *yaml.Node, err error) error {
+	return fmt.Errorf("config line %d: %w", node.Line, err)
+}
+
+// setJiraKey applies one jira section key to cfg, parsing typed keys such as page_size
+// or enhanced_search into their fields.
+func setJiraKey(cfg *JiraConfig, key, value string) error {
+	switch strings.ToLower(key) {
+	case "url":
+		cfg.URL = value
+	case "email":
+		cfg.Email = value
+	case "api_token":
+		cfg.APIToken = value
+	case "token":
+		cfg.APIToken = value
+	case "api_token_file":
+		cfg.APITokenFile = value
+	case "sprint_field":
+		cfg.SprintField = value
+	case "story_points_field":
+		cfg.StoryPointsField = value
+	case "browse_url":
+		cfg.BrowseURL = value
+	case "page_size":
+		return setInt(&cfg.PageSize, "jira page_size", value, noMinimum)
+	case "enhanced_search":
+		return setBool(&cfg.EnhancedSearch, "jira enhanced_search", value)
+	case "max_issues":
+		return setInt(&cfg.MaxIssues, "jira max_issues", value, 0)
+	case "extra_fields":
+		cfg.ExtraFields = nil
+		for _, field := range strings.Split(value, ",") {
+			if field = strings.TrimSpace(field); field != "" {
+				cfg.ExtraFields = append(cfg.ExtraFields, field)
+			}
+		}
+	case "audit_log":
+		cfg.AuditLog = value
+	case "user_agent":
+		cfg.UserAgent = value
+	case "atlassian_token_nocheck":
+		return setBool(&cfg.AtlassianTokenNoCheck, "jira atlassian_token_nocheck", value)
+	case "max_idle_conns_per_host":
+		return setInt(&cfg.MaxIdleConnsPerHost, "jira max_idle_conns_per_host", value, 0)
+	case "max_conns_per_host":
+		return setInt(&cfg.MaxConnsPerHost, "jira max_conns_per_host", value, 0)
+	case "idle_conn_timeout":
+		return setDuration(&cfg.IdleConnTimeout, "jira idle_conn_timeout", value)
+	case "auth_type":
+		cfg.AuthType = strings.ToLower(value)
+	case "access_token":
+		cfg.AccessToken = value
+	case "refresh_token":
+		cfg.RefreshToken = value
+	case "client_id":
+		cfg.ClientID = value
+	case "client_secret":
+		cfg.ClientSecret = value
+	case "token_url":
+		cfg.TokenURL = value
+	default:
+		return fmt.Errorf("unknown jira config key %q", key)
+	}
 	return nil
 }
 
-func applyJiraEnvOverrides(jira *JiraConfig) {
-	if v := strings.TrimSpace(os.Getenv("JIRA_URL")); v != "" {
-		jira.URL = v
+// noMinimum is the setInt minimum of keys that accept any whole number.
+const noMinimum = math.MinInt
+
+// setBool parses value into a boolean config field, naming the key in the error.
+func setBool(target *bool, name, value string) error {
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return fmt.Errorf("%s must be true or false, got %q", name, value)
 	}
-	if v := strings.TrimSpace(os.Getenv("JIRA_EMAIL")); v != "" {
-		jira.Email = v
+	*target = parsed
+	return nil
+}
+
+// setInt parses value into a whole-number config field of at least minimum, naming
+// the key in the error.
+func setInt(target *int, name, value string, minimum int) error {
+	parsed, err := strconv.Atoi(value)
+	switch {
+	case err != nil && minimum == noMinimum:
+		return fmt.Errorf("%s must be a whole number, got %q", name, value)
+	case err != nil || parsed < minimum:
+		return fmt.Errorf("%s must be a whole number of at least %d, got %q", name, minimum, value)
 	}
-	if v := strings.TrimSpace(os.Getenv("JIRA_API_TOKEN")); v != "" {
-		jira.APIToken = v
+	*target = parsed
+	return nil
+}
+
+// setDuration parses value, a duration such as "90s", into a config field, naming the
+// key in the error.
+func setDuration(target *time.Duration, name, value string) error {
+	parsed, err := time.ParseDuration(value)
+	if err != nil || parsed < 0 {
+		return fmt.Errorf("%s must be a duration such as 90s, got %q", name, value)
 	}
-	if v := strings.TrimSpace(os.Getenv("JIRA_TOKEN")); v != "" {
-		jira.APIToken = v
+	*target = parsed
+	return nil
+}
+
+// setWidths parses the output.widths entries into cfg.Widths.
+func setWidths(cfg *OutputConfig, entries map[string]string) error {
+	if entries == nil {
+		return nil
 	}
+	cfg.Widths = make(map[string]int, len(entries))
+	for format, value := range entries {
+		var width int
+		if err := setInt(&width, "output.widths."+format, value, 0); err != nil {
+			return err
+		}
+		cfg.Widths[strings.ToLower(format)] = width
+	}
+	return nil
+}
+
+// setOutputKey applies one scalar output section key to cfg.
+func setOutputKey(cfg *OutputConfig, key, value string) error {
+	switch strings.ToLower(key) {
+	case "default_mode":
+		cfg.DefaultMode = strings.ToLower(value)
+	case "ellipsis":
+		cfg.Ellipsis = strings.ToLower(value)
+	default:
+		return fmt.Errorf("unknown output config key %q", key)
+	}
+	return nil
+}
+
+// envOverride maps an environment variable onto a config key. Jira keys are named
+// JIRA_<KEY> and output keys WKREPORT_OUTPUT_<KEY>.
+type envOverride struct {
+	name    string
+	section string
+	key     string
+}
+
+// envOverrides lists the environment variables applied over the config file, in order;
+// when two set the same key, the later one wins.
+var envOverrides = []envOverride{
+	{"JIRA_URL", "jira", "url"},
+	{"JIRA_EMAIL", "jira", "email"},
+	{"JIRA_API_TOKEN", "jira", "api_token"},
+	{"JIRA_TOKEN", "jira", "token"},
+	{"JIRA_API_TOKEN_FILE", "jira", "api_token_file"},
+	{"JIRA_SPRINT_FIELD", "jira", "sprint_field"},
+	{"JIRA_STORY_POINTS_FIELD", "jira", "story_points_field"},
+	{"JIRA_BROWSE_URL", "jira", "browse_url"},
+	{"JIRA_PAGE_SIZE", "jira", "page_size"},
+	{"JIRA_ENHANCED_SEARCH", "jira", "enhanced_search"},
+	{"JIRA_MAX_ISSUES", "jira", "max_issues"},
+	{"JIRA_EXTRA_FIELDS", "jira", "extra_fields"},
+	{"JIRA_AUDIT_LOG", "jira", "audit_log"},
+	{"JIRA_USER_AGENT", "jira", "user_agent"},
+	{"JIRA_ATLASSIAN_TOKEN_NOCHECK", "jira", "atlassian_token_nocheck"},
+	{"JIRA_MAX_IDLE_CONNS_PER_HOST", "jira", "max_idle_conns_per_host"},
+	{"JIRA_MAX_CONNS_PER_HOST", "jira", "max_conns_per_host"},
+	{"JIRA_IDLE_CONN_TIMEOUT", "jira", "idle_conn_timeout"},
+	{"JIRA_AUTH_TYPE", "jira", "auth_type"},
+	{"JIRA_ACCESS_TOKEN", "jira", "access_token"},
+	{"JIRA_REFRESH_TOKEN", "jira", "refresh_token"},
+	{"JIRA_CLIENT_ID", "jira", "client_id"},
+	{"JIRA_CLIENT_SECRET", "jira", "client_secret"},
+	{"JIRA_TOKEN_URL", "jira", "token_url"},
+	{"WKREPORT_OUTPUT_MODE", "output", "default_mode"},
+	{"WKREPORT_OUTPUT_ELLIPSIS", "output", "ellipsis"},
+}
+
+// applyEnvOverrides sets the config keys of the non-empty variables in envOverrides,
+// parsing their values as the config file's would be.
+func applyEnvOverrides(cfg *Config) error {
+	for _, override := range envOverrides {
+		value := strings.TrimSpace(os.Getenv(override.name))
+		if value == "" {
+			continue
+		}
+		var err error
+		switch override.section {
+		case "jira":
+			err = setJiraKey(&cfg.Jira, override.key, value)
+		case "output":
+			err = setOutputKey(&cfg.Output, override.key, value)
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", override.name, err)
+		}
+		cfg.setSource(override.section, override.key, override.name+" env")
+	}
+	return nil
+}
+
+// loadAPITokenFile fills APIToken from APITokenFile when no token was given directly.
+func loadAPITokenFile(jira *JiraConfig, baseDir string) error {
+	if jira.APIToken != "" || jira.APITokenFile == "" {
+		return nil
+	}
+	path := jira.APITokenFile
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(baseDir, path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read jira api_token_file: %w", err)
+	}
+	token := strings.TrimSpace(string(data))
+	if token == "" {
+		return fmt.Errorf("jira api_token_file %s is empty", path)
+	}
+	jira.APIToken = token
+	return nil
 }
 
 func validate(cfg *Config) error {
 	if cfg.Jira.URL == "" {
 		return errors.New("jira url is required (cfg/config.yaml or JIRA_URL)")
 	}
+
+	switch cfg.Jira.AuthType {
+	case "":
+		cfg.Jira.AuthType = AuthBasic
+	case AuthBasic:
+	case AuthOAuth:
+		return validateOAuth(&cfg.Jira)
+	default:
+		return fmt.Errorf("unknown jira auth_type %q (expected %s or %s)", cfg.Jira.AuthType, AuthBasic, AuthOAuth)
+	}
+
 	if cfg.Jira.Email == "" {
 		return errors.New("jira email is required (cfg/config.yaml or JIRA_EMAIL)")
 	}
 	if cfg.Jira.APIToken == "" {
-		return errors.New("jira api token is required (cfg/config.yaml or JIRA_API_TOKEN)")
+		return errors.New("jira api token is required (cfg/config.yaml, JIRA_API_TOKEN, or JIRA_API_TOKEN_FILE)")
 	}
 	return nil
 }
 
-func splitKeyValue(line string) (key, value string, err error) {
-	parts := strings.SplitN(line, ":", 2)
-	if len(parts) != 2 {
-		return "", "", errors.New("expected key: value pair")
-	}
-	key = strings.TrimSpace(parts[0])
-	value = strings.TrimSpace(parts[1])
-	if key == "" {
-		return "", "", errors.New("missing key")
+// DefaultMaxIssues is the max_issues cap used when the config does not set one.
+const DefaultMaxIssues = 1000
+
+// maxPageSize is Jira's per-request limit for search and filter listing results.
+const maxPageSize = 100
+
+// clampPageSize pulls an out-of-range page_size back to the nearest allowed value.
+func clampPageSize(jira *JiraConfig) {
+	size := jira.PageSize
+	switch {
+	case size == 0:
+		return
+	case size < 1:
+		jira.PageSize = 1
+	case size > maxPageSize:
+		jira.PageSize = maxPageSize
+	default:
+		return
 	}
-	return key, value, nil
+	fmt.Fprintf(os.Stderr, "config: jira page_size %d is outside 1-%d; using %d\n", size, maxPageSize, jira.PageSize)
 }
 
-func stripQuotes(value string) string {
-	if len(value) >= 2 {
-		first := rune(value[0])
-		last := rune(value[len(value)-1])
-		if (first == '"' && last == '"') || (first == '\'' && last == '\'') {
-			return value[1 : len(value)-1]
-		}
+func validateOAuth(jira *JiraConfig) error {
+	if jira.AccessToken == "" && jira.RefreshToken == "" {
+		return errors.New("jira access_token or refresh_token is required for oauth (cfg/config.yaml or JIRA_ACCESS_TOKEN)")
 	}
-	return strings.TrimRightFunc(value, unicode.IsSpace)
+	if jira.RefreshToken != "" && (jira.ClientID == "" || jira.ClientSecret == "") {
+		return errors.New("jira client_id and client_secret are required to refresh oauth tokens (cfg/config.yaml or JIRA_CLIENT_ID/JIRA_CLIENT_SECRET)")
+	}
+	return nil
 }
 
-func isIndented(line string) bool {
-	return len(line) > 0 && (line[0] == ' ' || line[0] == '\t')
+var envReference = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// expandEnv replaces ${VAR} with the variable's value. Only the braced form is
+// expanded so tokens containing a bare "$" survive; unset variables become empty,
+// with a warning when JIRA_DEBUG is set.
+func expandEnv(value string) string {
+	return envReference.ReplaceAllStringFunc(value, func(ref string) string {
+		name := ref[2 : len(ref)-1]
+		v, ok := os.LookupEnv(name)
+		if !ok && strings.TrimSpace(os.Getenv("JIRA_DEBUG")) != "" {
+			fmt.Fprintf(os.Stderr, "config: ${%s} is not set; using an empty value\n", name)
+		}
+		return v
+	})
 }
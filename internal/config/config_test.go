@@ -0,0 +1,402 @@
+package config
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeConfig writes content to a config file in a temporary directory.
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+const profilesConfig = `
+default_profile: prod
+jira:
+  token: secret
+  sprint_field: customfield_10020
+profiles:
+  prod:
+    jira:
+      url: https://prod.example.com
+      email: prod@example.com
+  staging:
+    jira:
+      url: https://staging.example.com
+      email: staging@example.com
+      sprint_field: customfield_10099
+`
+
+func TestLoadProfileSelection(t *testing.T) {
+	path := writeConfig(t, profilesConfig)
+	for _, tc := range []struct {
+		profile     string
+		wantProfile string
+		wantURL     string
+		wantSprint  string
+	}{
+		{"", "prod", "https://prod.example.com", "customfield_10020"},
+		{"prod", "prod", "https://prod.example.com", "customfield_10020"},
+		{"staging", "staging", "https://staging.example.com", "customfield_10099"},
+	} {
+		cfg, err := LoadProfile(path, tc.profile)
+		if err != nil {
+			t.Fatalf("LoadProfile(%q): %v", tc.profile, err)
+		}
+		if cfg.Profile != tc.wantProfile || cfg.Jira.URL != tc.wantURL || cfg.Jira.SprintField != tc.wantSprint {
+			t.Errorf("LoadProfile(%q) = profile %q, url %q, sprint field %q; want %q, %q, %q",
+				tc.profile, cfg.Profile, cfg.Jira.URL, cfg.Jira.SprintField, tc.wantProfile, tc.wantURL, tc.wantSprint)
+		}
+		if got, want := cfg.Source("jira.url"), "profile "+tc.wantProfile; got != want {
+			t.Errorf("LoadProfile(%q): jira.url source = %q, want %q", tc.profile, got, want)
+		}
+		if got := cfg.Source("jira.api_token"); got != "file" {
+			t.Errorf("LoadProfile(%q): jira.api_token source = %q, want file", tc.profile, got)
+		}
+	}
+}
+
+func TestLoadProfileWithoutDefault(t *testing.T) {
+	path := writeConfig(t, `
+jira:
+  url: https://base.example.com
+  email: base@example.com
+  token: secret
+profiles:
+  prod:
+    jira:
+      url: https://prod.example.com
+`)
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.Profile != "" || cfg.Jira.URL != "https://base.example.com" {
+		t.Errorf("Load without default_profile = profile %q, url %q; want no profile and the top-level url", cfg.Profile, cfg.Jira.URL)
+	}
+}
+
+func TestLoadProfileErrors(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		content string
+		profile string
+		want    string
+	}{
+		{"unknown profile", profilesConfig, "qa", `unknown profile "qa" (available: prod, staging)`},
+		{"unknown default profile", strings.Replace(profilesConfig, "default_profile: prod", "default_profile: qa", 1), "", `unknown profile "qa"`},
+		{"no profiles", "jira:\n  url: https://example.com\n  email: a@example.com\n  token: secret\n", "prod", "defines no profiles"},
+		{"profile without jira block", "profiles:\n  prod:\n    url: https://example.com\n", "prod", "profiles only contain a jira block"},
+		{"bad key in profile", profilesConfig + "      page_size: lots\n", "staging", "profile staging: config line 16: jira page_size must be a whole number"},
+		{"bad indentation", "jira:\n  url: https://example.com\n    email: a@example.com\n", "", "parse config: yaml: line 3"},
+		{"tab indentation", "jira:\n\turl: https://example.com\n", "", "parse config: yaml: line 2"},
+		{"stray root value", "url: https://example.com\n", "", `config line 1: unrecognized config key "url"`},
+		{"nested value", "jira:\n  url:\n    host: example.com\n", "", "config line 3: url must be a single value"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := LoadProfile(writeConfig(t, tc.content), tc.profile)
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("LoadProfile error = %v, want one containing %q", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestLoadOutputValues(t *testing.T) {
+	t.Setenv("WK_TEST_ORDER", "Blocked")
+	path := writeConfig(t, `
+jira:
+  url: "https://example.com/jira"  # quoted, with a comment
+  email: 'a@example.com'
+  token: "se:cr#et"
+output:
+  status_order: [To Do, "In Progress: Dev", "${WK_TEST_ORDER}"]
+  allowed_statuses:
+    - To Do
+    - "Done, really"
+  status_aliases: {QA: In Review, "In QA": In Review}
+  widths: {table: 120, docs: 0}
+  status_colors:
+    QA: cyan
+`)
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.Jira.URL != "https://example.com/jira" || cfg.Jira.Email != "a@example.com" || cfg.Jira.APIToken != "se:cr#et" {
+		t.Errorf("jira = %q, %q, %q", cfg.Jira.URL, cfg.Jira.Email, cfg.Jira.APIToken)
+	}
+	out := cfg.Output
+	if want := []string{"To Do", "In Progress: Dev", "Blocked"}; !reflect.DeepEqual(out.StatusOrder, want) {
+		t.Errorf("status_order = %q, want %q", out.StatusOrder, want)
+	}
+	if want := []string{"To Do", "Done, really"}; !reflect.DeepEqual(out.AllowedStatuses, want) {
+		t.Errorf("allowed_statuses = %q, want %q", out.AllowedStatuses, want)
+	}
+	if want := map[string]string{"QA": "In Review", "In QA": "In Review"}; !reflect.DeepEqual(out.StatusAliases, want) {
+		t.Errorf("status_aliases = %q, want %q", out.StatusAliases, want)
+	}
+	if want := map[string]string{"QA": "cyan"}; !reflect.DeepEqual(out.StatusColors, want) {
+		t.Errorf("status_colors = %q, want %q", out.StatusColors, want)
+	}
+	if want := map[string]int{"table": 120, "docs": 0}; !reflect.DeepEqual(out.Widths, want) {
+		t.Errorf("widths = %v, want %v", out.Widths, want)
+	}
+}
+
+func TestLoadAPITokenFile(t *testing.T) {
+	path := writeConfig(t, "jira:\n  url: https://example.com\n  email: a@example.com\n  api_token_file: secrets/token\n")
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(filepath.Join(dir, "secrets"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	tokenPath := filepath.Join(dir, "secrets", "token")
+	if err := os.WriteFile(tokenPath, []byte("  from-file\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.Jira.APIToken != "from-file" || cfg.Source("jira.api_token") != "api_token_file" {
+		t.Errorf("api token = %q from %q, want from-file from api_token_file", cfg.Jira.APIToken, cfg.Source("jira.api_token"))
+	}
+
+	// A token given directly wins over the file.
+	t.Setenv("JIRA_API_TOKEN", "from-env")
+	if cfg, err := Load(path); err != nil {
+		t.Errorf("Load with JIRA_API_TOKEN: %v", err)
+	} else if cfg.Jira.APIToken != "from-env" {
+		t.Errorf("api token with JIRA_API_TOKEN = %q, want from-env", cfg.Jira.APIToken)
+	}
+	t.Setenv("JIRA_API_TOKEN", "")
+
+	if err := os.WriteFile(tokenPath, []byte("\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil || err.Error() != "jira api_token_file "+tokenPath+" is empty" {
+		t.Errorf("Load with an empty token file error = %v", err)
+	}
+	if err := os.Remove(tokenPath); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil || !strings.Contains(err.Error(), "read jira api_token_file") {
+		t.Errorf("Load with a missing token file error = %v", err)
+	}
+}
+
+// captureStderr calls fn and returns what it printed to stderr.
+func captureStderr(t *testing.T, fn func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("pipe: %v", err)
+	}
+	stderr := os.Stderr
+	os.Stderr = w
+	defer func() { os.Stderr = stderr }()
+
+	output := make(chan string)
+	go func() {
+		data, _ := io.ReadAll(r)
+		output <- string(data)
+	}()
+	fn()
+	w.Close()
+	return <-output
+}
+
+func TestExpandEnv(t *testing.T) {
+	t.Setenv("WK_TEST_HOST", "example.com")
+	t.Setenv("WK_TEST_EMPTY", "")
+	t.Setenv("WK_TEST_MISSING", "")
+	os.Unsetenv("WK_TEST_MISSING")
+	for value, want := range map[string]string{
+		"https://${WK_TEST_HOST}/jira":      "https://example.com/jira",
+		"${WK_TEST_HOST}${WK_TEST_HOST}":    "example.comexample.com",
+		"[${WK_TEST_EMPTY}]":                "[]",
+		"[${WK_TEST_MISSING}]":              "[]",
+		"pa$$word $WK_TEST_HOST ${1BAD} ${": "pa$$word $WK_TEST_HOST ${1BAD} ${",
+	} {
+		if got := expandEnv(value); got != want {
+			t.Errorf("expandEnv(%q) = %q, want %q", value, got, want)
+		}
+	}
+
+	t.Setenv("JIRA_DEBUG", "1")
+	warning := captureStderr(t, func() {
+		expandEnv("${WK_TEST_EMPTY}${WK_TEST_MISSING}")
+	})
+	if want := "config: ${WK_TEST_MISSING} is not set; using an empty value\n"; warning != want {
+		t.Errorf("JIRA_DEBUG warning = %q, want %q", warning, want)
+	}
+}
+
+func TestLoadExpandsEnvInValues(t *testing.T) {
+	t.Setenv("WK_TEST_TOKEN", "se$cret")
+	t.Setenv("WK_TEST_MISSING", "")
+	os.Unsetenv("WK_TEST_MISSING")
+	path := writeConfig(t, "jira:\n  url: https://example.com\n  email: someone${WK_TEST_MISSING}@example.com\n  token: ${WK_TEST_TOKEN}\n")
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.Jira.APIToken != "se$cret" || cfg.Jira.Email != "someone@example.com" {
+		t.Errorf("token %q, email %q; want the expanded values", cfg.Jira.APIToken, cfg.Jira.Email)
+	}
+}
+
+func TestClampPageSize(t *testing.T) {
+	for _, tc := range []struct {
+		size    int
+		want    int
+		warning string
+	}{
+		{0, 0, ""},
+		{1, 1, ""},
+		{100, 100, ""},
+		{-5, 1, "config: jira page_size -5 is outside 1-100; using 1\n"},
+		{500, 100, "config: jira page_size 500 is outside 1-100; using 100\n"},
+	} {
+		jira := JiraConfig{PageSize: tc.size}
+		warning := captureStderr(t, func() { clampPageSize(&jira) })
+		if jira.PageSize != tc.want || warning != tc.warning {
+			t.Errorf("clampPageSize(%d) = %d, warning %q; want %d, %q", tc.size, jira.PageSize, warning, tc.want, tc.warning)
+		}
+	}
+}
+
+func TestLoadClampsPageSize(t *testing.T) {
+	path := writeConfig(t, "jira:\n  url: https://example.com\n  email: a@example.com\n  token: secret\n  page_size: 250\n")
+	var cfg *Config
+	warning := captureStderr(t, func() {
+		var err error
+		if cfg, err = Load(path); err != nil {
+			t.Errorf("Load: %v", err)
+		}
+	})
+	if cfg == nil || cfg.Jira.PageSize != 100 || !strings.Contains(warning, "page_size 250 is outside 1-100") {
+		t.Errorf("Load with page_size 250 = %+v, warning %q; want 100 and a warning", cfg, warning)
+	}
+}
+
+func TestEnvOverridesPrecedence(t *testing.T) {
+	path := writeConfig(t, "jira:\n  url: https://file.example.com\n  email: file@example.com\n  token: file-token\n  page_size: 50\noutput:\n  default_mode: docs\n")
+	t.Setenv("JIRA_URL", "https://env.example.com")
+	t.Setenv("JIRA_API_TOKEN", "api-token")
+	// JIRA_TOKEN comes after JIRA_API_TOKEN in envOverrides, so it wins.
+	t.Setenv("JIRA_TOKEN", "token")
+	t.Setenv("JIRA_EMAIL", "  ")
+	t.Setenv("JIRA_PAGE_SIZE", "25")
+	t.Setenv("WKREPORT_OUTPUT_MODE", "slides")
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	for _, tc := range []struct {
+		key, got, want, source string
+	}{
+		{"jira.url", cfg.Jira.URL, "https://env.example.com", "JIRA_URL env"},
+		{"jira.api_token", cfg.Jira.APIToken, "token", "JIRA_TOKEN env"},
+		{"jira.email", cfg.Jira.Email, "file@example.com", "file"},
+		{"jira.page_size", strconv.Itoa(cfg.Jira.PageSize), "25", "JIRA_PAGE_SIZE env"},
+		{"output.default_mode", cfg.Output.DefaultMode, "slides", "WKREPORT_OUTPUT_MODE env"},
+	} {
+		if tc.got != tc.want || cfg.Source(tc.key) != tc.source {
+			t.Errorf("%s = %q from %q, want %q from %q", tc.key, tc.got, cfg.Source(tc.key), tc.want, tc.source)
+		}
+	}
+
+	t.Setenv("JIRA_PAGE_SIZE", "lots")
+	if _, err := Load(path); err == nil || !strings.HasPrefix(err.Error(), "JIRA_PAGE_SIZE: ") {
+		t.Errorf("Load with a bad JIRA_PAGE_SIZE error = %v, want it named", err)
+	}
+}
+
+func TestEnvOverrideNames(t *testing.T) {
+	for _, override := range envOverrides {
+		want := "JIRA_" + strings.ToUpper(override.key)
+		if override.section == "output" {
+			want = "WKREPORT_OUTPUT_" + strings.ToUpper(strings.TrimPrefix(override.key, "default_"))
+		}
+		if override.name != want {
+			t.Errorf("%s.%s is overridden by %s, want %s", override.section, override.key, override.name, want)
+		}
+	}
+}
+
+func TestLoadTypedValues(t *testing.T) {
+	path := writeConfig(t, `
+jira:
+  url: https://example.com
+  email: a@example.com
+  token: secret
+  enhanced_search: true
+  atlassian_token_nocheck: "1"
+  max_issues: 0
+  max_conns_per_host: 4
+  idle_conn_timeout: 90s
+`)
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	j := cfg.Jira
+	if !j.EnhancedSearch || !j.AtlassianTokenNoCheck || j.MaxIssues != 0 || j.MaxConnsPerHost != 4 || j.IdleConnTimeout != 90*time.Second {
+		t.Errorf("jira = %+v, want the typed values from the file", j)
+	}
+
+	for _, tc := range []struct {
+		line string
+		want string
+	}{
+		{"enhanced_search: maybe", `jira enhanced_search must be true or false, got "maybe"`},
+		{"max_issues: -1", `jira max_issues must be a whole number of at least 0, got "-1"`},
+		{"max_issues: 1.5", `jira max_issues must be a whole number of at least 0, got "1.5"`},
+		{"page_size: ten", `jira page_size must be a whole number, got "ten"`},
+		{"idle_conn_timeout: 90", `jira idle_conn_timeout must be a duration such as 90s, got "90"`},
+		{"idle_conn_timeout: -5s", `jira idle_conn_timeout must be a duration such as 90s, got "-5s"`},
+	} {
+		content := "jira:\n  url: https://example.com\n  email: a@example.com\n  token: secret\n  " + tc.line + "\n"
+		if _, err := Load(writeConfig(t, content)); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("Load with %q error = %v, want %q", tc.line, err, tc.want)
+		}
+	}
+}
+
+func TestConfigSources(t *testing.T) {
+	path := writeConfig(t, profilesConfig+"output:\n  ellipsis: ascii\n")
+	t.Setenv("JIRA_EMAIL", "env@example.com")
+
+	cfg, err := LoadProfile(path, "staging")
+	if err != nil {
+		t.Fatalf("LoadProfile: %v", err)
+	}
+	for key, want := range map[string]string{
+		"jira.url":          "profile staging",
+		"jira.sprint_field": "profile staging",
+		"jira.email":        "JIRA_EMAIL env",
+		"jira.api_token":    "file",
+		"jira.token":        SourceDefault,
+		"jira.page_size":    SourceDefault,
+		"output.ellipsis":   "file",
+		"output.widths":     SourceDefault,
+	} {
+		if got := cfg.Source(key); got != want {
+			t.Errorf("Source(%q) = %q, want %q", key, got, want)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package jira
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// adfNode is a node of an Atlassian Document Format tree, the rich text encoding API v3
+// uses for issue descriptions.
+type adfNode struct {
+	Type    string    `json:"type"`
+	Text    string    `json:"text"`
+	Content []adfNode `json:"content"`
+	Attrs   struct {
+		Text string `json:"text"`
+		URL  string `json:"url"`
+	} `json:"attrs"`
+	Marks []struct {
+		Type  string `json:"type"`
+		Attrs struct {
+			Href string `json:"href"`
+		} `json:"attrs"`
+	} `json:"marks"`
+}
+
+// descriptionText returns a description field as plain text. API v3 sends an ADF
+// document, older instances a plain string; anything else decodes to "".
+func descriptionText(raw json.RawMessage) string {
+	var plain string
+	if err := json.Unmarshal(raw, &plain); err == nil {
+		return strings.TrimSpace(plain)
+	}
+	var doc adfNode
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return ""
+	}
+	return flattenADF(doc)
+}
+
+// flattenADF renders an ADF tree as plain text: blocks are separated by blank lines,
+// bullet and numbered list items are prefixed with "- " and "1. " and indented by
+// nesting, and links keep their target in parentheses after the link text.
+func flattenADF(doc adfNode) string {
+	var blocks []string
+	for _, node := range doc.Content {
+		if text := adfBlock(node, ""); text != "" {
+			blocks = append(blocks, text)
+		}
+	}
+	return strings.Join(blocks, "\n\n")
+}
+
+// adfBlock renders one block node, indenting continuation lines of list items.
+func adfBlock(node adfNode, indent string) string {
+	switch node.Type {
+	case "bulletList", "orderedList":
+		var items []string
+		for i, item := range node.Content {
+			marker := "- "
+			if node.Type == "orderedList" {
+				marker = strconv.Itoa(i+1) + ". "
+			}
+			var parts []string
+			for _, child := range item.Content {
+				if text := adfBlock(child, indent+"  "); text != "" {
+					parts = append(parts, text)
+				}
+			}
+			items = append(items, indent+marker+strings.TrimLeft(strings.Join(parts, "\n"), " "))
+		}
+		return strings.Join(items, "\n")
+	case "paragraph", "heading", "codeBlock":
+		return indentLines(adfInline(node.Content), indent)
+	default:
+		// Quotes, panels, tables, and other containers are flattened to their blocks.
+		if len(node.Content) == 0 {
+			return indentLines(adfInline([]adfNode{node}), indent)
+		}
+		var parts []string
+		for _, child := range node.Content {
+			if text := adfBlock(child, indent); text != "" {
+				parts = append(parts, text)
+			}
+		}
+		return strings.Join(parts, "\n")
+	}
+}
+
+// indentLines trims text and indents each of its lines, returning "" for blank text.
+func indentLines(text, indent string) string {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return ""
+	}
+	return indent + strings.ReplaceAll(text, "\n", "\n"+indent)
+}
+
+// adfInline renders inline nodes: text (with links), hard breaks, mentions, emoji, and
+// smart links.
+func adfInline(nodes []adfNode) string {
+	var b strings.Builder
+	for _, node := range nodes {
+		switch node.Type {
+		case "text":
+			b.WriteString(node.Text)
+			for _, mark := range node.Marks {
+				if mark.Type == "link" && mark.Attrs.Href != "" && mark.Attrs.Href != node.Text {
+					b.WriteString(" (" + mark.Attrs.Href + ")")
+				}
+			}
+		case "hardBreak":
+			b.WriteString("\n")
+		case "mention", "emoji", "date", "status":
+			b.WriteString(node.Attrs.Text)
+		case "inlineCard", "blockCard", "embedCard":
+			b.WriteString(node.Attrs.URL)
+		default:
+			b.WriteString(adfInline(node.Content))
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,94 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestDescriptionText(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		raw  string
+		want string
+	}{
+		{"plain string", `"  Old style description \n"`, "Old style description"},
+		{"null", `null`, ""},
+		{"number", `42`, ""},
+		{"paragraphs", `{"type":"doc","content":[
+			{"type":"paragraph","content":[{"type":"text","text":"First"},{"type":"hardBreak"},{"type":"text","text":"line two"}]},
+			{"type":"paragraph","content":[]},
+			{"type":"heading","content":[{"type":"text","text":"Second"}]}
+		]}`, "First\nline two\n\nSecond"},
+		{"links and mentions", `{"type":"doc","content":[{"type":"paragraph","content":[
+			{"type":"mention","attrs":{"text":"@Pat Lee"}},
+			{"type":"text","text":" see "},
+			{"type":"text","text":"the docs","marks":[{"type":"strong"},{"type":"link","attrs":{"href":"https://example.com/docs"}}]},
+			{"type":"text","text":", "},
+			{"type":"text","text":"https://example.com","marks":[{"type":"link","attrs":{"href":"https://example.com"}}]},
+			{"type":"text","text":" and "},
+			{"type":"inlineCard","attrs":{"url":"https://jira.example.com/browse/ABC-1"}},
+			{"type":"text","text":" "},
+			{"type":"emoji","attrs":{"text":":tada:"}}
+		]}]}`, "@Pat Lee see the docs (https://example.com/docs), https://example.com and https://jira.example.com/browse/ABC-1 :tada:"},
+		{"lists", `{"type":"doc","content":[
+			{"type":"bulletList","content":[
+				{"type":"listItem","content":[
+					{"type":"paragraph","content":[{"type":"text","text":"Outer"}]},
+					{"type":"orderedList","content":[
+						{"type":"listItem","content":[{"type":"paragraph","content":[{"type":"text","text":"One"}]}]},
+						{"type":"listItem","content":[{"type":"paragraph","content":[{"type":"text","text":"Two"},{"type":"hardBreak"},{"type":"text","text":"wrapped"}]}]}
+					]}
+				]},
+				{"type":"listItem","content":[{"type":"paragraph","content":[{"type":"text","text":"Second outer"}]}]}
+			]},
+			{"type":"paragraph","content":[{"type":"text","text":"After"}]}
+		]}`, "- Outer\n  1. One\n  2. Two\n    wrapped\n- Second outer\n\nAfter"},
+		{"containers", `{"type":"doc","content":[
+			{"type":"blockquote","content":[{"type":"paragraph","content":[{"type":"text","text":"Quoted"}]}]},
+			{"type":"panel","content":[{"type":"paragraph","content":[{"type":"text","text":"Note"}]},{"type":"paragraph","content":[{"type":"text","text":"More"}]}]},
+			{"type":"rule"}
+		]}`, "Quoted\n\nNote\nMore"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := descriptionText(json.RawMessage(tc.raw)); got != tc.want {
+				t.Errorf("descriptionText = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestWithDescription(t *testing.T) {
+	var fields []string
+	srv := newSearchServer(t, &searchServer{
+		ids: []string{"1"},
+		issue: func(w http.ResponseWriter, r *http.Request, id string) {
+			fields = append(fields, r.URL.Query().Get("fields"))
+			writeJSON(t, w, map[string]any{"key": "ABC-1", "fields": map[string]any{
+				"description": map[string]any{"type": "doc", "content": []any{
+					map[string]any{"type": "paragraph", "content": []any{map[string]any{"type": "text", "text": "Details"}}},
+				}},
+			}})
+		},
+	})
+
+	for _, enabled := range []bool{false, true} {
+		issues, err := newTestClient(t, srv.Server, WithDescription(enabled)).SearchByJQL(context.Background(), "project = ABC")
+		if err != nil || len(issues) != 1 {
+			t.Fatalf("SearchByJQL = %v, %v", issues, err)
+		}
+		want := ""
+		if enabled {
+			want = "Details"
+		}
+		if issues[0].Description != want {
+			t.Errorf("WithDescription(%v): description = %q, want %q", enabled, issues[0].Description, want)
+		}
+		if requested := slices.Contains(strings.Split(fields[len(fields)-1], ","), "description"); requested != enabled {
+			t.Errorf("WithDescription(%v): fields %q", enabled, fields[len(fields)-1])
+		}
+	}
+}
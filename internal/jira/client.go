@@ -12,24 +12,265 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
 // Client communicates with the Jira REST API.
 type Client struct {
-	baseURL    string
-	httpClient *http.Client
-	authHeader string
+	baseURL          string
+	browseURL        string
+	httpClient       *http.Client
+	authHeader       string
+	oauth            *oauthSession
+	bestEffort       bool
+	sprintField      string
+	storyPointsField string
+	pageSize         int
+	enhancedSearch   bool
+	maxIssues        int
+	onIssue          func(Issue)
+	onRawIssue       func(json.RawMessage)
+	journalLookup    func(issueID string) (Issue, bool)
+	journalRecord    func(issueID string, issue Issue)
+	issueTimeout     time.Duration
+	withComments     bool
+	withDescription  bool
+	extraFields      []string
+	userAgent        string
+	tokenNoCheck     bool
+	pool             ConnPool
+
+	stats clientStats
+
+	// filterCache holds filters fetched by id during this client's lifetime, so
+	// resolving and then searching a filter costs one filter request.
+	filterMu    sync.Mutex
+	filterCache map[int]Filter
+
+	// summaryCache holds the summaries IssueSummaries has looked up, keyed by issue key.
+	summaryMu    sync.Mutex
+	summaryCache map[string]string
+}
+
+// Option customizes a Client created by NewClient.
+type Option func(*Client)
+
+// WithBestEffort makes searches skip issues whose details cannot be fetched. The skipped
+// issues are reported through a *PartialResultError returned alongside the other results.
+func WithBestEffort(enabled bool) Option {
+	return func(c *Client) {
+		c.bestEffort = enabled
+	}
+}
+
+// DefaultUserAgent is sent unless WithUserAgent overrides it.
+const DefaultUserAgent = "wkreport"
+
+// WithUserAgent sets the User-Agent header sent with every request. An empty value
+// keeps DefaultUserAgent.
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		if userAgent = strings.TrimSpace(userAgent); userAgent != "" {
+			c.userAgent = userAgent
+		}
+	}
+}
+
+// WithAtlassianTokenNoCheck sends X-Atlassian-Token: no-check on every request. Jira
+// only requires it on some write endpoints, but some gateways reject any request
+// without it; it is harmless on reads.
+func WithAtlassianTokenNoCheck(enabled bool) Option {
+	return func(c *Client) {
+		c.tokenNoCheck = enabled
+	}
+}
+
+// WithSprintField decodes the active sprint name from the given custom field
+// (for example customfield_10020) into Issue.Sprint.
+func WithSprintField(field string) Option {
+	return func(c *Client) {
+		c.sprintField = strings.TrimSpace(field)
+	}
+}
+
+// WithStoryPointsField decodes story points from the given custom field
+// (for example customfield_10016) into Issue.StoryPoints.
+func WithStoryPointsField(field string) Option {
+	return func(c *Client) {
+		c.storyPointsField = strings.TrimSpace(field)
+	}
+}
+
+// MaxPageSize is the largest page Jira returns from its search and filter listing APIs.
+const MaxPageSize = 100
+
+// WithPageSize sets how many results each search or filter listing request asks for.
+// Values outside 1 to MaxPageSize keep the default of MaxPageSize.
+func WithPageSize(size int) Option {
+	return func(c *Client) {
+		if size >= 1 && size <= MaxPageSize {
+			c.pageSize = size
+		}
+	}
+}
+
+// WithEnhancedSearch sends searches to the enhanced /rest/api/3/search/jql endpoint,
+// which pages with nextPageToken, instead of the deprecated /rest/api/3/search.
+// Filter search URLs that point at the old endpoint are rewritten as well.
+func WithEnhancedSearch(enabled bool) Option {
+	return func(c *Client) {
+		c.enhancedSearch = enabled
+	}
+}
+
+// WithMaxIssues makes searches fail with a *TooManyIssuesError, before any issue details
+// are fetched, when more than limit issues match. Zero or less means no limit.
+func WithMaxIssues(limit int) Option {
+	return func(c *Client) {
+		c.maxIssues = limit
+	}
+}
+
+// WithComments requests each issue's comments and decodes their count into
+// Issue.CommentCount. It is opt-in because comments enlarge every issue response.
+func WithComments(enabled bool) Option {
+	return func(c *Client) {
+		c.withComments = enabled
+	}
+}
+
+// WithDescription requests each issue's description and decodes it into
+// Issue.Description as plain text. Like WithComments it is opt-in, since descriptions
+// are often the largest part of an issue.
+func WithDescription(enabled bool) Option {
+	return func(c *Client) {
+		c.withDescription = enabled
+	}
+}
+
+// WithExtraFields adds fields to the search and issue detail requests. Their values are
+// decoded into Issue.Extra, keyed by field id, for templates and JSON output.
+func WithExtraFields(fields []string) Option {
+	return func(c *Client) {
+		c.extraFields = nil
+		for _, field := range fields {
+			if field = strings.TrimSpace(field); field != "" {
+				c.extraFields = append(c.extraFields, field)
+			}
+		}
+	}
+}
+
+// WithIssueCallback calls fn with each issue as soon as its details are fetched, in
+// search order, so callers can show results before the whole search completes.
+func WithIssueCallback(fn func(Issue)) Option {
+	return func(c *Client) {
+		c.onIssue = fn
+	}
+}
+
+// WithIssueJournal lets an interrupted search be resumed. Before an issue's details are
+// requested, lookup is asked for the details an earlier run fetched, which are used
+// instead when it reports true; record is called with each issue whose details were
+// fetched, so that a later run can look them up.
+func WithIssueJournal(lookup func(issueID string) (Issue, bool), record func(issueID string, issue Issue)) Option {
+	return func(c *Client) {
+		c.journalLookup = lookup
+		c.journalRecord = record
+	}
+}
+
+// WithRawSearch hands fn each issue of the search responses exactly as Jira sent it,
+// instead of fetching and decoding the issue details. Searches then return no issues.
+func WithRawSearch(fn func(json.RawMessage)) Option {
+	return func(c *Client) {
+		c.onRawIssue = fn
+	}
+}
+
+// WithIssueTimeout limits how long each issue's detail request may take; zero means no
+// limit beyond the HTTP client's. An issue that runs out of time fails like any other,
+// so best-effort searches skip it.
+func WithIssueTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.issueTimeout = timeout
+	}
+}
+
+// WithBrowseURL builds issue and issue navigator links from base instead of the API base
+// URL, for instances whose web UI is served from a different address. An empty base
+// keeps the default.
+func WithBrowseURL(base string) Option {
+	return func(c *Client) {
+		if base = strings.TrimRight(strings.TrimSpace(base), "/"); base != "" {
+			c.browseURL = base
+		}
+	}
+}
+
+// IssueFailure records an issue whose details could not be fetched.
+type IssueFailure struct {
+	ID  string
+	Err error
+}
+
+// PartialResultError reports the issues skipped by a best-effort search.
+type PartialResultError struct {
+	Failures []IssueFailure
+}
+
+func (e *PartialResultError) Error() string {
+	return fmt.Sprintf("%d issue(s) could not be fetched", len(e.Failures))
+}
+
+// TooManyIssuesError reports a search that matched more issues than WithMaxIssues allows.
+type TooManyIssuesError struct {
+	// Matched is the search's total when Jira reported one, otherwise the number of
+	// matches seen before giving up (so the real count is at least Matched).
+	Matched int
+	Exact   bool
+	Limit   int
+}
+
+func (e *TooManyIssuesError) Error() string {
+	if e.Exact {
+		return fmt.Sprintf("search matched %d issues, exceeding the limit of %d", e.Matched, e.Limit)
+	}
+	return fmt.Sprintf("search matched more than %d issues, exceeding the limit of %d", e.Limit, e.Limit)
 }
 
 // Issue represents a condensed view of a Jira issue.
 type Issue struct {
-	Key      string
-	Summary  string
-	Status   string
-	Parent   string
-	Resolved string
-	URL      string
+	Key     string `json:"key"`
+	Summary string `json:"summary"`
+	Status  string `json:"status"`
+	Parent  string `json:"parent,omitempty"`
+	// ParentType is the parent's issue type name, such as "Epic" for epic-as-parent
+	// issues or a standard type for subtask parents.
+	ParentType    string `json:"parentType,omitempty"`
+	ParentSummary string `json:"parentSummary,omitempty"`
+	ParentURL     string `json:"parentUrl,omitempty"`
+	Resolved      string `json:"resolved,omitempty"`
+	URL           string `json:"url,omitempty"`
+	// Updated is when the issue last changed, in ResolvedLayout.
+	Updated string `json:"updated,omitempty"`
+	// Assignee is the assignee's display name, empty for unassigned issues.
+	Assignee string `json:"assignee,omitempty"`
+	// Type and Priority are the names of the issue's type and priority.
+	Type     string `json:"type,omitempty"`
+	Priority string `json:"priority,omitempty"`
+	// Sprint is the name of the issue's active sprint, when a sprint field is configured.
+	Sprint string `json:"sprint,omitempty"`
+	// StoryPoints is nil when no story points field is configured or the issue has none.
+	StoryPoints *float64 `json:"storyPoints,omitempty"`
+	// CommentCount is only filled in when the client was created WithComments.
+	CommentCount int `json:"commentCount,omitempty"`
+	// Description is the issue's description as plain text, only filled in when the
+	// client was created WithDescription.
+	Description string `json:"description,omitempty"`
+	// Extra holds the decoded values of the fields requested WithExtraFields.
+	Extra map[string]any `json:"extra,omitempty"`
 }
 
 // Filter captures the minimal details needed to execute a Jira filter.
@@ -38,12 +279,36 @@ type Filter struct {
 	Name      string
 	JQL       string
 	SearchURL string
+	// Owner is the display name of the filter's owner.
+	Owner string
+	// Favourite reports whether the current user has starred the filter.
+	Favourite bool
 }
 
 var errFilterNotFound = errors.New("filter not found")
 
+// AmbiguousFilterError reports a filter name that matches several filters without
+// exactly matching one of them.
+type AmbiguousFilterError struct {
+	Name       string
+	Candidates []Filter
+}
+
+func (e *AmbiguousFilterError) Error() string {
+	names := make([]string, len(e.Candidates))
+	for i, f := range e.Candidates {
+		names[i] = fmt.Sprintf("%q (%d)", f.Name, f.ID)
+	}
+	return fmt.Sprintf("filter name %q matches %d filters: %s; use the numeric id to choose one",
+		e.Name, len(e.Candidates), strings.Join(names, ", "))
+}
+
+// ErrInterrupted is wrapped by search errors when the context is canceled (for example by
+// Ctrl-C). Searches return the issues fetched before the interruption alongside it.
+var ErrInterrupted = errors.New("interrupted")
+
 // NewClient creates a Jira API client configured for the provided credentials.
-func NewClient(baseURL, email, apiToken string) (*Client, error) {
+func NewClient(baseURL, email, apiToken string, opts ...Option) (*Client, error) {
 	base := strings.TrimRight(baseURL, "/")
 	if base == "" {
 		return nil, errors.New("jira base url is required")
@@ -57,34 +322,142 @@ func NewClient(baseURL, email, apiToken string) (*Client, error) {
 
 	authPayload := base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("%s:%s", email, apiToken)))
 
-	return &Client{
-		baseURL:    base,
-		httpClient: &http.Client{Timeout: 30 * time.Second},
-		authHeader: "Basic " + authPayload,
-	}, nil
+	return newClient(base, "Basic "+authPayload, nil, opts), nil
 }
 
-// ResolveFilter resolves an identifier (name or numeric id) to a filter definition.
+// NewOAuthClient creates a Jira API client that authenticates with an OAuth 2.0 (3LO)
+// bearer token. When a refresh token and client credentials are supplied, an expired
+// access token is refreshed transparently and the failed request retried once.
+func NewOAuthClient(baseURL string, creds OAuthCredentials, opts ...Option) (*Client, error) {
+	base := strings.TrimRight(baseURL, "/")
+	if base == "" {
+		return nil, errors.New("jira base url is required")
+	}
+	session, err := newOAuthSession(creds)
+	if err != nil {
+		return nil, err
+	}
+
+	return newClient(base, "", session, opts), nil
+}
+
+func newClient(base, authHeader string, session *oauthSession, opts []Option) *Client {
+	client := &Client{
+		baseURL:      base,
+		browseURL:    base,
+		pageSize:     MaxPageSize,
+		userAgent:    DefaultUserAgent,
+		authHeader:   authHeader,
+		oauth:        session,
+		filterCache:  make(map[int]Filter),
+		summaryCache: make(map[string]string),
+		pool: ConnPool{
+			MaxIdleConnsPerHost: DefaultMaxIdleConnsPerHost,
+			MaxConnsPerHost:     DefaultMaxConnsPerHost,
+			IdleConnTimeout:     DefaultIdleConnTimeout,
+		},
+	}
+	for _, opt := range opts {
+		opt(client)
+	}
+	client.httpClient = &http.Client{
+		Timeout:       30 * time.Second,
+		Transport:     &statsTransport{base: newTransport(client.pool), stats: &client.stats},
+		CheckRedirect: keepAuthOnRedirect,
+	}
+	return client
+}
+
+// do sends an authenticated JSON request. With OAuth credentials, a 401 response triggers
+// a token refresh and a single retry of the request. When the refresh fails, the error
+// wraps both the original 401, as an *APIError, and the refresh failure.
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", c.userAgent)
+	if c.tokenNoCheck {
+		req.Header.Set("X-Atlassian-Token", "no-check")
+	}
+	if c.oauth == nil {
+		req.Header.Set("Authorization", c.authHeader)
+		return c.httpClient.Do(req)
+	}
+
+	token := c.oauth.accessToken()
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp, err := c.httpClient.Do(req)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized || !c.oauth.canRefresh() {
+		return resp, err
+	}
+	unauthorized := newAPIError(req.URL.Path, resp)
+	resp.Body.Close()
+
+	refreshed, err := c.oauth.refresh(req.Context(), c.httpClient, c.userAgent, token)
+	if err != nil {
+		return nil, fmt.Errorf("%w; refresh oauth token: %w", unauthorized, err)
+	}
+
+	retry := req.Clone(req.Context())
+	retry.Header.Set("Authorization", "Bearer "+refreshed)
+	return c.httpClient.Do(retry)
+}
+
+// ResolveFilter resolves an identifier (name, numeric id, or filter URL) to a filter
+// definition.
 func (c *Client) ResolveFilter(ctx context.Context, identifier string) (*Filter, error) {
 	identifier = strings.TrimSpace(identifier)
 	if identifier == "" {
 		return nil, errors.New("empty filter identifier")
 	}
+	if strings.Contains(identifier, "://") {
+		id, ok := FilterIDFromURL(identifier)
+		if !ok {
+			return nil, errors.New("no filter id in URL (expected a filter= parameter or a /filter/<id> path)")
+		}
+		return c.filterByID(ctx, id)
+	}
 
+	id, idErr := strconv.Atoi(identifier)
+	var ambiguous *AmbiguousFilterError
 	if filter, err := c.filterByName(ctx, identifier); err == nil {
 		return filter, nil
+	} else if errors.As(err, &ambiguous) && idErr == nil {
+		// A numeric identifier that happens to appear in several names is an id.
 	} else if !errors.Is(err, errFilterNotFound) {
 		return nil, err
 	}
 
-	if id, err := strconv.Atoi(identifier); err == nil {
+	if idErr == nil {
 		return c.filterByID(ctx, id)
 	}
 
 	return nil, fmt.Errorf("filter %q not found", identifier)
 }
 
-// SearchByFilter fetches issues that belong to the provided Jira filter.
+// FilterIDFromURL extracts the filter id from a Jira URL such as
+// https://x.atlassian.net/issues/?filter=12345 or .../filter/12345.
+func FilterIDFromURL(raw string) (int, bool) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || u.Host == "" {
+		return 0, false
+	}
+	if id, err := strconv.Atoi(u.Query().Get("filter")); err == nil && id > 0 {
+		return id, true
+	}
+
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	for i := 0; i+1 < len(segments); i++ {
+		if segments[i] != "filter" {
+			continue
+		}
+		if id, err := strconv.Atoi(segments[i+1]); err == nil && id > 0 {
+			return id, true
+		}
+	}
+	return 0, false
+}
+
+// SearchByFilter fetches issues that belong to the provided Jira filter. In best-effort
+// mode the returned error may be a *PartialResultError accompanying the fetched issues.
 func (c *Client) SearchByFilter(ctx context.Context, filter *Filter) ([]Issue, error) {
 	if filter == nil {
 		return nil, errors.New("filter is required")
@@ -93,84 +466,212 @@ func (c *Client) SearchByFilter(ctx context.Context, filter *Filter) ([]Issue, e
 		return nil, fmt.Errorf("filter %q is missing a valid id", filter.Name)
 	}
 
-	details, err := c.filterByID(ctx, filter.ID)
-	if err != nil {
-		return nil, fmt.Errorf("fetch filter %d: %w", filter.ID, err)
+	// A filter from ResolveFilter already carries its searchUrl; only look it up
+	// again when the caller built the Filter without one. Some Jira versions omit
+	// searchUrl, so fall back to running the filter's JQL.
+	name, searchURL, jql := filter.Name, strings.TrimSpace(filter.SearchURL), strings.TrimSpace(filter.JQL)
+	if searchURL == "" && jql == "" {
+		details, err := c.filterByID(ctx, filter.ID)
+		if err != nil {
+			return nil, fmt.Errorf("fetch filter %d: %w", filter.ID, err)
+		}
+		name, searchURL, jql = details.Name, strings.TrimSpace(details.SearchURL), strings.TrimSpace(details.JQL)
 	}
-
-	searchURL := strings.TrimSpace(details.SearchURL)
 	if searchURL == "" {
-		return nil, fmt.Errorf("filter %q is missing searchUrl", details.Name)
+		if jql == "" {
+			return nil, fmt.Errorf("filter %q has neither a searchUrl nor JQL", name)
+		}
+		searchURL = c.jqlSearchURL(jql)
 	}
 
 	issues, err := c.fetchIssuesFromSearchURL(ctx, searchURL)
-	if err != nil {
-		return nil, err
-	}
 	if len(issues) == 0 {
-		return nil, nil
+		return nil, err
 	}
 
-	return issues, nil
+	return issues, err
 }
 
-// ListFilters fetches a set of filters accessible to the current user.
-func (c *Client) ListFilters(ctx context.Context) ([]Filter, error) {
-	const pageSize = 100
+// SearchByJQL fetches issues matching an arbitrary JQL query.
+func (c *Client) SearchByJQL(ctx context.Context, jql string) ([]Issue, error) {
+	jql = strings.TrimSpace(jql)
+	if jql == "" {
+		return nil, errors.New("jql is required")
+	}
 
-	filters := make([]Filter, 0)
-	startAt := 0
+	issues, err := c.fetchIssuesFromSearchURL(ctx, c.jqlSearchURL(jql))
+	if len(issues) == 0 {
+		return nil, err
+	}
 
-	for {
-		endpoint := c.baseURL + "/rest/api/3/filter/search"
-		req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, http.NoBody)
-		if err != nil {
-			return nil, fmt.Errorf("create filter list request: %w", err)
-		}
+	return issues, err
+}
 
-		q := req.URL.Query()
-		q.Set("startAt", strconv.Itoa(startAt))
-		q.Set("maxResults", strconv.Itoa(pageSize))
-		q.Set("expand", "jql")
-		req.URL.RawQuery = q.Encode()
+// jqlSearchURL returns the search endpoint URL that runs jql.
+func (c *Client) jqlSearchURL(jql string) string {
+	q := url.Values{}
+	q.Set("jql", strings.TrimSpace(jql))
+	if c.enhancedSearch {
+		return c.baseURL + enhancedSearchPath + "?" + q.Encode()
+	}
+	return c.baseURL + legacySearchPath + "?" + q.Encode()
+}
 
-		req.Header.Set("Authorization", c.authHeader)
-		req.Header.Set("Accept", "application/json")
+// BrowseJQLURL returns the Jira web URL that runs jql in the issue navigator.
+func (c *Client) BrowseJQLURL(jql string) string {
+	q := url.Values{}
+	q.Set("jql", strings.TrimSpace(jql))
+	return c.browseURL + "/issues/?" + q.Encode()
+}
 
-		resp, err := c.httpClient.Do(req)
-		if err != nil {
-			return nil, fmt.Errorf("filter list request: %w", err)
-		}
+// ListFilters fetches a set of filters accessible to the current user.
+func (c *Client) ListFilters(ctx context.Context) ([]Filter, error) {
+	first, err := c.filterListPage(ctx, 0)
+	if err != nil {
+		return nil, err
+	}
 
-		if resp.StatusCode != http.StatusOK {
-			body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
-			resp.Body.Close()
-			return nil, fmt.Errorf("filter list failed: %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	pages := []filterSearchResponse{first}
+	step := len(first.Values)
+	switch {
+	case first.IsLast || step == 0:
+	case first.Total > 0:
+		// The total is known, so the remaining pages can be requested at once.
+		rest, err := c.filterListPages(ctx, step, first.Total)
+		if err != nil {
+			return nil, err
 		}
-
-		var payload filterSearchResponse
-		if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
-			resp.Body.Close()
-			return nil, fmt.Errorf("decode filter list: %w", err)
+		pages = append(pages, rest...)
+	default:
+		for startAt := step; ; {
+			page, err := c.filterListPage(ctx, startAt)
+			if err != nil {
+				return nil, err
+			}
+			pages = append(pages, page)
+			if page.IsLast || len(page.Values) == 0 {
+				break
+			}
+			startAt += len(page.Values)
 		}
-		resp.Body.Close()
+	}
 
-		for _, f := range payload.Values {
+	filters := make([]Filter, 0)
+	for _, page := range pages {
+		for _, f := range page.Values {
 			if filter := toFilter(f); filter != nil {
 				filters = append(filters, *filter)
 			}
 		}
+	}
+	return filters, nil
+}
 
-		if payload.IsLast || len(payload.Values) == 0 {
-			break
-		}
+// maxFilterPageFetches bounds the filter listing pages requested concurrently.
+const maxFilterPageFetches = 4
 
-		startAt += len(payload.Values)
-		if payload.Total > 0 && len(filters) >= payload.Total {
-			break
+// filterListPages fetches the filter listing pages from step up to total, step
+// filters apart, at most maxFilterPageFetches at a time. Pages are returned in offset
+// order; the first failure cancels the others and is returned.
+func (c *Client) filterListPages(ctx context.Context, step, total int) ([]filterSearchResponse, error) {
+	var offsets []int
+	for offset := step; offset < total; offset += step {
+		offsets = append(offsets, offset)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	pages := make([]filterSearchResponse, len(offsets))
+	errs := make([]error, len(offsets))
+	slots := make(chan struct{}, maxFilterPageFetches)
+	var wg sync.WaitGroup
+	for i, offset := range offsets {
+		wg.Go(func() {
+			slots <- struct{}{}
+			defer func() { <-slots }()
+			if pages[i], errs[i] = c.filterListPage(ctx, offset); errs[i] != nil {
+				cancel()
+			}
+		})
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil && !errors.Is(err, context.Canceled) {
+			return nil, err
 		}
 	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return pages, nil
+}
+
+// filterListPage fetches one page of the filter listing.
+func (c *Client) filterListPage(ctx context.Context, startAt int) (filterSearchResponse, error) {
+	endpoint := c.baseURL + "/rest/api/3/filter/search"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, http.NoBody)
+	if err != nil {
+		return filterSearchResponse{}, fmt.Errorf("create filter list request: %w", err)
+	}
 
+	q := req.URL.Query()
+	q.Set("startAt", strconv.Itoa(startAt))
+	q.Set("maxResults", strconv.Itoa(c.pageSize))
+	q.Set("expand", "jql,owner,favourite")
+	req.URL.RawQuery = q.Encode()
+
+	resp, err := c.do(req)
+	if err != nil {
+		return filterSearchResponse{}, fmt.Errorf("filter list request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return filterSearchResponse{}, newAPIError("filter list", resp)
+	}
+
+	var payload filterSearchResponse
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return filterSearchResponse{}, fmt.Errorf("decode filter list: %w", err)
+	}
+	return payload, nil
+}
+
+// FavouriteFilters fetches the filters the current user has starred.
+func (c *Client) FavouriteFilters(ctx context.Context) ([]Filter, error) {
+	endpoint := c.baseURL + "/rest/api/3/filter/favourite"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, http.NoBody)
+	if err != nil {
+		return nil, fmt.Errorf("create favourite filters request: %w", err)
+	}
+	q := req.URL.Query()
+	q.Set("expand", "jql,owner,favourite")
+	req.URL.RawQuery = q.Encode()
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("favourite filters request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError("favourite filters", resp)
+	}
+
+	var payload []filterSummary
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return nil, fmt.Errorf("decode favourite filters: %w", err)
+	}
+
+	filters := make([]Filter, 0, len(payload))
+	for _, f := range payload {
+		if filter := toFilter(f); filter != nil {
+			// The endpoint only returns favourites, whether or not it echoes the flag.
+			filter.Favourite = true
+			filters = append(filters, *filter)
+		}
+	}
 	return filters, nil
 }
 
@@ -183,13 +684,10 @@ func (c *Client) filterByName(ctx context.Context, name string) (*Filter, error)
 	q := req.URL.Query()
 	q.Set("filterName", name)
 	q.Set("maxResults", "50")
-	q.Set("expand", "jql")
+	q.Set("expand", "jql,searchUrl,owner,favourite")
 	req.URL.RawQuery = q.Encode()
 
-	req.Header.Set("Authorization", c.authHeader)
-	req.Header.Set("Accept", "application/json")
-
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("filter search request: %w", err)
 	}
@@ -199,8 +697,7 @@ func (c *Client) filterByName(ctx context.Context, name string) (*Filter, error)
 		return nil, errFilterNotFound
 	}
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
-		return nil, fmt.Errorf("filter search failed: %s: %s", resp.Status, strings.TrimSpace(string(body)))
+		return nil, newAPIError("filter search", resp)
 	}
 
 	var payload filterSearchResponse
@@ -208,17 +705,31 @@ func (c *Client) filterByName(ctx context.Context, name string) (*Filter, error)
 		return nil, fmt.Errorf("decode filter search: %w", err)
 	}
 
+	var folded []filterSummary
 	for _, f := range payload.Values {
-		if strings.EqualFold(f.Name, name) {
+		if f.Name == name {
 			return toFilter(f), nil
 		}
+		if strings.EqualFold(f.Name, name) {
+			folded = append(folded, f)
+		}
 	}
 
-	if len(payload.Values) > 0 {
-		return toFilter(payload.Values[0]), nil
+	candidates := payload.Values
+	if len(folded) > 0 {
+		candidates = folded
 	}
-
-	return nil, errFilterNotFound
+	switch len(candidates) {
+	case 0:
+		return nil, errFilterNotFound
+	case 1:
+		return toFilter(candidates[0]), nil
+	}
+	ambiguous := &AmbiguousFilterError{Name: name}
+	for _, f := range candidates {
+		ambiguous.Candidates = append(ambiguous.Candidates, *toFilter(f))
+	}
+	return nil, ambiguous
 }
 
 func (c *Client) filterByID(ctx context.Context, id int) (*Filter, error) {
@@ -226,15 +737,20 @@ func (c *Client) filterByID(ctx context.Context, id int) (*Filter, error) {
 		return nil, errFilterNotFound
 	}
 
+	c.filterMu.Lock()
+	cached, ok := c.filterCache[id]
+	c.filterMu.Unlock()
+	if ok {
+		return &cached, nil
+	}
+
 	endpoint := fmt.Sprintf("%s/rest/api/3/filter/%d", c.baseURL, id)
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, http.NoBody)
 	if err != nil {
 		return nil, fmt.Errorf("create filter request: %w", err)
 	}
-	req.Header.Set("Authorization", c.authHeader)
-	req.Header.Set("Accept", "application/json")
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("filter request: %w", err)
 	}
@@ -244,8 +760,7 @@ func (c *Client) filterByID(ctx context.Context, id int) (*Filter, error) {
 		return nil, errFilterNotFound
 	}
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
-		return nil, fmt.Errorf("filter request failed: %s: %s", resp.Status, strings.TrimSpace(string(body)))
+		return nil, newAPIError(fmt.Sprintf("filter %d", id), resp)
 	}
 
 	bodyBytes, err := io.ReadAll(io.LimitReader(resp.Body, 4096))
@@ -260,12 +775,20 @@ func (c *Client) filterByID(ctx context.Context, id int) (*Filter, error) {
 		return nil, fmt.Errorf("decode filter: %w", err)
 	}
 
-	return toFilter(filterSummary{
+	filter := toFilter(filterSummary{
 		ID:        payload.ID,
 		Name:      payload.Name,
 		JQL:       payload.JQL,
 		SearchURL: payload.SearchURL,
-	}), nil
+		Owner:     payload.Owner,
+		Favourite: payload.Favourite,
+	})
+	if filter != nil {
+		c.filterMu.Lock()
+		c.filterCache[id] = *filter
+		c.filterMu.Unlock()
+	}
+	return filter, nil
 }
 
 type filterSearchResponse struct {
@@ -277,17 +800,25 @@ type filterSearchResponse struct {
 }
 
 type filterSummary struct {
-	ID        string `json:"id"`
-	Name      string `json:"name"`
-	JQL       string `json:"jql"`
-	SearchURL string `json:"searchUrl"`
+	ID        string      `json:"id"`
+	Name      string      `json:"name"`
+	JQL       string      `json:"jql"`
+	SearchURL string      `json:"searchUrl"`
+	Owner     filterOwner `json:"owner"`
+	Favourite bool        `json:"favourite"`
 }
 
 type filterDetailsResponse struct {
-	ID        string `json:"id"`
-	Name      string `json:"name"`
-	JQL       string `json:"jql"`
-	SearchURL string `json:"searchUrl"`
+	ID        string      `json:"id"`
+	Name      string      `json:"name"`
+	JQL       string      `json:"jql"`
+	SearchURL string      `json:"searchUrl"`
+	Owner     filterOwner `json:"owner"`
+	Favourite bool        `json:"favourite"`
+}
+
+type filterOwner struct {
+	DisplayName string `json:"displayName"`
 }
 
 func toFilter(summary filterSummary) *Filter {
@@ -300,26 +831,31 @@ func toFilter(summary filterSummary) *Filter {
 		Name:      summary.Name,
 		JQL:       summary.JQL,
 		SearchURL: summary.SearchURL,
+		Owner:     strings.TrimSpace(summary.Owner.DisplayName),
+		Favourite: summary.Favourite,
 	}
 }
 
+const (
+	legacySearchPath   = "/rest/api/3/search"
+	enhancedSearchPath = "/rest/api/3/search/jql"
+)
+
 func (c *Client) fetchIssuesFromSearchURL(ctx context.Context, searchURL string) ([]Issue, error) {
 	searchURL = strings.TrimSpace(searchURL)
 	if searchURL == "" {
 		return nil, errors.New("search url is required")
 	}
 
-	const pageSize = 100
-
 	type searchPayloadPage struct {
 		Issues []struct {
 			ID string `json:"id"`
 		} `json:"issues"`
-		StartAt    int  `json:"startAt"`
-		MaxResults int  `json:"maxResults"`
-		Total      int  `json:"total"`
-		IsLast     bool `json:"isLast"`
-		NextPage   string `json:"nextPage"`
+		StartAt       int    `json:"startAt"`
+		MaxResults    int    `json:"maxResults"`
+		Total         int    `json:"total"`
+		IsLast        bool   `json:"isLast"`
+		NextPage      string `json:"nextPage"`
 		NextPageToken string `json:"nextPageToken"`
 	}
 
@@ -333,9 +869,15 @@ func (c *Client) fetchIssuesFromSearchURL(ctx context.Context, searchURL string)
 	useNextPage := false
 	var nextPageToken string
 	if parsed, err := url.Parse(searchURL); err == nil && parsed.Scheme != "" && parsed.Host != "" {
-		searchEndpoint = fmt.Sprintf("%s://%s%s", parsed.Scheme, parsed.Host, parsed.Path)
+		path := parsed.Path
+		if c.enhancedSearch && strings.HasSuffix(strings.TrimRight(path, "/"), legacySearchPath) {
+			path = strings.TrimRight(path, "/") + "/jql"
+		}
+		searchEndpoint = fmt.Sprintf("%s://%s%s", parsed.Scheme, parsed.Host, path)
 		baseQuery = parsed.Query()
 	}
+	// The enhanced endpoint pages only by nextPageToken; startAt is not accepted.
+	tokenPaged := strings.HasSuffix(strings.TrimRight(searchEndpoint, "/"), enhancedSearchPath)
 
 	issueIDs := make([]string, 0)
 	startAt := 0
@@ -343,6 +885,9 @@ func (c *Client) fetchIssuesFromSearchURL(ctx context.Context, searchURL string)
 	var lastPageStartAt = -1
 	var lastPageCount = -1
 	var lastPageFirstID string
+	// seenTokens catches a server that hands out a nextPageToken it already returned,
+	// which would otherwise page forever.
+	seenTokens := make(map[string]bool)
 
 	for {
 		requestStartAt := startAt
@@ -360,27 +905,26 @@ func (c *Client) fetchIssuesFromSearchURL(ctx context.Context, searchURL string)
 			}
 			if nextPageToken != "" {
 				q.Set("nextPageToken", nextPageToken)
-			} else {
+			} else if !tokenPaged {
 				q.Set("startAt", strconv.Itoa(requestStartAt))
 			}
-			q.Set("maxResults", strconv.Itoa(pageSize))
-			q.Set("fields", "id")
+			q.Set("maxResults", strconv.Itoa(c.pageSize))
+			q.Set("fields", c.searchFieldList())
 			req.URL.RawQuery = q.Encode()
 		}
 
-		req.Header.Set("Authorization", c.authHeader)
-		req.Header.Set("Accept", "application/json")
-
-		resp, err := c.httpClient.Do(req)
+		resp, err := c.do(req)
 		if err != nil {
+			if errors.Is(ctx.Err(), context.Canceled) {
+				return nil, fmt.Errorf("%w while searching for issues", ErrInterrupted)
+			}
 			return nil, fmt.Errorf("execute searchUrl request: %w", err)
 		}
 
 		if resp.StatusCode != http.StatusOK {
-			body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+			apiErr := newAPIError("searchUrl", resp)
 			resp.Body.Close()
-			trimmed := strings.TrimSpace(string(body))
-			return nil, fmt.Errorf("jira api error (searchUrl): %s: %s", resp.Status, trimmed)
+			return nil, apiErr
 		}
 
 		bodyBytes, err := io.ReadAll(io.LimitReader(resp.Body, 2<<20))
@@ -397,6 +941,11 @@ func (c *Client) fetchIssuesFromSearchURL(ctx context.Context, searchURL string)
 		if len(payload.Issues) == 0 && len(payload.Results) > 0 {
 			page = payload.Results[0]
 		}
+		if c.onRawIssue != nil {
+			if err := c.passRawIssues(bodyBytes, len(payload.Issues) == 0 && len(payload.Results) > 0); err != nil {
+				return nil, err
+			}
+		}
 
 		pageFirstID := ""
 		if len(page.Issues) > 0 {
@@ -416,6 +965,14 @@ func (c *Client) fetchIssuesFromSearchURL(ctx context.Context, searchURL string)
 			}
 			issueIDs = append(issueIDs, issueID)
 		}
+		if c.maxIssues > 0 {
+			if page.Total > c.maxIssues {
+				return nil, &TooManyIssuesError{Matched: page.Total, Exact: true, Limit: c.maxIssues}
+			}
+			if len(issueIDs) > c.maxIssues {
+				return nil, &TooManyIssuesError{Matched: len(issueIDs), Limit: c.maxIssues}
+			}
+		}
 
 		if page.IsLast || len(page.Issues) == 0 {
 			break
@@ -431,8 +988,14 @@ func (c *Client) fetchIssuesFromSearchURL(ctx context.Context, searchURL string)
 				return nil, fmt.Errorf("invalid jira nextPage url: %q", nextPage)
 			}
 		} else if token := strings.TrimSpace(page.NextPageToken); token != "" {
+			if seenTokens[token] {
+				return nil, fmt.Errorf("jira search pagination did not advance (nextPageToken %q repeated)", token)
+			}
+			seenTokens[token] = true
 			nextPageToken = token
 			useNextPage = false
+		} else if tokenPaged {
+			break
 		} else {
 			nextPageToken = ""
 			useNextPage = false
@@ -447,22 +1010,69 @@ func (c *Client) fetchIssuesFromSearchURL(ctx context.Context, searchURL string)
 		lastPageFirstID = pageFirstID
 	}
 
-	if len(issueIDs) == 0 {
+	if len(issueIDs) == 0 || c.onRawIssue != nil {
 		return nil, nil
 	}
 
 	issues := make([]Issue, 0, len(issueIDs))
+	var failures []IssueFailure
 	for _, issueID := range issueIDs {
-		issue, err := c.fetchIssueDetails(ctx, issueID)
-		if err != nil {
-			return nil, fmt.Errorf("fetch issue %s: %w", issueID, err)
+		issue, journaled := Issue{}, false
+		if c.journalLookup != nil {
+			issue, journaled = c.journalLookup(issueID)
+		}
+		if !journaled {
+			fetched, err := c.fetchIssueDetailsWithin(ctx, issueID)
+			if err != nil {
+				if errors.Is(ctx.Err(), context.Canceled) {
+					return issues, fmt.Errorf("%w after fetching %d of %d issues", ErrInterrupted, len(issues), len(issueIDs))
+				}
+				if c.bestEffort && ctx.Err() == nil {
+					failures = append(failures, IssueFailure{ID: issueID, Err: err})
+					continue
+				}
+				return nil, fmt.Errorf("fetch issue %s: %w", issueID, err)
+			}
+			issue = fetched
+			if c.journalRecord != nil {
+				c.journalRecord(issueID, issue)
+			}
 		}
 		issues = append(issues, issue)
+		if c.onIssue != nil {
+			c.onIssue(issue)
+		}
 	}
 
+	if len(failures) > 0 {
+		return issues, &PartialResultError{Failures: failures}
+	}
 	return issues, nil
 }
 
+// passRawIssues hands the issues of one search response to the WithRawSearch callback
+// without decoding them. nested selects the first entry of a "results" response.
+func (c *Client) passRawIssues(body []byte, nested bool) error {
+	type rawPage struct {
+		Issues []json.RawMessage `json:"issues"`
+	}
+	var payload struct {
+		rawPage
+		Results []rawPage `json:"results"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return fmt.Errorf("decode searchUrl response: %w", err)
+	}
+	page := payload.rawPage
+	if nested {
+		page = payload.Results[0]
+	}
+	for _, issue := range page.Issues {
+		c.onRawIssue(issue)
+	}
+	return nil
+}
+
 type issueFields struct {
 	Summary string `json:"summary"`
 	Status  struct {
@@ -472,9 +1082,57 @@ type issueFields struct {
 		Name string `json:"name"`
 	} `json:"resolution"`
 	ResolutionDate string `json:"resolutiondate"`
+	Updated        string `json:"updated"`
 	Parent         struct {
-		Key string `json:"key"`
+		Key    string `json:"key"`
+		Fields struct {
+			Summary   string `json:"summary"`
+			IssueType struct {
+				Name string `json:"name"`
+			} `json:"issuetype"`
+		} `json:"fields"`
 	} `json:"parent"`
+	Comment struct {
+		Total int `json:"total"`
+	} `json:"comment"`
+	Assignee struct {
+		DisplayName string `json:"displayName"`
+	} `json:"assignee"`
+	IssueType struct {
+		Name string `json:"name"`
+	} `json:"issuetype"`
+	Priority struct {
+		Name string `json:"name"`
+	} `json:"priority"`
+	Description json.RawMessage `json:"description"`
+}
+
+// RawIssue returns the issue's JSON exactly as Jira sends it, with every field
+// (fields=*all). It is meant for discovering custom field ids.
+func (c *Client) RawIssue(ctx context.Context, key string) (json.RawMessage, error) {
+	key = strings.TrimSpace(key)
+	if key == "" {
+		return nil, errors.New("issue key is required")
+	}
+	var raw json.RawMessage
+	if err := c.getJSON(ctx, "issue "+key, "/rest/api/3/issue/"+url.PathEscape(key)+"?fields=*all", &raw); err != nil {
+		return nil, err
+	}
+	return raw, nil
+}
+
+// fetchIssueDetailsWithin is fetchIssueDetails limited to the WithIssueTimeout budget.
+func (c *Client) fetchIssueDetailsWithin(ctx context.Context, issueID string) (Issue, error) {
+	if c.issueTimeout <= 0 {
+		return c.fetchIssueDetails(ctx, issueID)
+	}
+	issueCtx, cancel := context.WithTimeout(ctx, c.issueTimeout)
+	defer cancel()
+	issue, err := c.fetchIssueDetails(issueCtx, issueID)
+	if err != nil && ctx.Err() == nil && errors.Is(issueCtx.Err(), context.DeadlineExceeded) {
+		return Issue{}, fmt.Errorf("issue took longer than %s", c.issueTimeout)
+	}
+	return issue, err
 }
 
 func (c *Client) fetchIssueDetails(ctx context.Context, issueID string) (Issue, error) {
@@ -485,49 +1143,83 @@ func (c *Client) fetchIssueDetails(ctx context.Context, issueID string) (Issue,
 	}
 
 	q := req.URL.Query()
-	q.Set("fields", "summary,status,resolution,resolutiondate,parent")
+	q.Set("fields", c.issueFieldList())
 	req.URL.RawQuery = q.Encode()
 
-	req.Header.Set("Authorization", c.authHeader)
-	req.Header.Set("Accept", "application/json")
-
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return Issue{}, fmt.Errorf("execute issue request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
-		trimmed := strings.TrimSpace(string(body))
-		return Issue{}, fmt.Errorf("jira api error (issue %s): %s: %s", issueID, resp.Status, trimmed)
+		return Issue{}, newAPIError("issue "+issueID, resp)
 	}
 
 	var payload struct {
-		Key    string      `json:"key"`
-		Fields issueFields `json:"fields"`
+		Key    string          `json:"key"`
+		Fields json.RawMessage `json:"fields"`
 	}
 	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
 		return Issue{}, fmt.Errorf("decode issue %s: %w", issueID, err)
 	}
 
-	issue := issueFromFields(payload.Key, payload.Fields)
-	if issue.Key != "" {
-		issue.URL = fmt.Sprintf("%s/browse/%s", c.baseURL, issue.Key)
+	var fields issueFields
+	if len(payload.Fields) > 0 {
+		if err := json.Unmarshal(payload.Fields, &fields); err != nil {
+			return Issue{}, fmt.Errorf("decode issue %s fields: %w", issueID, err)
+		}
+	}
+
+	issue := issueFromFields(payload.Key, fields)
+	if c.withComments {
+		issue.CommentCount = fields.Comment.Total
+	}
+	if c.withDescription {
+		issue.Description = descriptionText(fields.Description)
 	}
+	if err := c.applyCustomFields(&issue, payload.Fields); err != nil {
+		return Issue{}, fmt.Errorf("decode issue %s: %w", issueID, err)
+	}
+	issue.URL = c.issueURL(issue.Key)
+	issue.ParentURL = c.issueURL(issue.Parent)
 	return issue, nil
 }
 
+// issueURL returns the web link for an issue key, or "" when the key is empty so that
+// callers never emit a dangling /browse/ link.
+func (c *Client) issueURL(key string) string {
+	key = strings.TrimSpace(key)
+	if key == "" {
+		return ""
+	}
+	return c.browseURL + "/browse/" + url.PathEscape(key)
+}
+
 func issueFromFields(key string, fields issueFields) Issue {
 	return Issue{
-		Key:      strings.TrimSpace(key),
-		Summary:  strings.TrimSpace(fields.Summary),
-		Status:   strings.TrimSpace(fields.Status.Name),
-		Parent:   strings.TrimSpace(fields.Parent.Key),
-		Resolved: formatResolved(fields.ResolutionDate, fields.Resolution.Name),
+		Key:           strings.TrimSpace(key),
+		Summary:       strings.TrimSpace(fields.Summary),
+		Status:        strings.TrimSpace(fields.Status.Name),
+		Parent:        strings.TrimSpace(fields.Parent.Key),
+		ParentType:    strings.TrimSpace(fields.Parent.Fields.IssueType.Name),
+		ParentSummary: strings.TrimSpace(fields.Parent.Fields.Summary),
+		Resolved:      formatResolved(fields.ResolutionDate, fields.Resolution.Name),
+		Assignee:      strings.TrimSpace(fields.Assignee.DisplayName),
+		Type:          strings.TrimSpace(fields.IssueType.Name),
+		Priority:      strings.TrimSpace(fields.Priority.Name),
+		Updated:       formatUpdated(fields.Updated),
 	}
 }
 
+// formatUpdated reformats the updated timestamp, keeping values in unknown layouts as is.
+func formatUpdated(updated string) string {
+	if formatted, ok := formatTimestamp(updated); ok {
+		return formatted
+	}
+	return strings.TrimSpace(updated)
+}
+
 func debugEnabled() bool {
 	return strings.TrimSpace(os.Getenv("JIRA_DEBUG")) != ""
 }
@@ -554,23 +1246,13 @@ func logSearchPage(requestStartAt, startAt, count int, isLast bool, hasNextPageT
 	)
 }
 
+// ResolvedLayout is the time layout of Issue.Resolved when Jira reports a resolution date.
+const ResolvedLayout = "2006-01-02 15:04"
+
 func formatResolved(resolutionDate, resolutionName string) string {
 	dateValue := strings.TrimSpace(resolutionDate)
-	layouts := []string{
-		time.RFC3339Nano,
-		time.RFC3339,
-		"2006-01-02T15:04:05.000-0700",
-		"2006-01-02T15:04:05-0700",
-		"2006-01-02 15:04:05-0700",
-		"2006-01-02 15:04:05",
-	}
-
-	if dateValue != "" {
-		for _, layout := range layouts {
-			if parsed, err := time.Parse(layout, dateValue); err == nil {
-				return parsed.Format("2006-01-02 15:04")
-			}
-		}
+	if formatted, ok := formatTimestamp(dateValue); ok {
+		return formatted
 	}
 
 	if name := strings.TrimSpace(resolutionName); name != "" {
@@ -579,3 +1261,28 @@ func formatResolved(resolutionDate, resolutionName string) string {
 
 	return dateValue
 }
+
+// timestampLayouts are the layouts Jira uses for date-time fields.
+var timestampLayouts = []string{
+	time.RFC3339Nano,
+	time.RFC3339,
+	"2006-01-02T15:04:05.000-0700",
+	"2006-01-02T15:04:05-0700",
+	"2006-01-02 15:04:05-0700",
+	"2006-01-02 15:04:05",
+}
+
+// formatTimestamp reformats a Jira date-time value in ResolvedLayout, reporting false
+// when it is empty or not in a known layout.
+func formatTimestamp(value string) (string, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "", false
+	}
+	for _, layout := range timestampLayouts {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			return parsed.Format(ResolvedLayout), true
+		}
+	}
+	return "", false
+}
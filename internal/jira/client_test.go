@@ -0,0 +1,1070 @@
+package jira
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// newTestClient returns a client for srv with basic auth credentials.
+func newTestClient(t *testing.T, srv *httptest.Server, opts ...Option) *Client {
+	t.Helper()
+	client, err := NewClient(srv.URL, "user@example.com", "token", opts...)
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	return client
+}
+
+// writeJSON encodes v as the response body.
+func writeJSON(t *testing.T, w http.ResponseWriter, v any) {
+	t.Helper()
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		t.Errorf("encode response: %v", err)
+	}
+}
+
+func TestListFiltersOrdersConcurrentPages(t *testing.T) {
+	// Three pages of one filter each; the later pages answer first.
+	delays := map[int]time.Duration{0: 0, 1: 60 * time.Millisecond, 2: 0}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		startAt, _ := strconv.Atoi(r.URL.Query().Get("startAt"))
+		time.Sleep(delays[startAt])
+		writeJSON(t, w, filterSearchResponse{
+			Values:  []filterSummary{{ID: strconv.Itoa(100 + startAt), Name: fmt.Sprintf("filter %d", startAt)}},
+			StartAt: startAt,
+			Total:   3,
+			IsLast:  startAt == 2,
+		})
+	}))
+	defer srv.Close()
+
+	filters, err := newTestClient(t, srv, WithPageSize(1)).ListFilters(context.Background())
+	if err != nil {
+		t.Fatalf("ListFilters: %v", err)
+	}
+	var ids []int
+	for _, f := range filters {
+		ids = append(ids, f.ID)
+	}
+	if want := []int{100, 101, 102}; fmt.Sprint(ids) != fmt.Sprint(want) {
+		t.Errorf("filter ids = %v, want %v", ids, want)
+	}
+}
+
+func TestFilterOwnerAndFavourite(t *testing.T) {
+	var expands []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/rest/api/3/filter/search":
+			// filter/search leaves owner and favourite out unless expanded.
+			expands = append(expands, r.URL.Query().Get("expand"))
+			io.WriteString(w, `{"startAt":0,"maxResults":50,"total":2,"isLast":true,"values":[
+				{"id":"12","name":"Weekly report","jql":"project = ABC","owner":{"accountId":"5b10","displayName":" Pat Lee "},"favourite":true},
+				{"id":"3","name":"Bugs","jql":"type = Bug","owner":{"accountId":"5b11","displayName":"Sam Roe"},"favourite":false}
+			]}`)
+		case "/rest/api/3/filter/12":
+			io.WriteString(w, `{"id":"12","name":"Weekly report","jql":"project = ABC","owner":{"accountId":"5b10","displayName":"Pat Lee"},"favourite":true}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	client := newTestClient(t, srv)
+
+	filters, err := client.ListFilters(context.Background())
+	if err != nil {
+		t.Fatalf("ListFilters: %v", err)
+	}
+	type meta struct {
+		id        int
+		owner     string
+		favourite bool
+	}
+	var got []meta
+	for _, f := range filters {
+		got = append(got, meta{f.ID, f.Owner, f.Favourite})
+	}
+	if want := []meta{{12, "Pat Lee", true}, {3, "Sam Roe", false}}; !slices.Equal(got, want) {
+		t.Errorf("listed filters = %+v, want %+v", got, want)
+	}
+
+	filter, err := client.ResolveFilter(context.Background(), "12")
+	if err != nil {
+		t.Fatalf("ResolveFilter: %v", err)
+	}
+	if filter.Owner != "Pat Lee" || !filter.Favourite {
+		t.Errorf("filter 12 owner %q, favourite %v, want Pat Lee, true", filter.Owner, filter.Favourite)
+	}
+	for _, expand := range expands {
+		if !strings.Contains(expand, "owner") || !strings.Contains(expand, "favourite") {
+			t.Errorf("expand = %q, want owner and favourite", expand)
+		}
+	}
+}
+
+func TestFavouriteFilters(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/api/3/filter/favourite" {
+			http.NotFound(w, r)
+			return
+		}
+		// The favourite flag is only echoed when expanded; the second filter omits it.
+		io.WriteString(w, `[
+			{"id":"12","name":"Weekly report","jql":"project = ABC","owner":{"displayName":"Pat Lee"},"favourite":true},
+			{"id":"7","name":"weekly triage","jql":"status = New"}
+		]`)
+	}))
+	defer srv.Close()
+
+	filters, err := newTestClient(t, srv).FavouriteFilters(context.Background())
+	if err != nil {
+		t.Fatalf("FavouriteFilters: %v", err)
+	}
+	want := []Filter{
+		{ID: 12, Name: "Weekly report", JQL: "project = ABC", Owner: "Pat Lee", Favourite: true},
+		{ID: 7, Name: "weekly triage", JQL: "status = New", Favourite: true},
+	}
+	if !slices.Equal(filters, want) {
+		t.Errorf("FavouriteFilters = %+v, want %+v", filters, want)
+	}
+}
+
+func TestListFiltersFirstErrorCancelsRest(t *testing.T) {
+	var mu sync.Mutex
+	canceled := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		startAt, _ := strconv.Atoi(r.URL.Query().Get("startAt"))
+		switch startAt {
+		case 0:
+			writeJSON(t, w, filterSearchResponse{
+				Values: []filterSummary{{ID: "100", Name: "first"}},
+				Total:  4,
+			})
+		case 1:
+			http.Error(w, "boom", http.StatusInternalServerError)
+		default:
+			select {
+			case <-r.Context().Done():
+				mu.Lock()
+				canceled++
+				mu.Unlock()
+			case <-time.After(5 * time.Second):
+				t.Errorf("page at %d was not canceled", startAt)
+			}
+		}
+	}))
+	defer srv.Close()
+
+	_, err := newTestClient(t, srv, WithPageSize(1)).ListFilters(context.Background())
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusInternalServerError {
+		t.Fatalf("ListFilters error = %v, want the 500 from the failing page", err)
+	}
+	// The server notices the dropped requests shortly after the client gives up.
+	for deadline := time.Now().Add(2 * time.Second); ; time.Sleep(10 * time.Millisecond) {
+		mu.Lock()
+		n := canceled
+		mu.Unlock()
+		if n == 2 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("%d pending pages were canceled, want 2", n)
+		}
+	}
+}
+
+// searchServer is a Jira instance with a single page of search results, whose issue
+// details it answers and counts.
+type searchServer struct {
+	*httptest.Server
+	// ids are the issue ids of the search page; issue id N has the key ABC-N.
+	ids []string
+	// total is the search total reported to the client; zero reports len(ids).
+	total int
+	// filter is the body of the /rest/api/3/filter/1 response.
+	filter map[string]any
+	// issue answers a detail request in place of the default response.
+	issue func(w http.ResponseWriter, r *http.Request, id string)
+
+	mu       sync.Mutex
+	requests map[string]int
+	// searches are the jql parameters of the search requests.
+	searches []string
+	// searchFields are the fields parameters of the search requests.
+	searchFields []string
+}
+
+// newSearchServer starts s.
+func newSearchServer(t *testing.T, s *searchServer) *searchServer {
+	t.Helper()
+	s.requests = make(map[string]int)
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.requests[r.URL.Path]++
+		s.mu.Unlock()
+		switch path := r.URL.Path; {
+		case path == "/rest/api/3/filter/1" && s.filter != nil:
+			writeJSON(t, w, s.filter)
+		case path == legacySearchPath:
+			s.mu.Lock()
+			s.searches = append(s.searches, r.URL.Query().Get("jql"))
+			s.searchFields = append(s.searchFields, r.URL.Query().Get("fields"))
+			s.mu.Unlock()
+			refs := make([]map[string]string, len(s.ids))
+			for i, id := range s.ids {
+				refs[i] = map[string]string{"id": id}
+			}
+			writeJSON(t, w, map[string]any{"issues": refs, "total": cmp.Or(s.total, len(s.ids)), "isLast": true})
+		case strings.HasPrefix(path, "/rest/api/3/issue/"):
+			id := strings.TrimPrefix(path, "/rest/api/3/issue/")
+			if s.issue != nil {
+				s.issue(w, r, id)
+				return
+			}
+			writeJSON(t, w, map[string]any{"key": "ABC-" + id, "fields": map[string]any{"summary": "Issue " + id}})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// hits returns how many requests were made for path.
+func (s *searchServer) hits(path string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests[path]
+}
+
+// issueKeys returns the keys of issues.
+func issueKeys(issues []Issue) []string {
+	keys := make([]string, len(issues))
+	for i, issue := range issues {
+		keys[i] = issue.Key
+	}
+	return keys
+}
+
+func TestSearchBestEffortSkipsFailedIssues(t *testing.T) {
+	srv := newSearchServer(t, &searchServer{
+		ids: []string{"1", "2", "3"},
+		issue: func(w http.ResponseWriter, r *http.Request, id string) {
+			if id == "2" {
+				http.Error(w, "boom", http.StatusInternalServerError)
+				return
+			}
+			writeJSON(t, w, map[string]any{"key": "ABC-" + id})
+		},
+	})
+
+	issues, err := newTestClient(t, srv.Server, WithBestEffort(true)).SearchByJQL(context.Background(), "project = ABC")
+	if want := []string{"ABC-1", "ABC-3"}; !slices.Equal(issueKeys(issues), want) {
+		t.Errorf("best-effort issues = %q, want %q", issueKeys(issues), want)
+	}
+	var partial *PartialResultError
+	if !errors.As(err, &partial) || len(partial.Failures) != 1 || partial.Failures[0].ID != "2" {
+		t.Fatalf("best-effort error = %v, want a *PartialResultError for issue 2", err)
+	}
+	var apiErr *APIError
+	if !errors.As(partial.Failures[0].Err, &apiErr) || apiErr.StatusCode != http.StatusInternalServerError {
+		t.Errorf("failure = %v, want the 500", partial.Failures[0].Err)
+	}
+
+	issues, err = newTestClient(t, srv.Server).SearchByJQL(context.Background(), "project = ABC")
+	if issues != nil || err == nil || !strings.Contains(err.Error(), "fetch issue 2") {
+		t.Errorf("strict search = %q, %v; want no issues and the issue 2 failure", issueKeys(issues), err)
+	}
+}
+
+func TestSearchInterruptedKeepsFetchedIssues(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	srv := newSearchServer(t, &searchServer{
+		ids: []string{"1", "2", "3"},
+		issue: func(w http.ResponseWriter, r *http.Request, id string) {
+			if id == "2" {
+				// Ctrl-C arrives while the second issue is being fetched.
+				cancel()
+				<-r.Context().Done()
+				return
+			}
+			writeJSON(t, w, map[string]any{"key": "ABC-" + id})
+		},
+	})
+
+	issues, err := newTestClient(t, srv.Server, WithBestEffort(true)).SearchByJQL(ctx, "project = ABC")
+	if !errors.Is(err, ErrInterrupted) || !strings.Contains(err.Error(), "after fetching 1 of 3 issues") {
+		t.Errorf("error = %v, want ErrInterrupted after 1 of 3 issues", err)
+	}
+	if want := []string{"ABC-1"}; !slices.Equal(issueKeys(issues), want) {
+		t.Errorf("issues = %q, want %q", issueKeys(issues), want)
+	}
+	if n := srv.hits("/rest/api/3/issue/3"); n != 0 {
+		t.Errorf("issue 3 was requested %d times after the interrupt", n)
+	}
+}
+
+func TestIssueCallbackRunsAsIssuesArrive(t *testing.T) {
+	var mu sync.Mutex
+	var events []string
+	record := func(event string) {
+		mu.Lock()
+		defer mu.Unlock()
+		events = append(events, event)
+	}
+	srv := newSearchServer(t, &searchServer{
+		ids: []string{"1", "2", "3"},
+		issue: func(w http.ResponseWriter, r *http.Request, id string) {
+			record("fetch " + id)
+			writeJSON(t, w, map[string]any{"key": "ABC-" + id, "fields": map[string]any{}})
+		},
+	})
+
+	client := newTestClient(t, srv.Server, WithIssueCallback(func(issue Issue) { record("row " + issue.Key) }))
+	if _, err := client.SearchByJQL(context.Background(), "project = ABC"); err != nil {
+		t.Fatalf("SearchByJQL: %v", err)
+	}
+	want := []string{"fetch 1", "row ABC-1", "fetch 2", "row ABC-2", "fetch 3", "row ABC-3"}
+	if !slices.Equal(events, want) {
+		t.Errorf("events = %q, want each issue delivered before the next is fetched %q", events, want)
+	}
+}
+
+func TestIssueDetailsDecodeParent(t *testing.T) {
+	parents := map[string]any{
+		"1": map[string]any{"key": "ABC-9", "fields": map[string]any{"summary": " Launch ", "issuetype": map[string]string{"name": "Epic"}}},
+		"2": map[string]any{"key": "ABC-1", "fields": map[string]any{"summary": "Parent task", "issuetype": map[string]string{"name": "Task"}}},
+	}
+	srv := newSearchServer(t, &searchServer{
+		ids: []string{"1", "2", "3"},
+		issue: func(w http.ResponseWriter, r *http.Request, id string) {
+			fields := map[string]any{}
+			if parent, ok := parents[id]; ok {
+				fields["parent"] = parent
+			}
+			writeJSON(t, w, map[string]any{"key": "ABC-" + id, "fields": fields})
+		},
+	})
+
+	issues, err := newTestClient(t, srv.Server).SearchByJQL(context.Background(), "project = ABC")
+	if err != nil {
+		t.Fatalf("SearchByJQL: %v", err)
+	}
+	type parent struct{ key, typ, summary, url string }
+	var got []parent
+	for _, issue := range issues {
+		got = append(got, parent{issue.Parent, issue.ParentType, issue.ParentSummary, issue.ParentURL})
+	}
+	want := []parent{
+		{"ABC-9", "Epic", "Launch", srv.URL + "/browse/ABC-9"},
+		{"ABC-1", "Task", "Parent task", srv.URL + "/browse/ABC-1"},
+		{},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("parents = %+v, want %+v", got, want)
+	}
+}
+
+func TestWithCommentsDecodesTotal(t *testing.T) {
+	for _, enabled := range []bool{false, true} {
+		var fields string
+		srv := newSearchServer(t, &searchServer{
+			ids: []string{"1"},
+			issue: func(w http.ResponseWriter, r *http.Request, id string) {
+				fields = r.URL.Query().Get("fields")
+				io.WriteString(w, `{"key":"ABC-1","fields":{"summary":"Talked about","comment":{"comments":[{"id":"10"}],"maxResults":1,"total":4,"startAt":0}}}`)
+			},
+		})
+		issues, err := newTestClient(t, srv.Server, WithComments(enabled)).SearchByJQL(context.Background(), "project = ABC")
+		if err != nil || len(issues) != 1 {
+			t.Fatalf("SearchByJQL = %d issues, %v; want 1 issue", len(issues), err)
+		}
+		want := 0
+		if enabled {
+			want = 4
+		}
+		if issues[0].CommentCount != want {
+			t.Errorf("WithComments(%v): CommentCount = %d, want %d", enabled, issues[0].CommentCount, want)
+		}
+		if requested := slices.Contains(strings.Split(fields, ","), "comment"); requested != enabled {
+			t.Errorf("WithComments(%v): fields = %q", enabled, fields)
+		}
+	}
+}
+
+func TestWithExtraFieldsKeepsDefaults(t *testing.T) {
+	var detailFields string
+	srv := newSearchServer(t, &searchServer{
+		ids: []string{"1"},
+		issue: func(w http.ResponseWriter, r *http.Request, id string) {
+			detailFields = r.URL.Query().Get("fields")
+			io.WriteString(w, `{"key":"ABC-1","fields":{"summary":"Extra","customfield_10050":"Platform","customfield_10051":{"value":"High"}}}`)
+		},
+	})
+	client := newTestClient(t, srv.Server, WithExtraFields([]string{" customfield_10050 ", "summary", "", "customfield_10051"}))
+	issues, err := client.SearchByJQL(context.Background(), "project = ABC")
+	if err != nil || len(issues) != 1 {
+		t.Fatalf("SearchByJQL = %d issues, %v; want 1 issue", len(issues), err)
+	}
+
+	if want := []string{"id,customfield_10050,summary,customfield_10051"}; !slices.Equal(srv.searchFields, want) {
+		t.Errorf("search fields = %q, want %q", srv.searchFields, want)
+	}
+	wantDetail := strings.Join(append(slices.Clone(defaultIssueFields), "customfield_10050", "customfield_10051"), ",")
+	if detailFields != wantDetail {
+		t.Errorf("detail fields = %q, want %q", detailFields, wantDetail)
+	}
+	want := map[string]any{"customfield_10050": "Platform", "summary": "Extra", "customfield_10051": map[string]any{"value": "High"}}
+	if got := issues[0].Extra; fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("Extra = %v, want %v", got, want)
+	}
+}
+
+func TestResolveFilterCachesFiltersByID(t *testing.T) {
+	srv := newSearchServer(t, &searchServer{
+		filter: map[string]any{"id": "1", "name": "Weekly", "jql": "project = ABC"},
+	})
+	client := newTestClient(t, srv.Server)
+
+	for range 3 {
+		filter, err := client.ResolveFilter(context.Background(), "1")
+		if err != nil {
+			t.Fatalf("ResolveFilter: %v", err)
+		}
+		if filter.ID != 1 || filter.Name != "Weekly" || filter.JQL != "project = ABC" {
+			t.Errorf("filter = %+v, want filter 1, Weekly", filter)
+		}
+	}
+	if n := srv.hits("/rest/api/3/filter/1"); n != 1 {
+		t.Errorf("filter 1 was fetched %d times, want once", n)
+	}
+
+	// The cache is per client.
+	if _, err := newTestClient(t, srv.Server).ResolveFilter(context.Background(), "1"); err != nil {
+		t.Fatalf("ResolveFilter with a new client: %v", err)
+	}
+	if n := srv.hits("/rest/api/3/filter/1"); n != 2 {
+		t.Errorf("filter 1 was fetched %d times by two clients, want twice", n)
+	}
+}
+
+func TestSearchByFilterReusesResolvedFilter(t *testing.T) {
+	srv := &searchServer{ids: []string{"1"}}
+	srv.filter = map[string]any{"id": "1", "name": "Weekly", "jql": "project = ABC"}
+	newSearchServer(t, srv)
+	srv.filter["searchUrl"] = srv.URL + legacySearchPath + "?jql=project%20%3D%20ABC"
+
+	for _, tc := range []struct {
+		name        string
+		filter      func(*Client) *Filter
+		wantFetches int
+	}{
+		{"resolved filter", func(c *Client) *Filter {
+			filter, err := c.ResolveFilter(context.Background(), "1")
+			if err != nil {
+				t.Fatalf("ResolveFilter: %v", err)
+			}
+			return filter
+		}, 1},
+		{"bare filter id", func(*Client) *Filter { return &Filter{ID: 1} }, 1},
+		{"filter with jql", func(*Client) *Filter { return &Filter{ID: 1, JQL: "project = ABC"} }, 0},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			before := srv.hits("/rest/api/3/filter/1")
+			client := newTestClient(t, srv.Server)
+			issues, err := client.SearchByFilter(context.Background(), tc.filter(client))
+			if err != nil || len(issues) != 1 {
+				t.Fatalf("SearchByFilter = %d issues, %v; want 1 issue", len(issues), err)
+			}
+			if n := srv.hits("/rest/api/3/filter/1") - before; n != tc.wantFetches {
+				t.Errorf("filter 1 was fetched %d times, want %d", n, tc.wantFetches)
+			}
+		})
+	}
+}
+
+func TestIssueURL(t *testing.T) {
+	client, err := NewClient("https://jira.example.com/", "user@example.com", "token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	browse, err := NewClient("https://api.example.com", "user@example.com", "token", WithBrowseURL(" https://jira.example.com/ "))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		client *Client
+		key    string
+		want   string
+	}{
+		{client, "ABC-1", "https://jira.example.com/browse/ABC-1"},
+		{client, " ABC-1 ", "https://jira.example.com/browse/ABC-1"},
+		{client, "", ""},
+		{client, "  ", ""},
+		{client, "ABC 1/2?", "https://jira.example.com/browse/ABC%201%2F2%3F"},
+		{browse, "ABC-1", "https://jira.example.com/browse/ABC-1"},
+	} {
+		if got := tc.client.issueURL(tc.key); got != tc.want {
+			t.Errorf("issueURL(%q) = %q, want %q", tc.key, got, tc.want)
+		}
+	}
+}
+
+func TestIssueDetailsWithoutKeyHaveNoLinks(t *testing.T) {
+	srv := newSearchServer(t, &searchServer{
+		ids: []string{"1"},
+		issue: func(w http.ResponseWriter, r *http.Request, id string) {
+			writeJSON(t, w, map[string]any{"fields": map[string]any{"summary": "No key"}})
+		},
+	})
+	issues, err := newTestClient(t, srv.Server).SearchByJQL(context.Background(), "project = ABC")
+	if err != nil || len(issues) != 1 {
+		t.Fatalf("SearchByJQL = %v, %v", issues, err)
+	}
+	if issues[0].URL != "" || issues[0].ParentURL != "" {
+		t.Errorf("links = %q, %q; want none", issues[0].URL, issues[0].ParentURL)
+	}
+}
+
+func TestWithPageSize(t *testing.T) {
+	for _, tc := range []struct {
+		size int
+		want string
+	}{
+		{0, "100"},
+		{25, "25"},
+		{101, "100"},
+		{-1, "100"},
+	} {
+		var mu sync.Mutex
+		var got []string
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			got = append(got, r.URL.Path+" "+r.URL.Query().Get("maxResults"))
+			mu.Unlock()
+			writeJSON(t, w, map[string]any{"values": []any{}, "issues": []any{}, "isLast": true})
+		}))
+		client := newTestClient(t, srv, WithPageSize(tc.size))
+		if _, err := client.ListFilters(context.Background()); err != nil {
+			t.Fatalf("ListFilters: %v", err)
+		}
+		if _, err := client.SearchByJQL(context.Background(), "project = ABC"); err != nil {
+			t.Fatalf("SearchByJQL: %v", err)
+		}
+		srv.Close()
+		want := []string{"/rest/api/3/filter/search " + tc.want, legacySearchPath + " " + tc.want}
+		if !slices.Equal(got, want) {
+			t.Errorf("WithPageSize(%d) requests = %q, want %q", tc.size, got, want)
+		}
+	}
+}
+
+func TestMaxIssues(t *testing.T) {
+	srv := newSearchServer(t, &searchServer{ids: []string{"1", "2", "3"}})
+	for _, tc := range []struct {
+		limit   int
+		wantErr string
+	}{
+		{0, ""},
+		{3, ""},
+		{2, "search matched 3 issues, exceeding the limit of 2"},
+	} {
+		before := srv.hits("/rest/api/3/issue/1")
+		issues, err := newTestClient(t, srv.Server, WithMaxIssues(tc.limit)).SearchByJQL(context.Background(), "project = ABC")
+		if tc.wantErr == "" {
+			if err != nil || len(issues) != 3 {
+				t.Errorf("WithMaxIssues(%d) = %d issues, %v; want all 3", tc.limit, len(issues), err)
+			}
+			continue
+		}
+		var tooMany *TooManyIssuesError
+		if !errors.As(err, &tooMany) || !tooMany.Exact || tooMany.Matched != 3 || err.Error() != tc.wantErr {
+			t.Errorf("WithMaxIssues(%d) error = %v, want %q", tc.limit, err, tc.wantErr)
+		}
+		if n := srv.hits("/rest/api/3/issue/1") - before; n != 0 {
+			t.Errorf("WithMaxIssues(%d) fetched details %d times past the cap", tc.limit, n)
+		}
+	}
+}
+
+func TestMaxIssuesWithoutTotal(t *testing.T) {
+	// Pages of two issues with no total, so the cap is noticed only once it is passed.
+	pages := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pages++
+		startAt, _ := strconv.Atoi(r.URL.Query().Get("startAt"))
+		writeJSON(t, w, map[string]any{
+			"startAt": startAt,
+			"issues":  []map[string]string{{"id": strconv.Itoa(startAt + 1)}, {"id": strconv.Itoa(startAt + 2)}},
+		})
+	}))
+	defer srv.Close()
+
+	_, err := newTestClient(t, srv, WithMaxIssues(3)).SearchByJQL(context.Background(), "project = ABC")
+	var tooMany *TooManyIssuesError
+	if !errors.As(err, &tooMany) || tooMany.Exact || tooMany.Matched != 4 {
+		t.Fatalf("error = %v, want an inexact *TooManyIssuesError after 4 matches", err)
+	}
+	if want := "search matched more than 3 issues, exceeding the limit of 3"; err.Error() != want {
+		t.Errorf("error = %q, want %q", err, want)
+	}
+	if pages != 2 {
+		t.Errorf("requested %d pages, want 2", pages)
+	}
+}
+
+// filterNameServer answers filter name searches with names, numbering them from 100.
+func filterNameServer(t *testing.T, names ...string) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/rest/api/3/filter/search":
+			values := make([]filterSummary, len(names))
+			for i, name := range names {
+				values[i] = filterSummary{ID: strconv.Itoa(100 + i), Name: name}
+			}
+			writeJSON(t, w, filterSearchResponse{Values: values, IsLast: true})
+		case "/rest/api/3/filter/7":
+			writeJSON(t, w, filterDetailsResponse{ID: "7", Name: "Filter seven"})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestResolveFilterByName(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		names      []string
+		identifier string
+		wantID     int
+		wantErr    string
+	}{
+		{"exact match among several", []string{"Weekly", "weekly", "Weekly (old)"}, "weekly", 101, ""},
+		{"single case-insensitive match", []string{"Weekly", "Weekly (old)"}, "WEEKLY", 100, ""},
+		{"single partial match", []string{"Weekly report"}, "Weekly", 100, ""},
+		{
+			"case-insensitive collision", []string{"Weekly", "WEEKLY", "Weekly (old)"}, "weekly", 0,
+			`filter name "weekly" matches 2 filters: "Weekly" (100), "WEEKLY" (101); use the numeric id to choose one`,
+		},
+		{
+			"several partial matches", []string{"Weekly A", "Weekly B"}, "Weekly", 0,
+			`filter name "Weekly" matches 2 filters: "Weekly A" (100), "Weekly B" (101); use the numeric id to choose one`,
+		},
+		{"numeric identifier matching several names", []string{"Sprint 7", "Release 7"}, "7", 7, ""},
+		{"no match", nil, "Weekly", 0, `filter "Weekly" not found`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := filterNameServer(t, tc.names...)
+			filter, err := newTestClient(t, srv).ResolveFilter(context.Background(), tc.identifier)
+			if tc.wantErr != "" {
+				if err == nil || err.Error() != tc.wantErr {
+					t.Errorf("ResolveFilter error = %v, want %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ResolveFilter: %v", err)
+			}
+			if filter.ID != tc.wantID {
+				t.Errorf("ResolveFilter = filter %d (%q), want %d", filter.ID, filter.Name, tc.wantID)
+			}
+		})
+	}
+}
+
+func TestAmbiguousFilterErrorCandidates(t *testing.T) {
+	srv := filterNameServer(t, "Weekly", "WEEKLY")
+	_, err := newTestClient(t, srv).ResolveFilter(context.Background(), "weekly")
+	var ambiguous *AmbiguousFilterError
+	if !errors.As(err, &ambiguous) {
+		t.Fatalf("ResolveFilter error = %v, want an *AmbiguousFilterError", err)
+	}
+	var ids []int
+	for _, f := range ambiguous.Candidates {
+		ids = append(ids, f.ID)
+	}
+	if ambiguous.Name != "weekly" || !slices.Equal(ids, []int{100, 101}) {
+		t.Errorf("ambiguous = %q with candidates %v, want weekly with 100, 101", ambiguous.Name, ids)
+	}
+}
+
+func TestFilterIDFromURL(t *testing.T) {
+	for _, tc := range []struct {
+		url    string
+		want   int
+		wantOK bool
+	}{
+		{"https://x.atlassian.net/issues/?filter=12345", 12345, true},
+		{"https://x.atlassian.net/issues/?jql=project%3DABC&filter=42", 42, true},
+		{"https://x.atlassian.net/secure/IssueNavigator.jspa?mode=hide&requestId=10", 0, false},
+		{"https://x.atlassian.net/rest/api/3/filter/777", 777, true},
+		{"https://x.atlassian.net/jira/filter/9/", 9, true},
+		{"  https://x.atlassian.net/issues/?filter=5  ", 5, true},
+		{"https://x.atlassian.net/issues/?filter=abc", 0, false},
+		{"https://x.atlassian.net/issues/?filter=-3", 0, false},
+		{"https://x.atlassian.net/filter/", 0, false},
+		{"https://x.atlassian.net/filters", 0, false},
+		{"/issues/?filter=12", 0, false},
+		{"://bad", 0, false},
+	} {
+		got, ok := FilterIDFromURL(tc.url)
+		if got != tc.want || ok != tc.wantOK {
+			t.Errorf("FilterIDFromURL(%q) = %d, %v; want %d, %v", tc.url, got, ok, tc.want, tc.wantOK)
+		}
+	}
+}
+
+func TestResolveFilterByURL(t *testing.T) {
+	srv := filterNameServer(t)
+	client := newTestClient(t, srv)
+	filter, err := client.ResolveFilter(context.Background(), srv.URL+"/issues/?filter=7")
+	if err != nil || filter.ID != 7 {
+		t.Fatalf("ResolveFilter(url) = %+v, %v; want filter 7", filter, err)
+	}
+	if _, err := client.ResolveFilter(context.Background(), srv.URL+"/issues/?jql=project"); err == nil || !strings.Contains(err.Error(), "no filter id in URL") {
+		t.Errorf("ResolveFilter(url without a filter) error = %v", err)
+	}
+}
+
+func TestIssueTimeoutSkipsSlowIssues(t *testing.T) {
+	srv := newSearchServer(t, &searchServer{
+		ids: []string{"1", "2", "3"},
+		issue: func(w http.ResponseWriter, r *http.Request, id string) {
+			if id == "2" {
+				select {
+				case <-r.Context().Done():
+				case <-time.After(5 * time.Second):
+					t.Error("slow issue was not abandoned")
+				}
+				return
+			}
+			writeJSON(t, w, map[string]any{"key": "ABC-" + id})
+		},
+	})
+
+	client := newTestClient(t, srv.Server, WithBestEffort(true), WithIssueTimeout(50*time.Millisecond))
+	issues, err := client.SearchByJQL(context.Background(), "project = ABC")
+	if want := []string{"ABC-1", "ABC-3"}; !slices.Equal(issueKeys(issues), want) {
+		t.Errorf("issues = %q, want %q", issueKeys(issues), want)
+	}
+	var partial *PartialResultError
+	if !errors.As(err, &partial) || len(partial.Failures) != 1 || partial.Failures[0].ID != "2" {
+		t.Fatalf("error = %v, want issue 2 skipped", err)
+	}
+	if got := partial.Failures[0].Err.Error(); got != "issue took longer than 50ms" {
+		t.Errorf("failure = %q, want the timeout named", got)
+	}
+}
+
+func TestSearchByFilterFallsBackToJQL(t *testing.T) {
+	srv := newSearchServer(t, &searchServer{
+		ids:    []string{"1"},
+		filter: map[string]any{"id": "1", "name": "Weekly", "jql": " project = ABC ORDER BY key ", "searchUrl": ""},
+	})
+	client := newTestClient(t, srv.Server)
+	filter, err := client.ResolveFilter(context.Background(), "1")
+	if err != nil {
+		t.Fatalf("ResolveFilter: %v", err)
+	}
+	issues, err := client.SearchByFilter(context.Background(), filter)
+	if err != nil || len(issues) != 1 {
+		t.Fatalf("SearchByFilter = %d issues, %v; want 1 issue", len(issues), err)
+	}
+	if want := []string{"project = ABC ORDER BY key"}; !slices.Equal(srv.searches, want) {
+		t.Errorf("searched %q, want %q", srv.searches, want)
+	}
+
+	_, err = client.SearchByFilter(context.Background(), &Filter{ID: 2, Name: "Empty", SearchURL: " ", JQL: " "})
+	if err == nil || !strings.Contains(err.Error(), "fetch filter 2") {
+		t.Errorf("SearchByFilter without searchUrl or JQL error = %v, want the filter fetched", err)
+	}
+}
+
+func TestSearchByFilterWithoutSearchURLOrJQL(t *testing.T) {
+	srv := newSearchServer(t, &searchServer{
+		filter: map[string]any{"id": "1", "name": "Broken"},
+	})
+	_, err := newTestClient(t, srv.Server).SearchByFilter(context.Background(), &Filter{ID: 1})
+	if err == nil || err.Error() != `filter "Broken" has neither a searchUrl nor JQL` {
+		t.Errorf("SearchByFilter error = %v", err)
+	}
+}
+
+func TestMissingScopeFromChallenge(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		status    int
+		challenge string
+		body      string
+		want      string
+	}{
+		{"403 insufficient scope", http.StatusForbidden, `Bearer realm="jira", error="insufficient_scope", scope="read:jira-work"`, "", "read:jira-work"},
+		{"401 insufficient scope", http.StatusUnauthorized, `Bearer error="insufficient_scope", scope="read:jira-user"`, "", "read:jira-user"},
+		{"403 without a scope parameter", http.StatusForbidden, `Bearer error="insufficient_scope"`, "", readOnlyScope},
+		{"403 scope mismatch body", http.StatusForbidden, "", `{"code":401,"message":"Unauthorized; scope does not match"}`, readOnlyScope},
+		{"401 bad token", http.StatusUnauthorized, `Bearer realm="jira", error="invalid_token"`, "", ""},
+		{"403 permission", http.StatusForbidden, "", `{"errorMessages":["You do not have permission"]}`, ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tc.challenge != "" {
+					w.Header().Set("WWW-Authenticate", tc.challenge)
+				}
+				http.Error(w, tc.body, tc.status)
+			}))
+			defer srv.Close()
+
+			_, err := newTestClient(t, srv).CurrentUser(context.Background())
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) || apiErr.StatusCode != tc.status {
+				t.Fatalf("CurrentUser error = %v, want a %d *APIError", err, tc.status)
+			}
+			if apiErr.MissingScope != tc.want {
+				t.Errorf("MissingScope = %q, want %q", apiErr.MissingScope, tc.want)
+			}
+			if hint := "missing the " + tc.want + " scope"; tc.want != "" && !strings.Contains(err.Error(), hint) {
+				t.Errorf("error %q does not mention %q", err, hint)
+			}
+		})
+	}
+}
+
+func TestEnhancedSearchPagesByToken(t *testing.T) {
+	// Each token names the page it fetches; a repeat token sends the client back.
+	pages := map[string]map[string]any{
+		"":   {"issues": []map[string]string{{"id": "1"}, {"id": "2"}}, "nextPageToken": "p2"},
+		"p2": {"issues": []map[string]string{{"id": "3"}, {"id": "4"}}, "nextPageToken": "p3"},
+		"p3": {"issues": []map[string]string{{"id": "5"}}, "isLast": true},
+	}
+	for _, tc := range []struct {
+		name     string
+		last     map[string]any
+		want     []string
+		wantErr  string
+		wantPage []string
+	}{
+		{name: "last page", want: []string{"ABC-1", "ABC-2", "ABC-3", "ABC-4", "ABC-5"}, wantPage: []string{"", "p2", "p3"}},
+		{
+			name:     "repeated token",
+			last:     map[string]any{"issues": []map[string]string{{"id": "5"}}, "nextPageToken": "p2"},
+			wantErr:  `nextPageToken "p2" repeated`,
+			wantPage: []string{"", "p2", "p3"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var mu sync.Mutex
+			var tokens []string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case r.URL.Path == "/rest/api/3/search/jql":
+					q := r.URL.Query()
+					if q.Has("startAt") {
+						t.Errorf("enhanced search sent startAt=%q", q.Get("startAt"))
+					}
+					token := q.Get("nextPageToken")
+					mu.Lock()
+					tokens = append(tokens, token)
+					mu.Unlock()
+					page := pages[token]
+					if token == "p3" && tc.last != nil {
+						page = tc.last
+					}
+					writeJSON(t, w, page)
+				case strings.HasPrefix(r.URL.Path, "/rest/api/3/issue/"):
+					id := strings.TrimPrefix(r.URL.Path, "/rest/api/3/issue/")
+					writeJSON(t, w, map[string]any{"id": id, "key": "ABC-" + id, "fields": map[string]any{"summary": "Issue " + id}})
+				default:
+					http.NotFound(w, r)
+				}
+			}))
+			defer srv.Close()
+
+			issues, err := newTestClient(t, srv, WithEnhancedSearch(true)).SearchByJQL(context.Background(), "project = ABC")
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("SearchByJQL error = %v, want one containing %q", err, tc.wantErr)
+				}
+			} else if err != nil {
+				t.Fatalf("SearchByJQL: %v", err)
+			}
+			if !slices.Equal(issueKeys(issues), tc.want) {
+				t.Errorf("issues = %q, want %q", issueKeys(issues), tc.want)
+			}
+			if !slices.Equal(tokens, tc.wantPage) {
+				t.Errorf("requested tokens %q, want %q", tokens, tc.wantPage)
+			}
+		})
+	}
+}
+
+func TestStatsCountsRequestsAndBytes(t *testing.T) {
+	const body = `{"accountId":"abc","displayName":"Ada"}`
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, body)
+	}))
+	defer srv.Close()
+
+	client := newTestClient(t, srv)
+	if stats := client.Stats(); stats.Requests != 0 || stats.Bytes != 0 {
+		t.Fatalf("new client stats = %+v, want none", stats)
+	}
+	for i := 1; i <= 2; i++ {
+		if _, err := client.CurrentUser(context.Background()); err != nil {
+			t.Fatalf("CurrentUser: %v", err)
+		}
+		stats := client.Stats()
+		if stats.Requests != int64(i) || stats.Bytes != int64(i*len(body)) {
+			t.Errorf("after %d calls stats = %d requests, %d bytes; want %d, %d", i, stats.Requests, stats.Bytes, i, i*len(body))
+		}
+	}
+	if client.Stats().Duration <= 0 {
+		t.Error("stats recorded no time")
+	}
+}
+
+// headerServer serves /rest/api/3/myself, refusing the first request with a 401 so an
+// OAuth client refreshes at /oauth/token, and records header for every request by path.
+func headerServer(t *testing.T, header string) (*httptest.Server, func() map[string][]string) {
+	t.Helper()
+	var mu sync.Mutex
+	seen := make(map[string][]string)
+	rejected := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		seen[r.URL.Path] = append(seen[r.URL.Path], r.Header.Get(header))
+		reject := !rejected && r.Header.Get("Authorization") == "Bearer stale-token"
+		rejected = rejected || reject
+		mu.Unlock()
+		switch {
+		case r.URL.Path == "/oauth/token":
+			writeJSON(t, w, map[string]string{"access_token": "fresh-token"})
+		case reject:
+			http.Error(w, `{"message":"token expired"}`, http.StatusUnauthorized)
+		default:
+			writeJSON(t, w, map[string]string{"accountId": "abc"})
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv, func() map[string][]string {
+		mu.Lock()
+		defer mu.Unlock()
+		return maps.Clone(seen)
+	}
+}
+
+func TestUserAgentOnEveryRequest(t *testing.T) {
+	srv, seen := headerServer(t, "User-Agent")
+	client := newTestClient(t, srv, WithUserAgent(" wkreport/1.2 (ops) "))
+	for range 2 {
+		if _, err := client.CurrentUser(context.Background()); err != nil {
+			t.Fatalf("CurrentUser: %v", err)
+		}
+	}
+	if got, want := seen()["/rest/api/3/myself"], []string{"wkreport/1.2 (ops)", "wkreport/1.2 (ops)"}; !slices.Equal(got, want) {
+		t.Errorf("basic auth User-Agent = %q, want %q", got, want)
+	}
+
+	srv, seen = headerServer(t, "User-Agent")
+	oauth, err := NewOAuthClient(srv.URL, OAuthCredentials{
+		AccessToken:  "stale-token",
+		RefreshToken: "refresh-1",
+		ClientID:     "client",
+		ClientSecret: "secret",
+		TokenURL:     srv.URL + "/oauth/token",
+	}, WithUserAgent("wkreport/1.2"))
+	if err != nil {
+		t.Fatalf("NewOAuthClient: %v", err)
+	}
+	if _, err := oauth.CurrentUser(context.Background()); err != nil {
+		t.Fatalf("CurrentUser: %v", err)
+	}
+	got := seen()
+	if want := []string{"wkreport/1.2", "wkreport/1.2"}; !slices.Equal(got["/rest/api/3/myself"], want) {
+		t.Errorf("OAuth User-Agent = %q, want %q for the rejected request and its retry", got["/rest/api/3/myself"], want)
+	}
+	if want := []string{"wkreport/1.2"}; !slices.Equal(got["/oauth/token"], want) {
+		t.Errorf("refresh User-Agent = %q, want %q", got["/oauth/token"], want)
+	}
+
+	srv, seen = headerServer(t, "User-Agent")
+	if _, err := newTestClient(t, srv, WithUserAgent("  ")).CurrentUser(context.Background()); err != nil {
+		t.Fatalf("CurrentUser: %v", err)
+	}
+	if got, want := seen()["/rest/api/3/myself"], []string{DefaultUserAgent}; !slices.Equal(got, want) {
+		t.Errorf("blank User-Agent sent %q, want %q", got, want)
+	}
+}
+
+func TestAtlassianTokenNoCheck(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		opts []Option
+		want string
+	}{
+		{"default", nil, ""},
+		{"disabled", []Option{WithAtlassianTokenNoCheck(false)}, ""},
+		{"enabled", []Option{WithAtlassianTokenNoCheck(true)}, "no-check"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			srv, seen := headerServer(t, "X-Atlassian-Token")
+			client := newTestClient(t, srv, tc.opts...)
+			for range 2 {
+				if _, err := client.CurrentUser(context.Background()); err != nil {
+					t.Fatalf("CurrentUser: %v", err)
+				}
+			}
+			if got, want := seen()["/rest/api/3/myself"], []string{tc.want, tc.want}; !slices.Equal(got, want) {
+				t.Errorf("X-Atlassian-Token = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestCurrentUser(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/api/3/myself" {
+			http.NotFound(w, r)
+			return
+		}
+		// A trimmed sample of the Jira Cloud myself response.
+		io.WriteString(w, `{
+			"self": "https://example.atlassian.net/rest/api/3/user?accountId=5b10ac8d82e05b22cc7d4ef5",
+			"accountId": "5b10ac8d82e05b22cc7d4ef5",
+			"accountType": "atlassian",
+			"emailAddress": " pat@example.com ",
+			"displayName": "Pat Lee ",
+			"active": true,
+			"timeZone": "Europe/London",
+			"groups": {"size": 3, "items": []}
+		}`)
+	}))
+	t.Cleanup(srv.Close)
+
+	user, err := newTestClient(t, srv).CurrentUser(context.Background())
+	if err != nil {
+		t.Fatalf("CurrentUser: %v", err)
+	}
+	if want := (User{AccountID: "5b10ac8d82e05b22cc7d4ef5", DisplayName: "Pat Lee", Email: "pat@example.com"}); user != want {
+		t.Errorf("CurrentUser = %+v, want %+v", user, want)
+	}
+}
@@ -0,0 +1,87 @@
+package jira
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// readOnlyScope is the OAuth scope wkreport needs; it never writes to Jira.
+const readOnlyScope = "read:jira-work"
+
+// APIError describes a non-success response from the Jira REST API.
+type APIError struct {
+	// Op names the request that failed, such as "myself" or "issue 10001".
+	Op         string
+	StatusCode int
+	Status     string
+	Body       string
+	// MissingScope is the OAuth scope Jira reported as missing on a 401 or 403, if any.
+	MissingScope string
+}
+
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("jira api error (%s): %s", e.Op, e.Status)
+	if e.Body != "" {
+		msg += ": " + e.Body
+	}
+	if hint := e.Hint(); hint != "" {
+		msg += " (" + hint + ")"
+	}
+	return msg
+}
+
+// Hint returns an actionable explanation for errors with a known cause.
+func (e *APIError) Hint() string {
+	if e.MissingScope != "" {
+		return fmt.Sprintf("your token is missing the %s scope", e.MissingScope)
+	}
+	return ""
+}
+
+// newAPIError reads a bounded excerpt of resp's body into an *APIError. On a 401 or
+// 403 it also looks for the missing OAuth scope in WWW-Authenticate and the body.
+func newAPIError(op string, resp *http.Response) *APIError {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	apiErr := &APIError{
+		Op:         op,
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		Body:       strings.TrimSpace(string(body)),
+	}
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		apiErr.MissingScope = missingScope(resp.Header.Get("WWW-Authenticate"), apiErr.Body)
+	}
+	return apiErr
+}
+
+// missingScope extracts the scope from an insufficient_scope challenge such as
+// `Bearer error="insufficient_scope", scope="read:jira-work"`. Jira Cloud sometimes
+// only says "scope does not match" in the body, which means the read scope is absent.
+func missingScope(challenge, body string) string {
+	params := authParams(challenge)
+	if scope := params["scope"]; scope != "" && (params["error"] == "" || params["error"] == "insufficient_scope") {
+		return scope
+	}
+	if params["error"] == "insufficient_scope" || strings.Contains(strings.ToLower(body), "scope does not match") {
+		return readOnlyScope
+	}
+	return ""
+}
+
+// authParams parses the key="value" parameters of a WWW-Authenticate challenge.
+func authParams(challenge string) map[string]string {
+	params := make(map[string]string)
+	if i := strings.IndexByte(challenge, ' '); i >= 0 && !strings.Contains(challenge[:i], "=") {
+		challenge = challenge[i+1:]
+	}
+	for _, part := range strings.Split(challenge, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+		params[strings.ToLower(strings.TrimSpace(key))] = strings.Trim(strings.TrimSpace(value), `"`)
+	}
+	return params
+}
@@ -0,0 +1,169 @@
+package jira
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// defaultIssueFields lists the fields every issue detail request asks for.
+var defaultIssueFields = []string{"summary", "status", "resolution", "resolutiondate", "parent", "assignee", "updated", "issuetype", "priority"}
+
+// IssueFields returns the fields the client requests for each issue: the defaults plus
+// the configured custom fields and those of the opt-in options. Fields outside this
+// list are never decoded.
+func (c *Client) IssueFields() []string {
+	fields := append([]string(nil), defaultIssueFields...)
+	if c.sprintField != "" {
+		fields = append(fields, c.sprintField)
+	}
+	if c.storyPointsField != "" {
+		fields = append(fields, c.storyPointsField)
+	}
+	if c.withComments {
+		fields = append(fields, "comment")
+	}
+	if c.withDescription {
+		fields = append(fields, "description")
+	}
+	return appendFields(fields, c.extraFields)
+}
+
+// issueFieldList returns the comma-separated fields parameter for issue detail requests.
+func (c *Client) issueFieldList() string {
+	return strings.Join(c.IssueFields(), ",")
+}
+
+// searchFieldList returns the fields parameter for search requests, which only need
+// issue ids plus any extra fields. Raw searches are not followed by detail requests, so
+// they ask for the detail fields instead.
+func (c *Client) searchFieldList() string {
+	if c.onRawIssue != nil {
+		return c.issueFieldList()
+	}
+	return strings.Join(appendFields([]string{"id"}, c.extraFields), ",")
+}
+
+// appendFields adds extra to fields, skipping any already present.
+func appendFields(fields, extra []string) []string {
+	seen := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		seen[field] = true
+	}
+	for _, field := range extra {
+		if !seen[field] {
+			seen[field] = true
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// applyCustomFields decodes the configured custom fields from the raw fields object.
+func (c *Client) applyCustomFields(issue *Issue, rawFields json.RawMessage) error {
+	if (c.sprintField == "" && c.storyPointsField == "" && len(c.extraFields) == 0) || len(rawFields) == 0 {
+		return nil
+	}
+
+	var custom map[string]json.RawMessage
+	if err := json.Unmarshal(rawFields, &custom); err != nil {
+		return fmt.Errorf("decode custom fields: %w", err)
+	}
+
+	if raw, ok := custom[c.sprintField]; ok {
+		issue.Sprint = activeSprintName(raw)
+	}
+	if raw, ok := custom[c.storyPointsField]; ok {
+		issue.StoryPoints = storyPoints(raw)
+	}
+	for _, field := range c.extraFields {
+		raw, ok := custom[field]
+		if !ok {
+			continue
+		}
+		var value any
+		if err := json.Unmarshal(raw, &value); err != nil {
+			return fmt.Errorf("decode field %s: %w", field, err)
+		}
+		if issue.Extra == nil {
+			issue.Extra = make(map[string]any)
+		}
+		issue.Extra[field] = value
+	}
+	return nil
+}
+
+// storyPoints decodes a numeric story points value, returning nil when it is unset.
+func storyPoints(raw json.RawMessage) *float64 {
+	var points *float64
+	if err := json.Unmarshal(raw, &points); err != nil {
+		return nil
+	}
+	return points
+}
+
+// activeSprintName returns the name of the active sprint in a sprint custom field value.
+// Jira returns either structured sprint objects or, on older instances, strings like
+// "com.atlassian.greenhopper.service.sprint.Sprint@1f[id=5,state=ACTIVE,name=Sprint 5,...]".
+func activeSprintName(raw json.RawMessage) string {
+	var structured []struct {
+		Name  string `json:"name"`
+		State string `json:"state"`
+	}
+	if err := json.Unmarshal(raw, &structured); err == nil {
+		for _, sprint := range structured {
+			if strings.EqualFold(strings.TrimSpace(sprint.State), "active") {
+				return strings.TrimSpace(sprint.Name)
+			}
+		}
+		return ""
+	}
+
+	var blobs []string
+	if err := json.Unmarshal(raw, &blobs); err != nil {
+		var single string
+		if err := json.Unmarshal(raw, &single); err != nil {
+			return ""
+		}
+		blobs = []string{single}
+	}
+
+	for _, blob := range blobs {
+		attrs := parseSprintBlob(blob)
+		if strings.EqualFold(attrs["state"], "active") {
+			return attrs["name"]
+		}
+	}
+	return ""
+}
+
+var sprintAttrPattern = regexp.MustCompile(`(?:^|,)(\w+)=`)
+
+// parseSprintBlob extracts the key=value attributes from a legacy sprint string. Values
+// may contain commas (sprint names often do), so attributes are split on ",key=" only.
+func parseSprintBlob(blob string) map[string]string {
+	attrs := make(map[string]string)
+
+	start := strings.Index(blob, "[")
+	end := strings.LastIndex(blob, "]")
+	if start < 0 || end <= start {
+		return attrs
+	}
+	body := blob[start+1 : end]
+
+	matches := sprintAttrPattern.FindAllStringSubmatchIndex(body, -1)
+	for i, m := range matches {
+		valueEnd := len(body)
+		if i+1 < len(matches) {
+			valueEnd = matches[i+1][0]
+		}
+		key := strings.ToLower(body[m[2]:m[3]])
+		value := strings.TrimSpace(body[m[1]:valueEnd])
+		if value == "<null>" {
+			value = ""
+		}
+		attrs[key] = value
+	}
+	return attrs
+}
@@ -0,0 +1,75 @@
+package jira
+
+import (
+	"encoding/json"
+	"maps"
+	"testing"
+)
+
+func TestActiveSprintName(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		raw  string
+		want string
+	}{
+		{"active sprint", `[{"id":5,"name":"Sprint 5","state":"active"}]`, "Sprint 5"},
+		{"closed only", `[{"id":3,"name":"Sprint 3","state":"closed"},{"id":4,"name":"Sprint 4","state":"closed"}]`, ""},
+		{"closed then active", `[{"id":4,"name":"Sprint 4","state":"closed"},{"id":5,"name":" Sprint 5 ","state":"ACTIVE"}]`, "Sprint 5"},
+		{"active then future", `[{"id":5,"name":"Sprint 5","state":"active"},{"id":6,"name":"Sprint 6","state":"future"}]`, "Sprint 5"},
+		{"empty", `[]`, ""},
+		{"null", `null`, ""},
+		{"legacy blobs", `["com.atlassian.greenhopper.service.sprint.Sprint@1a[id=4,rapidViewId=1,state=CLOSED,name=Sprint 4,startDate=2026-09-16T09:00:00.000Z]",` +
+			`"com.atlassian.greenhopper.service.sprint.Sprint@1f[id=5,rapidViewId=1,state=ACTIVE,name=Sprint 5, Platform,startDate=2026-09-30T09:00:00.000Z,goal=<null>]"]`, "Sprint 5, Platform"},
+		{"legacy single blob", `"com.atlassian.greenhopper.service.sprint.Sprint@1f[id=5,state=ACTIVE,name=Sprint 5]"`, "Sprint 5"},
+		{"legacy closed blob", `["com.atlassian.greenhopper.service.sprint.Sprint@1a[id=4,state=CLOSED,name=Sprint 4]"]`, ""},
+		{"number", `42`, ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := activeSprintName(json.RawMessage(tc.raw)); got != tc.want {
+				t.Errorf("activeSprintName(%s) = %q, want %q", tc.raw, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestStoryPoints(t *testing.T) {
+	for _, tc := range []struct {
+		raw  string
+		want *float64
+	}{
+		{`13`, ptr(13.0)},
+		{`0.5`, ptr(0.5)},
+		{`0`, ptr(0.0)},
+		{`null`, nil},
+		{`"8"`, nil},
+	} {
+		got := storyPoints(json.RawMessage(tc.raw))
+		if (got == nil) != (tc.want == nil) || got != nil && *got != *tc.want {
+			t.Errorf("storyPoints(%s) = %v, want %v", tc.raw, got, tc.want)
+		}
+	}
+}
+
+func ptr[T any](v T) *T { return &v }
+
+func TestParseSprintBlob(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		blob string
+		want map[string]string
+	}{
+		{
+			"legacy sprint",
+			"com.atlassian.greenhopper.service.sprint.Sprint@1f[id=5,rapidViewId=1,state=ACTIVE,name=Sprint 5, Platform,goal=<null>,sequence=5]",
+			map[string]string{"id": "5", "rapidviewid": "1", "state": "ACTIVE", "name": "Sprint 5, Platform", "goal": "", "sequence": "5"},
+		},
+		{"no brackets", "com.atlassian.greenhopper.service.sprint.Sprint@1f", map[string]string{}},
+		{"empty body", "Sprint@1f[]", map[string]string{}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := parseSprintBlob(tc.blob); !maps.Equal(got, tc.want) {
+				t.Errorf("parseSprintBlob(%q) = %v, want %v", tc.blob, got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package jira
+
+import (
+	"regexp"
+	"strings"
+)
+
+var orderByPattern = regexp.MustCompile(`(?i)^order\s+by\b`)
+
+// SplitOrderBy separates a JQL query into its filter expression and trailing ORDER BY
+// clause (including the keywords). Either part may be empty. Only the last ORDER BY
+// outside quoted strings counts, so it is not fooled by text such as
+// summary ~ "sort order by date".
+func SplitOrderBy(jql string) (query, orderBy string) {
+	jql = strings.TrimSpace(jql)
+	start := -1
+	var quote byte
+	for i := 0; i < len(jql); i++ {
+		c := jql[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case (i == 0 || !isWordByte(jql[i-1])) && orderByPattern.MatchString(jql[i:]):
+			start = i
+		}
+	}
+	if start < 0 {
+		return jql, ""
+	}
+	return strings.TrimSpace(jql[:start]), strings.TrimSpace(jql[start:])
+}
+
+// isWordByte reports whether c can be part of a JQL keyword or field name.
+func isWordByte(c byte) bool {
+	return c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// AndJQL narrows jql with an additional clause, keeping any ORDER BY clause at the end.
+func AndJQL(jql, clause string) string {
+	clause = strings.TrimSpace(clause)
+	if clause == "" {
+		return strings.TrimSpace(jql)
+	}
+
+	query, orderBy := SplitOrderBy(jql)
+	combined := clause
+	if query != "" {
+		combined = "(" + query + ") AND " + clause
+	}
+	if orderBy != "" {
+		combined += " " + orderBy
+	}
+	return combined
+}
+
+// OrderJQL replaces any ORDER BY clause of jql with order, which may be given with or
+// without the ORDER BY keywords (for example "updated DESC").
+func OrderJQL(jql, order string) string {
+	query, _ := SplitOrderBy(jql)
+	_, clause := SplitOrderBy(order)
+	if clause == "" {
+		clause = strings.TrimSpace(order)
+		if clause == "" {
+			return query
+		}
+		clause = "ORDER BY " + clause
+	}
+	if query == "" {
+		return clause
+	}
+	return query + " " + clause
+}
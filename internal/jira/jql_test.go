@@ -0,0 +1,34 @@
+package jira
+
+import "testing"
+
+func TestSplitOrderBy(t *testing.T) {
+	for _, tc := range []struct {
+		name                 string
+		jql                  string
+		wantQuery, wantOrder string
+	}{
+		{"no order by", "project = ABC AND status = Done", "project = ABC AND status = Done", ""},
+		{"order by", "project = ABC order by rank", "project = ABC", "order by rank"},
+		{"only order by", "ORDER BY updated DESC", "", "ORDER BY updated DESC"},
+		{"double quoted", `summary ~ "sort order by date" ORDER BY rank`, `summary ~ "sort order by date"`, "ORDER BY rank"},
+		{"single quoted", `summary ~ 'order by' ORDER BY rank`, `summary ~ 'order by'`, "ORDER BY rank"},
+		{"quoted only", `summary ~ "sort order by date"`, `summary ~ "sort order by date"`, ""},
+		{"escaped quote", `summary ~ "say \"hi\" order by" ORDER BY key`, `summary ~ "say \"hi\" order by"`, "ORDER BY key"},
+		{"field name", `reorder by = 1`, `reorder by = 1`, ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			query, order := SplitOrderBy(tc.jql)
+			if query != tc.wantQuery || order != tc.wantOrder {
+				t.Errorf("SplitOrderBy(%q) = %q, %q, want %q, %q", tc.jql, query, order, tc.wantQuery, tc.wantOrder)
+			}
+		})
+	}
+}
+
+func TestAndJQLKeepsQuotedOrderBy(t *testing.T) {
+	got := AndJQL(`summary ~ "sort order by date" ORDER BY rank`, "status = Done")
+	if want := `(summary ~ "sort order by date") AND status = Done ORDER BY rank`; got != want {
+		t.Errorf("AndJQL = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,117 @@
+package jira
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// DefaultOAuthTokenURL is Atlassian's OAuth 2.0 token endpoint.
+const DefaultOAuthTokenURL = "https://auth.atlassian.com/oauth/token"
+
+// OAuthCredentials holds the tokens and app credentials for OAuth 2.0 (3LO) access.
+type OAuthCredentials struct {
+	AccessToken  string
+	RefreshToken string
+	ClientID     string
+	ClientSecret string
+	// TokenURL overrides DefaultOAuthTokenURL.
+	TokenURL string
+}
+
+// oauthSession tracks the current access token and refreshes it on demand.
+type oauthSession struct {
+	mu    sync.Mutex
+	creds OAuthCredentials
+}
+
+func newOAuthSession(creds OAuthCredentials) (*oauthSession, error) {
+	creds.AccessToken = strings.TrimSpace(creds.AccessToken)
+	creds.RefreshToken = strings.TrimSpace(creds.RefreshToken)
+	if creds.AccessToken == "" && creds.RefreshToken == "" {
+		return nil, errors.New("oauth access token or refresh token is required")
+	}
+	if creds.RefreshToken != "" && (creds.ClientID == "" || creds.ClientSecret == "") {
+		return nil, errors.New("oauth client id and client secret are required to refresh tokens")
+	}
+	if strings.TrimSpace(creds.TokenURL) == "" {
+		creds.TokenURL = DefaultOAuthTokenURL
+	}
+	return &oauthSession{creds: creds}, nil
+}
+
+func (s *oauthSession) accessToken() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.creds.AccessToken
+}
+
+func (s *oauthSession) canRefresh() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.creds.RefreshToken != ""
+}
+
+// refresh exchanges the refresh token for a new access token. If another request already
+// replaced the stale token, the current one is returned without calling the endpoint.
+func (s *oauthSession) refresh(ctx context.Context, httpClient *http.Client, userAgent, stale string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.creds.AccessToken != "" && s.creds.AccessToken != stale {
+		return s.creds.AccessToken, nil
+	}
+
+	body, err := json.Marshal(map[string]string{
+		"grant_type":    "refresh_token",
+		"client_id":     s.creds.ClientID,
+		"client_secret": s.creds.ClientSecret,
+		"refresh_token": s.creds.RefreshToken,
+	})
+	if err != nil {
+		return "", fmt.Errorf("encode token request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.creds.TokenURL, bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("create token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("token request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return "", fmt.Errorf("token request failed: %s: %s", resp.Status, strings.TrimSpace(string(respBody)))
+	}
+
+	var payload struct {
+		AccessToken  string `json:"access_token"`
+		RefreshToken string `json:"refresh_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return "", fmt.Errorf("decode token response: %w", err)
+	}
+	if strings.TrimSpace(payload.AccessToken) == "" {
+		return "", errors.New("token response did not include an access token")
+	}
+
+	s.creds.AccessToken = strings.TrimSpace(payload.AccessToken)
+	// Atlassian rotates refresh tokens; keep the newest one for later refreshes.
+	if rotated := strings.TrimSpace(payload.RefreshToken); rotated != "" {
+		s.creds.RefreshToken = rotated
+	}
+	return s.creds.AccessToken, nil
+}
@@ -0,0 +1,115 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// oauthServer is a Jira instance that accepts only validToken, with a token endpoint
+// that hands it out for the expected refresh token unless refreshFails is set.
+type oauthServer struct {
+	*httptest.Server
+	validToken   string
+	refreshFails bool
+
+	mu        sync.Mutex
+	auths     []string
+	refreshes int
+}
+
+func newOAuthServer(t *testing.T, refreshFails bool) *oauthServer {
+	t.Helper()
+	s := &oauthServer{validToken: "fresh-token", refreshFails: refreshFails}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/oauth/token", func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		json.NewDecoder(r.Body).Decode(&body)
+		s.mu.Lock()
+		s.refreshes++
+		s.mu.Unlock()
+		if s.refreshFails || body["grant_type"] != "refresh_token" || body["refresh_token"] != "refresh-1" {
+			http.Error(w, `{"error":"invalid_grant"}`, http.StatusForbidden)
+			return
+		}
+		writeJSON(t, w, map[string]string{"access_token": s.validToken, "refresh_token": "refresh-2"})
+	})
+	mux.HandleFunc("/rest/api/3/myself", func(w http.ResponseWriter, r *http.Request) {
+		auth := r.Header.Get("Authorization")
+		s.mu.Lock()
+		s.auths = append(s.auths, auth)
+		s.mu.Unlock()
+		if auth != "Bearer "+s.validToken {
+			http.Error(w, `{"message":"token expired"}`, http.StatusUnauthorized)
+			return
+		}
+		writeJSON(t, w, map[string]string{"accountId": "abc", "displayName": "Pat Lee"})
+	})
+	s.Server = httptest.NewServer(mux)
+	t.Cleanup(s.Close)
+	return s
+}
+
+func newTestOAuthClient(t *testing.T, srv *oauthServer) *Client {
+	t.Helper()
+	client, err := NewOAuthClient(srv.URL, OAuthCredentials{
+		AccessToken:  "stale-token",
+		RefreshToken: "refresh-1",
+		ClientID:     "client",
+		ClientSecret: "secret",
+		TokenURL:     srv.URL + "/oauth/token",
+	})
+	if err != nil {
+		t.Fatalf("NewOAuthClient: %v", err)
+	}
+	return client
+}
+
+func TestOAuthRefreshesAndRetriesOn401(t *testing.T) {
+	srv := newOAuthServer(t, false)
+	client := newTestOAuthClient(t, srv)
+
+	user, err := client.CurrentUser(context.Background())
+	if err != nil {
+		t.Fatalf("CurrentUser: %v", err)
+	}
+	if user.DisplayName != "Pat Lee" {
+		t.Errorf("user = %+v, want Pat Lee", user)
+	}
+	if want := []string{"Bearer stale-token", "Bearer fresh-token"}; strings.Join(srv.auths, ",") != strings.Join(want, ",") {
+		t.Errorf("Authorization headers = %q, want %q", srv.auths, want)
+	}
+
+	// The refreshed token is kept, so later requests need no refresh.
+	if _, err := client.CurrentUser(context.Background()); err != nil {
+		t.Fatalf("second CurrentUser: %v", err)
+	}
+	if srv.refreshes != 1 || len(srv.auths) != 3 || srv.auths[2] != "Bearer fresh-token" {
+		t.Errorf("after a second request: %d refreshes, headers %q; want 1 refresh and the fresh token", srv.refreshes, srv.auths)
+	}
+}
+
+func TestOAuthRefreshFailureSurfacesThe401(t *testing.T) {
+	srv := newOAuthServer(t, true)
+	client := newTestOAuthClient(t, srv)
+
+	_, err := client.CurrentUser(context.Background())
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("CurrentUser error = %v, want the original 401", err)
+	}
+	if !strings.Contains(apiErr.Body, "token expired") {
+		t.Errorf("401 body = %q, want Jira's message", apiErr.Body)
+	}
+	if !strings.Contains(err.Error(), "refresh oauth token: token request failed: 403") {
+		t.Errorf("CurrentUser error = %v, want the refresh failure too", err)
+	}
+	if srv.refreshes != 1 || len(srv.auths) != 1 {
+		t.Errorf("%d refreshes and %d requests, want one of each", srv.refreshes, len(srv.auths))
+	}
+}
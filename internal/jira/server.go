@@ -0,0 +1,68 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ServerInfo summarizes the Jira instance answering at the client's base URL.
+type ServerInfo struct {
+	BaseURL        string `json:"baseUrl"`
+	Version        string `json:"version"`
+	DeploymentType string `json:"deploymentType"`
+	ServerTitle    string `json:"serverTitle"`
+}
+
+// ServerInfo fetches /rest/api/3/serverInfo, which confirms the instance is reachable.
+func (c *Client) ServerInfo(ctx context.Context) (ServerInfo, error) {
+	var info ServerInfo
+	if err := c.getJSON(ctx, "serverInfo", "/rest/api/3/serverInfo", &info); err != nil {
+		return ServerInfo{}, err
+	}
+	return info, nil
+}
+
+// User identifies a Jira account.
+type User struct {
+	AccountID   string `json:"accountId"`
+	DisplayName string `json:"displayName"`
+	// Email is empty when the account's profile visibility hides it.
+	Email string `json:"emailAddress"`
+}
+
+// CurrentUser returns the account the client authenticates as, from /rest/api/3/myself.
+func (c *Client) CurrentUser(ctx context.Context) (User, error) {
+	var user User
+	if err := c.getJSON(ctx, "myself", "/rest/api/3/myself", &user); err != nil {
+		return User{}, err
+	}
+	user.AccountID = strings.TrimSpace(user.AccountID)
+	user.DisplayName = strings.TrimSpace(user.DisplayName)
+	user.Email = strings.TrimSpace(user.Email)
+	return user, nil
+}
+
+// getJSON issues a GET for path and decodes the JSON response into out.
+func (c *Client) getJSON(ctx context.Context, op, path string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+path, http.NoBody)
+	if err != nil {
+		return fmt.Errorf("create %s request: %w", op, err)
+	}
+
+	resp, err := c.do(req)
+	if err != nil {
+		return fmt.Errorf("%s request: %w", op, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return newAPIError(op, resp)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decode %s: %w", op, err)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package jira
+
+import (
+	"io"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// Stats summarizes the HTTP traffic a Client has generated.
+type Stats struct {
+	// Requests counts every request sent, including OAuth refreshes and retries.
+	Requests int64
+	// Duration is the cumulative time spent waiting for response headers.
+	Duration time.Duration
+	// Bytes counts the response body bytes read so far.
+	Bytes int64
+}
+
+type clientStats struct {
+	requests atomic.Int64
+	nanos    atomic.Int64
+	bytes    atomic.Int64
+}
+
+// Stats returns the number of requests made so far, the time spent on them, and the
+// response bytes read.
+func (c *Client) Stats() Stats {
+	return Stats{
+		Requests: c.stats.requests.Load(),
+		Duration: time.Duration(c.stats.nanos.Load()),
+		Bytes:    c.stats.bytes.Load(),
+	}
+}
+
+// statsTransport counts and times the requests passing through it.
+type statsTransport struct {
+	base  http.RoundTripper
+	stats *clientStats
+}
+
+func (t *statsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	t.stats.requests.Add(1)
+	t.stats.nanos.Add(int64(time.Since(start)))
+	if resp != nil && resp.Body != nil {
+		resp.Body = &countingBody{ReadCloser: resp.Body, bytes: &t.stats.bytes}
+	}
+	return resp, err
+}
+
+// countingBody adds the bytes read from a response body to a Stats total.
+type countingBody struct {
+	io.ReadCloser
+	bytes *atomic.Int64
+}
+
+func (b *countingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.bytes.Add(int64(n))
+	return n, err
+}
@@ -0,0 +1,91 @@
+package jira
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// maxKeysPerLookup bounds the keys in one IssueSummaries search, keeping the JQL and
+// the request URL well within Jira's limits.
+const maxKeysPerLookup = 50
+
+// IssueSummaries returns the summaries of the issues with the given keys, looked up with
+// one "key in (...)" search per batch of keys. Duplicate keys are requested once, and
+// keys this client has looked up before are answered from its cache. Keys Jira does not
+// return, such as issues the user cannot see, are left out of the result.
+func (c *Client) IssueSummaries(ctx context.Context, keys []string) (map[string]string, error) {
+	summaries := make(map[string]string, len(keys))
+	var missing []string
+	requested := make(map[string]bool, len(keys))
+
+	c.summaryMu.Lock()
+	for _, key := range keys {
+		key = strings.TrimSpace(key)
+		if key == "" || requested[key] {
+			continue
+		}
+		requested[key] = true
+		if summary, ok := c.summaryCache[key]; ok {
+			if summary != "" {
+				summaries[key] = summary
+			}
+			continue
+		}
+		missing = append(missing, key)
+	}
+	c.summaryMu.Unlock()
+
+	for start := 0; start < len(missing); start += maxKeysPerLookup {
+		batch := missing[start:min(start+maxKeysPerLookup, len(missing))]
+		found, err := c.searchSummaries(ctx, batch)
+		if err != nil {
+			return summaries, err
+		}
+
+		c.summaryMu.Lock()
+		for _, key := range batch {
+			// Unfound keys are cached as "" so they are not requested again.
+			c.summaryCache[key] = found[key]
+			if found[key] != "" {
+				summaries[key] = found[key]
+			}
+		}
+		c.summaryMu.Unlock()
+	}
+	return summaries, nil
+}
+
+// searchSummaries runs one search for the summaries of keys.
+func (c *Client) searchSummaries(ctx context.Context, keys []string) (map[string]string, error) {
+	q := url.Values{}
+	q.Set("jql", "key in ("+strings.Join(keys, ", ")+")")
+	q.Set("fields", "summary")
+	q.Set("maxResults", strconv.Itoa(len(keys)))
+	path := legacySearchPath
+	if c.enhancedSearch {
+		path = enhancedSearchPath
+	} else {
+		// Keys that no longer exist are reported as warnings instead of failing the search.
+		q.Set("validateQuery", "warn")
+	}
+
+	var payload struct {
+		Issues []struct {
+			Key    string `json:"key"`
+			Fields struct {
+				Summary string `json:"summary"`
+			} `json:"fields"`
+		} `json:"issues"`
+	}
+	if err := c.getJSON(ctx, "summary search", path+"?"+q.Encode(), &payload); err != nil {
+		return nil, err
+	}
+
+	found := make(map[string]string, len(payload.Issues))
+	for _, issue := range payload.Issues {
+		found[strings.TrimSpace(issue.Key)] = strings.TrimSpace(issue.Fields.Summary)
+	}
+	return found, nil
+}
@@ -0,0 +1,95 @@
+package jira
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// summaryServer answers "key in (...)" searches with the summary "Summary of KEY" for
+// every key but ABC-404, counting how often each key is looked up.
+func summaryServer(t *testing.T) (*httptest.Server, func() (lookups map[string]int, searches int)) {
+	t.Helper()
+	var mu sync.Mutex
+	lookups := make(map[string]int)
+	searches := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		jql := r.URL.Query().Get("jql")
+		list, ok := strings.CutPrefix(jql, "key in (")
+		if r.URL.Path != legacySearchPath || !ok {
+			http.Error(w, "unexpected request "+r.URL.String(), http.StatusBadRequest)
+			return
+		}
+		var issues []map[string]any
+		mu.Lock()
+		searches++
+		for _, key := range strings.Split(strings.TrimSuffix(list, ")"), ", ") {
+			lookups[key]++
+			if key != "ABC-404" {
+				issues = append(issues, map[string]any{"key": key, "fields": map[string]string{"summary": "Summary of " + key}})
+			}
+		}
+		mu.Unlock()
+		writeJSON(t, w, map[string]any{"issues": issues})
+	}))
+	t.Cleanup(srv.Close)
+	return srv, func() (map[string]int, int) {
+		mu.Lock()
+		defer mu.Unlock()
+		return maps.Clone(lookups), searches
+	}
+}
+
+func TestIssueSummariesFetchesEachParentOnce(t *testing.T) {
+	srv, seen := summaryServer(t)
+	client := newTestClient(t, srv)
+
+	got, err := client.IssueSummaries(context.Background(), []string{"ABC-1", "ABC-2", "ABC-1", " ABC-2 ", "", "ABC-404"})
+	if err != nil {
+		t.Fatalf("IssueSummaries: %v", err)
+	}
+	want := map[string]string{"ABC-1": "Summary of ABC-1", "ABC-2": "Summary of ABC-2"}
+	if !maps.Equal(got, want) {
+		t.Errorf("IssueSummaries = %v, want %v", got, want)
+	}
+
+	// A second lookup in the same run is answered from the cache, unfound keys included.
+	got, err = client.IssueSummaries(context.Background(), []string{"ABC-2", "ABC-404", "ABC-3"})
+	if err != nil {
+		t.Fatalf("second IssueSummaries: %v", err)
+	}
+	if want := map[string]string{"ABC-2": "Summary of ABC-2", "ABC-3": "Summary of ABC-3"}; !maps.Equal(got, want) {
+		t.Errorf("second IssueSummaries = %v, want %v", got, want)
+	}
+
+	lookups, searches := seen()
+	if want := map[string]int{"ABC-1": 1, "ABC-2": 1, "ABC-404": 1, "ABC-3": 1}; !maps.Equal(lookups, want) {
+		t.Errorf("lookups = %v, want each key once", lookups)
+	}
+	if searches != 2 {
+		t.Errorf("searches = %d, want one per call", searches)
+	}
+}
+
+func TestIssueSummariesBatches(t *testing.T) {
+	srv, seen := summaryServer(t)
+	keys := make([]string, maxKeysPerLookup+1)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("ABC-%d", i+1)
+	}
+	got, err := newTestClient(t, srv).IssueSummaries(context.Background(), keys)
+	if err != nil {
+		t.Fatalf("IssueSummaries: %v", err)
+	}
+	if len(got) != len(keys) {
+		t.Errorf("IssueSummaries found %d summaries, want %d", len(got), len(keys))
+	}
+	if _, searches := seen(); searches != 2 {
+		t.Errorf("searches = %d, want 2 batches", searches)
+	}
+}
@@ -0,0 +1,74 @@
+package jira
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Connection pool defaults. Go's own transport keeps only 2 idle connections per host,
+// so a search followed by one request per issue would keep reconnecting when requests
+// overlap.
+const (
+	DefaultMaxIdleConnsPerHost = 16
+	DefaultMaxConnsPerHost     = 32
+	DefaultIdleConnTimeout     = 90 * time.Second
+)
+
+// ConnPool tunes a Client's HTTP connection pool. Zero fields use the defaults above.
+type ConnPool struct {
+	MaxIdleConnsPerHost int
+	MaxConnsPerHost     int
+	IdleConnTimeout     time.Duration
+}
+
+// WithConnPool replaces the connection pool defaults with pool's non-zero settings.
+func WithConnPool(pool ConnPool) Option {
+	return func(c *Client) {
+		if pool.MaxIdleConnsPerHost > 0 {
+			c.pool.MaxIdleConnsPerHost = pool.MaxIdleConnsPerHost
+		}
+		if pool.MaxConnsPerHost > 0 {
+			c.pool.MaxConnsPerHost = pool.MaxConnsPerHost
+		}
+		if pool.IdleConnTimeout > 0 {
+			c.pool.IdleConnTimeout = pool.IdleConnTimeout
+		}
+	}
+}
+
+// newTransport returns a copy of http.DefaultTransport, keeping its proxy, dial, and
+// TLS settings, with the connection pool configured by pool.
+func newTransport(pool ConnPool) *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConnsPerHost = pool.MaxIdleConnsPerHost
+	transport.MaxConnsPerHost = pool.MaxConnsPerHost
+	transport.IdleConnTimeout = pool.IdleConnTimeout
+	return transport
+}
+
+// maxRedirects is how many redirects a request follows, the same limit as Go's default.
+const maxRedirects = 10
+
+// keepAuthOnRedirect is the client's CheckRedirect, which decides whether the
+// Authorization header follows a redirect, such as a filter's searchUrl moving from
+// http to https or to a new context path. Redirects to the same host name keep it,
+// whatever their port, unless they downgrade an https request to plain http, where it
+// would be sent in the clear. Redirects to other hosts never carry it.
+func keepAuthOnRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= maxRedirects {
+		return fmt.Errorf("stopped after %d redirects", maxRedirects)
+	}
+	first := via[0]
+	auth := first.Header.Get("Authorization")
+	switch {
+	case auth == "":
+	case req.URL.Hostname() != first.URL.Hostname():
+		req.Header.Del("Authorization")
+	case first.URL.Scheme == "https" && req.URL.Scheme != "https":
+		req.Header.Del("Authorization")
+	default:
+		req.Header.Set("Authorization", auth)
+	}
+	return nil
+}
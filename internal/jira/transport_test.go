@@ -0,0 +1,139 @@
+package jira
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestKeepAuthOnRedirect(t *testing.T) {
+	request := func(rawURL, auth string) *http.Request {
+		req := httptest.NewRequest(http.MethodGet, rawURL, nil)
+		if auth != "" {
+			req.Header.Set("Authorization", auth)
+		}
+		return req
+	}
+	for _, tc := range []struct {
+		name string
+		from string
+		to   string
+		auth string
+		want string
+	}{
+		{"same host", "https://jira.example.com/rest/api/3/search", "https://jira.example.com/jira/rest/api/3/search", "Basic abc", "Basic abc"},
+		{"same host, other port", "https://jira.example.com/a", "https://jira.example.com:8443/a", "Basic abc", "Basic abc"},
+		{"http to https", "http://jira.example.com/a", "https://jira.example.com/a", "Basic abc", "Basic abc"},
+		{"https to http", "https://jira.example.com/a", "http://jira.example.com/a", "Basic abc", ""},
+		{"other host", "https://jira.example.com/a", "https://evil.example.net/a", "Bearer xyz", ""},
+		{"subdomain", "https://example.com/a", "https://jira.example.com/a", "Basic abc", ""},
+		{"no credentials", "https://jira.example.com/a", "https://jira.example.com/b", "", ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			// Go may or may not have copied the header onto the redirect already.
+			for _, copied := range []string{"", tc.auth} {
+				req := request(tc.to, copied)
+				if err := keepAuthOnRedirect(req, []*http.Request{request(tc.from, tc.auth)}); err != nil {
+					t.Fatalf("keepAuthOnRedirect: %v", err)
+				}
+				if got := req.Header.Get("Authorization"); got != tc.want {
+					t.Errorf("Authorization (copied %q) = %q, want %q", copied, got, tc.want)
+				}
+			}
+		})
+	}
+
+	via := make([]*http.Request, maxRedirects)
+	for i := range via {
+		via[i] = request("https://jira.example.com/a", "Basic abc")
+	}
+	err := keepAuthOnRedirect(request("https://jira.example.com/b", ""), via)
+	if want := "stopped after 10 redirects"; err == nil || err.Error() != want {
+		t.Errorf("keepAuthOnRedirect after %d redirects = %v, want %q", maxRedirects, err, want)
+	}
+}
+
+func TestSearchFollowsRedirects(t *testing.T) {
+	var mu sync.Mutex
+	auths := make(map[string]string)
+	record := func(name string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			auths[name] = r.Header.Get("Authorization")
+			mu.Unlock()
+			writeJSON(t, w, map[string]any{"issues": []any{}, "isLast": true})
+		}
+	}
+	sameHost := httptest.NewServer(record("same host"))
+	defer sameHost.Close()
+	otherHost := httptest.NewServer(record("other host"))
+	defer otherHost.Close()
+	// httptest listens on 127.0.0.1; localhost names the same machine but another host.
+	otherURL, err := url.Parse(otherHost.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherURL.Host = strings.Replace(otherURL.Host, "127.0.0.1", "localhost", 1)
+
+	origin := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		target := sameHost.URL
+		if r.URL.Query().Get("jql") == "other" {
+			target = otherURL.String()
+		}
+		http.Redirect(w, r, target+r.URL.RequestURI(), http.StatusFound)
+	}))
+	defer origin.Close()
+
+	client := newTestClient(t, origin)
+	for _, jql := range []string{"same", "other"} {
+		if _, err := client.SearchByJQL(context.Background(), jql); err != nil {
+			t.Fatalf("SearchByJQL(%q): %v", jql, err)
+		}
+	}
+	if !strings.HasPrefix(auths["same host"], "Basic ") {
+		t.Errorf("redirect to the same host sent Authorization %q, want the credentials", auths["same host"])
+	}
+	if got, ok := auths["other host"]; !ok || got != "" {
+		t.Errorf("redirect to another host sent Authorization %q (reached: %v), want none", got, ok)
+	}
+}
+
+func TestNewTransportConnPool(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		opts []Option
+		want ConnPool
+	}{
+		{"defaults", nil, ConnPool{DefaultMaxIdleConnsPerHost, DefaultMaxConnsPerHost, DefaultIdleConnTimeout}},
+		{"configured", []Option{WithConnPool(ConnPool{MaxIdleConnsPerHost: 4, MaxConnsPerHost: 8, IdleConnTimeout: 30 * time.Second})}, ConnPool{4, 8, 30 * time.Second}},
+		{"partly configured", []Option{WithConnPool(ConnPool{MaxConnsPerHost: 64})}, ConnPool{DefaultMaxIdleConnsPerHost, 64, DefaultIdleConnTimeout}},
+		{"negative values", []Option{WithConnPool(ConnPool{MaxIdleConnsPerHost: -1, IdleConnTimeout: -time.Second})}, ConnPool{DefaultMaxIdleConnsPerHost, DefaultMaxConnsPerHost, DefaultIdleConnTimeout}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			client, err := NewClient("https://jira.example.com", "user@example.com", "token", tc.opts...)
+			if err != nil {
+				t.Fatalf("NewClient: %v", err)
+			}
+			stats, ok := client.httpClient.Transport.(*statsTransport)
+			if !ok {
+				t.Fatalf("client transport is %T, want *statsTransport", client.httpClient.Transport)
+			}
+			transport, ok := stats.base.(*http.Transport)
+			if !ok {
+				t.Fatalf("base transport is %T, want *http.Transport", stats.base)
+			}
+			got := ConnPool{transport.MaxIdleConnsPerHost, transport.MaxConnsPerHost, transport.IdleConnTimeout}
+			if got != tc.want {
+				t.Errorf("connection pool = %+v, want %+v", got, tc.want)
+			}
+			if transport.Proxy == nil {
+				t.Error("transport dropped the default proxy settings")
+			}
+		})
+	}
+}